@@ -3,13 +3,16 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"resumeparser/internal/extractor"
 	"resumeparser/internal/models"
 	"resumeparser/internal/parser"
+	"sort"
 	"strings"
 	"time"
 )
@@ -19,23 +22,30 @@ func main() {
 	debug := flag.Bool("debug", false, "Enable debug output")
 	outputFormat := flag.String("format", "json", "Output format (json or text)")
 	timeout := flag.Duration("timeout", 30*time.Second, "Processing timeout")
+	backend := flag.String("backend", "native", "PDF extraction backend (native or pdfbox)")
+	password := flag.String("password", "", "Password for encrypted PDFs")
+	ocr := flag.String("ocr", "", "OCR for scanned PDFs: \"tesseract\" or a command reading the image path {image}")
+	normalize := flag.String("normalize", "all", "Text normalization: all, none or a comma-separated list of unicode, ligatures, invisible, hyphenation and punctuation")
+	sectionsPath := flag.String("sections", "", "JSON file of section definitions, added to the built-in ones")
 	flag.Parse()
 
 	// Validate arguments
 	if flag.NArg() < 1 {
-		fmt.Fprintf(os.Stderr, "Error: Please provide a PDF file path\n")
-		fmt.Fprintf(os.Stderr, "Usage: %s [options] <pdf-file>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Error: Please provide a resume file path\n")
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] <resume-file | ->\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\nOptions:\n")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
-	pdfPath := flag.Arg(0)
+	filePath := flag.Arg(0)
 
-	// Validate file
-	if err := validateFile(pdfPath); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	// Validate file; "-" reads the resume from stdin
+	if filePath != "-" {
+		if err := validateFile(filePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	// Create context with timeout
@@ -44,38 +54,111 @@ func main() {
 
 	// Initialize extractor
 	if *debug {
-		fmt.Fprintf(os.Stderr, "Initializing PDF extractor...\n")
+		fmt.Fprintf(os.Stderr, "Initializing extractor...\n")
 	}
-	ext := extractor.New()
-
-	// Extract text
-	if *debug {
-		fmt.Fprintf(os.Stderr, "Processing PDF: %s\n", pdfPath)
+	// The format is detected from the file content, not its extension
+	opts := []extractor.Option{extractor.WithPassword(*password)}
+	switch {
+	case *ocr == "tesseract":
+		opts = append(opts, extractor.WithOCR(extractor.NewTesseract("")))
+	case *ocr != "":
+		opts = append(opts, extractor.WithOCR(&extractor.CommandOCR{Args: strings.Fields(*ocr)}))
 	}
-	text, err := ext.Extract(ctx, pdfPath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error extracting text: %v\n", err)
+	ext := extractor.NewDefaultRegistry(opts...)
+	switch strings.ToLower(*backend) {
+	case "native":
+	case "pdfbox":
+		ext.Register(extractor.TypePDF, extractor.New(opts...))
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Unknown extraction backend %q\n", *backend)
 		os.Exit(1)
 	}
+	// Markdown without headings or links reads as plain text, so trust
+	// the extension for text content
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".md", ".markdown":
+		ext.RegisterSniffer(func(data []byte) extractor.ContentType {
+			if extractor.Detect(data) == extractor.TypeText {
+				return extractor.TypeMarkdown
+			}
+			return extractor.TypeUnknown
+		})
+	}
 
+	// Read the input
 	if *debug {
-		fmt.Fprintf(os.Stderr, "Extracted %d characters of text\n", len(text))
-		if len(text) > 100 {
-			fmt.Fprintf(os.Stderr, "First 100 chars: %q\n", text[:100])
-		}
+		fmt.Fprintf(os.Stderr, "Processing file: %s\n", filePath)
+	}
+	var data []byte
+	var err error
+	if filePath == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filePath)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
 	}
 
 	// Create and configure parser
 	if *debug {
 		fmt.Fprintf(os.Stderr, "Initializing parser...\n")
 	}
-	p := parser.NewParser()
+	normalization, err := parser.ParseNormalization(*normalize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	parserOpts := []parser.Option{parser.WithNormalization(normalization)}
+	if *sectionsPath != "" {
+		sections, err := parser.LoadSectionConfig(*sectionsPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		parserOpts = append(parserOpts, parser.WithSections(parser.DefaultSectionConfig().Merge(sections)))
+	}
+	p := parser.NewParser(parserOpts...)
 
-	// Parse the resume
-	if *debug {
-		fmt.Fprintf(os.Stderr, "Parsing resume content...\n")
+	var resume *models.Resume
+	if t := ext.Detect(data); t == extractor.TypeLaTeX {
+		// LaTeX source is read from its commands rather than extracted text
+		if *debug {
+			fmt.Fprintf(os.Stderr, "Parsing LaTeX source...\n")
+		}
+		resume, err = p.ParseLaTeX(extractor.DecodeText(data))
+		if err == nil {
+			resume.Metadata[models.MetaFormat] = string(t)
+		}
+	} else {
+		// Extract text
+		var doc *models.Document
+		doc, err = ext.ExtractLayoutBytes(ctx, data)
+		if err != nil {
+			var encrypted *models.EncryptedError
+			if errors.As(err, &encrypted) && encrypted.PasswordRequired {
+				fmt.Fprintf(os.Stderr, "Error: %v (use -password)\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Error extracting text: %v\n", err)
+			os.Exit(1)
+		}
+
+		if *debug {
+			text := doc.Text()
+			fmt.Fprintf(os.Stderr, "Extracted %d characters of text\n", len(text))
+			if len(text) > 100 {
+				fmt.Fprintf(os.Stderr, "First 100 chars: %q\n", text[:100])
+			}
+		}
+
+		// Parse the resume
+		if *debug {
+			fmt.Fprintf(os.Stderr, "Parsing resume content...\n")
+		}
+		resume, err = p.ParseDocument(doc)
 	}
-	resume, err := p.Parse(text)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing resume: %v\n", err)
 		os.Exit(1)
@@ -108,12 +191,6 @@ func validateFile(path string) error {
 		return fmt.Errorf("not a regular file: %s", path)
 	}
 
-	// Check extension
-	ext := strings.ToLower(filepath.Ext(path))
-	if ext != ".pdf" {
-		return fmt.Errorf("unsupported file type %q (only PDF files are supported)", ext)
-	}
-
 	return nil
 }
 
@@ -127,163 +204,133 @@ func outputJSON(resume *models.Resume) {
 }
 
 func outputText(resume *models.Resume) {
-	// Print contact information
-	if contact, ok := resume.Sections["contact"]; ok {
-		if contactContent, ok := contact.Content.(*models.ContactContent); ok {
-			fmt.Println("Contact Information:")
-			if contactContent.Name != "" {
-				fmt.Printf("  Name: %s\n", contactContent.Name)
-			}
-			if contactContent.Location != "" {
-				fmt.Printf("  Location: %s\n", contactContent.Location)
-			}
-			if len(contactContent.Email) > 0 {
-				fmt.Printf("  Email: %s\n", strings.Join(contactContent.Email, ", "))
-			}
-			if len(contactContent.Number) > 0 {
-				fmt.Printf("  Phone: %s\n", strings.Join(contactContent.Number, ", "))
+	for _, section := range resume.Sections {
+		label := section.Heading
+		if label == "" {
+			label = strings.Title(section.Name)
+		}
+		switch content := section.Content.(type) {
+		case *models.ContactContent:
+			if section.Heading == "" {
+				label = "Contact Information"
 			}
-			for platform, url := range contactContent.Social {
-				fmt.Printf("  %s: %s\n", strings.Title(platform), url)
+			outputContact(label, content)
+		case *models.TimelineContent:
+			if section.Name == "education" {
+				outputEducation(label, content)
+			} else {
+				outputTimeline(label, content)
 			}
-			fmt.Println()
+		case *models.ListContent:
+			outputList(label, content)
+		case *models.FreeformContent:
+			outputFreeform(label, content)
 		}
 	}
+}
 
-	// Print education
-	if education, ok := resume.Sections["education"]; ok {
-		if educationContent, ok := education.Content.(*models.TimelineContent); ok {
-			fmt.Println("Education:")
-			for _, entry := range educationContent.Entries {
-				// Print organization with location
-				fmt.Printf("  • %s", entry.Organization)
-				if entry.Location != "" {
-					fmt.Printf(", %s", entry.Location)
-				}
-				fmt.Println()
-
-				// Print duration and title/degree with dashes and more indent
-				if entry.StartDate != "" || entry.EndDate != "" {
-					fmt.Printf("          - %s – %s\n", entry.StartDate, entry.EndDate)
-				}
-				if entry.Title != "" {
-					fmt.Printf("          - %s\n", entry.Title)
-				}
+func outputContact(label string, contact *models.ContactContent) {
+	fmt.Printf("%s:\n", label)
+	if contact.Name != "" {
+		fmt.Printf("  Name: %s\n", contact.Name)
+	}
+	if contact.Location != "" {
+		fmt.Printf("  Location: %s\n", contact.Location)
+	}
+	if len(contact.Email) > 0 {
+		fmt.Printf("  Email: %s\n", strings.Join(contact.Email, ", "))
+	}
+	if len(contact.Number) > 0 {
+		fmt.Printf("  Phone: %s\n", strings.Join(contact.Number, ", "))
+	}
+	platforms := make([]string, 0, len(contact.Social))
+	for platform := range contact.Social {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	for _, platform := range platforms {
+		fmt.Printf("  %s: %s\n", strings.Title(platform), contact.Social[platform])
+	}
+	fmt.Println()
+}
 
-				// Print additional details with same indent level
-				for _, detail := range entry.Details {
-					fmt.Printf("          - %s\n", detail)
-				}
-				fmt.Println()
-			}
+func outputEducation(label string, education *models.TimelineContent) {
+	fmt.Printf("%s:\n", label)
+	for _, entry := range education.Entries {
+		// Print organization with location
+		fmt.Printf("  • %s", entry.Organization)
+		if entry.Location != "" {
+			fmt.Printf(", %s", entry.Location)
 		}
-	}
+		fmt.Println()
 
-	// Print experience
-	if experience, ok := resume.Sections["experience"]; ok {
-		if experienceContent, ok := experience.Content.(*models.TimelineContent); ok {
-			fmt.Println("Experience:")
-			for _, entry := range experienceContent.Entries {
-				fmt.Printf("  %s", entry.Organization)
-				if entry.Location != "" {
-					fmt.Printf(", %s", entry.Location)
-				}
-				fmt.Println()
+		// Print duration and title/degree with dashes and more indent
+		if entry.StartDate != "" || entry.EndDate != "" {
+			fmt.Printf("          - %s – %s\n", entry.StartDate, entry.EndDate)
+		}
+		if entry.Title != "" {
+			fmt.Printf("          - %s\n", entry.Title)
+		}
 
-				if entry.Title != "" {
-					fmt.Printf("  %s\n", entry.Title)
-				}
-				if entry.StartDate != "" || entry.EndDate != "" {
-					fmt.Printf("  %s - %s\n", entry.StartDate, entry.EndDate)
-				}
-				for _, detail := range entry.Details {
-					fmt.Printf("    • %s\n", detail)
-				}
-				fmt.Println()
-			}
+		// Print additional details with same indent level
+		for _, detail := range entry.Details {
+			fmt.Printf("          - %s\n", detail)
 		}
+		fmt.Println()
 	}
+}
 
-	// Print projects
-	if projects, ok := resume.Sections["projects"]; ok {
-		if projectsContent, ok := projects.Content.(*models.TimelineContent); ok {
-			fmt.Println("Projects:")
-			for _, entry := range projectsContent.Entries {
-				fmt.Printf("  %s", entry.Organization)
-				if entry.Location != "" {
-					fmt.Printf(", %s", entry.Location)
-				}
-				fmt.Println()
-
-				if entry.Title != "" {
-					fmt.Printf("  %s\n", entry.Title)
-				}
-				if entry.StartDate != "" || entry.EndDate != "" {
-					fmt.Printf("  %s - %s\n", entry.StartDate, entry.EndDate)
-				}
-				for _, detail := range entry.Details {
-					fmt.Printf("    • %s\n", detail)
-				}
-				fmt.Println()
-			}
+func outputTimeline(label string, timeline *models.TimelineContent) {
+	fmt.Printf("%s:\n", label)
+	for _, entry := range timeline.Entries {
+		fmt.Printf("  %s", entry.Organization)
+		if entry.Location != "" {
+			fmt.Printf(", %s", entry.Location)
 		}
-	}
+		fmt.Println()
 
-	// Print skills
-	if skills, ok := resume.Sections["skills"]; ok {
-		if skillsContent, ok := skills.Content.(*models.ListContent); ok {
-			fmt.Println("Skills:")
-			for _, category := range skillsContent.Categories {
-				if category.Name != "" {
-					fmt.Printf("  %s:\n", category.Name)
-					for _, item := range category.Items {
-						fmt.Printf("    • %s\n", item)
-					}
-				} else {
-					for _, item := range category.Items {
-						fmt.Printf("  • %s\n", item)
-					}
-				}
-			}
-			fmt.Println()
+		if entry.Title != "" {
+			fmt.Printf("  %s\n", entry.Title)
+		}
+		if entry.StartDate != "" || entry.EndDate != "" {
+			fmt.Printf("  %s - %s\n", entry.StartDate, entry.EndDate)
 		}
+		for _, detail := range entry.Details {
+			fmt.Printf("    • %s\n", detail)
+		}
+		for _, link := range entry.Links {
+			fmt.Printf("    %s\n", link)
+		}
+		fmt.Println()
 	}
+}
 
-	// Print achievements
-	if achievements, ok := resume.Sections["achievements"]; ok {
-		if achievementsContent, ok := achievements.Content.(*models.ListContent); ok {
-			fmt.Println("Achievements:")
-			for _, category := range achievementsContent.Categories {
-				if category.Name != "" {
-					fmt.Printf("  %s:\n", category.Name)
-					for _, item := range category.Items {
-						fmt.Printf("    • %s\n", item)
-					}
-				} else {
-					for _, item := range category.Items {
-						fmt.Printf("  • %s\n", item)
-					}
-				}
+func outputList(label string, list *models.ListContent) {
+	fmt.Printf("%s:\n", label)
+	for _, category := range list.Categories {
+		if category.Name != "" {
+			fmt.Printf("  %s:\n", category.Name)
+			for _, item := range category.Items {
+				fmt.Printf("    • %s\n", item)
+			}
+		} else {
+			for _, item := range category.Items {
+				fmt.Printf("  • %s\n", item)
 			}
-			fmt.Println()
 		}
 	}
+	fmt.Println()
+}
 
-	// Print freeform sections
-	for name, section := range resume.Sections {
-		if section.Type == models.FreeformSection {
-			if freeformContent, ok := section.Content.(*models.FreeformContent); ok {
-				fmt.Printf("%s:\n", strings.Title(name))
-				for _, entry := range freeformContent.Entries {
-					if entry.Heading != "" {
-						fmt.Printf("  %s\n", entry.Heading)
-					}
-					for _, content := range entry.Content {
-						fmt.Printf("    %s\n", content)
-					}
-					fmt.Println()
-				}
-			}
+func outputFreeform(label string, freeform *models.FreeformContent) {
+	fmt.Printf("%s:\n", label)
+	for _, entry := range freeform.Entries {
+		if entry.Heading != "" {
+			fmt.Printf("  %s\n", entry.Heading)
+		}
+		for _, content := range entry.Content {
+			fmt.Printf("    %s\n", content)
 		}
+		fmt.Println()
 	}
 }
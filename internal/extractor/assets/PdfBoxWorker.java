@@ -0,0 +1,83 @@
+import java.io.BufferedInputStream;
+import java.io.BufferedOutputStream;
+import java.io.DataInputStream;
+import java.io.DataOutputStream;
+import java.io.EOFException;
+import java.io.IOException;
+import java.io.PrintStream;
+import java.nio.charset.StandardCharsets;
+import java.util.Arrays;
+
+import org.apache.pdfbox.Loader;
+import org.apache.pdfbox.pdmodel.PDDocument;
+import org.apache.pdfbox.text.PDFTextStripper;
+
+/**
+ * Long-lived PDFBox text extraction worker.
+ *
+ * Every message in either direction is a frame: a 4-byte big-endian length
+ * followed by that many payload bytes. Request payloads start with an
+ * operation byte: 'P' (ping) or 'X' (extract, followed by a 2-byte password
+ * length, the password and the PDF bytes). Response payloads start with a
+ * status byte, 'O' for success or 'E' for an error message.
+ *
+ * Run with the single-file source launcher:
+ *   java -cp pdfbox-app-3.0.3.jar PdfBoxWorker.java
+ */
+public class PdfBoxWorker {
+    public static void main(String[] args) throws IOException {
+        // Keep stdout for the protocol; anything else printed goes to stderr
+        PrintStream protocol = System.out;
+        System.setOut(System.err);
+
+        DataInputStream in = new DataInputStream(new BufferedInputStream(System.in));
+        DataOutputStream out = new DataOutputStream(new BufferedOutputStream(protocol));
+
+        while (true) {
+            int length;
+            try {
+                length = in.readInt();
+            } catch (EOFException e) {
+                return;
+            }
+            byte[] request = new byte[length];
+            in.readFully(request);
+
+            byte status = 'O';
+            byte[] reply;
+            try {
+                reply = handle(request);
+            } catch (Exception e) {
+                status = 'E';
+                reply = String.valueOf(e).getBytes(StandardCharsets.UTF_8);
+            }
+
+            out.writeInt(reply.length + 1);
+            out.writeByte(status);
+            out.write(reply);
+            out.flush();
+        }
+    }
+
+    private static byte[] handle(byte[] request) throws IOException {
+        if (request.length == 0) {
+            throw new IOException("empty request");
+        }
+        switch (request[0]) {
+            case 'P':
+                return "pong".getBytes(StandardCharsets.UTF_8);
+            case 'X':
+                int passwordLength = ((request[1] & 0xff) << 8) | (request[2] & 0xff);
+                String password = new String(request, 3, passwordLength, StandardCharsets.UTF_8);
+                byte[] pdf = Arrays.copyOfRange(request, 3 + passwordLength, request.length);
+                try (PDDocument document = Loader.loadPDF(pdf, password)) {
+                    PDFTextStripper stripper = new PDFTextStripper();
+                    stripper.setSortByPosition(true);
+                    stripper.setPageEnd("\f");
+                    return stripper.getText(document).getBytes(StandardCharsets.UTF_8);
+                }
+            default:
+                throw new IOException("unknown operation " + (char) request[0]);
+        }
+    }
+}
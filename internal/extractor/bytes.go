@@ -0,0 +1,76 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"resumeparser/internal/models"
+)
+
+// BytesExtractor is implemented by extractors that can read a document held
+// in memory, so uploads and archive members never touch disk
+type BytesExtractor interface {
+	ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error)
+}
+
+// ExtractLayoutBytes extracts a document from memory. Extractors that only
+// accept paths are given a temporary file.
+func ExtractLayoutBytes(ctx context.Context, e LayoutExtractor, data []byte) (*models.Document, error) {
+	if be, ok := e.(BytesExtractor); ok {
+		return be.ExtractLayoutBytes(ctx, data)
+	}
+
+	path, cleanup, err := writeTempInput(data)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return e.ExtractLayout(ctx, path)
+}
+
+// ExtractLayoutReader reads a document from r and extracts it
+func ExtractLayoutReader(ctx context.Context, e LayoutExtractor, r io.Reader) (*models.Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	return ExtractLayoutBytes(ctx, e, data)
+}
+
+// ExtractBytes is the plain text form of ExtractLayoutBytes
+func ExtractBytes(ctx context.Context, e LayoutExtractor, data []byte) (string, error) {
+	doc, err := ExtractLayoutBytes(ctx, e, data)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+// ExtractReader is the plain text form of ExtractLayoutReader
+func ExtractReader(ctx context.Context, e LayoutExtractor, r io.Reader) (string, error) {
+	doc, err := ExtractLayoutReader(ctx, e, r)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+// writeTempInput stores data in a temporary file for path-based tools
+func writeTempInput(data []byte) (string, func(), error) {
+	f, err := os.CreateTemp("", "resume-input-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp input file: %w", err)
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write temp input file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write temp input file: %w", err)
+	}
+	return f.Name(), cleanup, nil
+}
@@ -0,0 +1,181 @@
+package extractor
+
+import (
+	"math"
+	"resumeparser/internal/pdf"
+	"sort"
+	"strings"
+)
+
+// region is a block of text read as a unit: either a column of a
+// multi-column band or text spanning the full width of the page
+type region struct {
+	column int // 0 for full-width text, 1 and up for columns left to right
+	items  []pdf.Text
+}
+
+const (
+	gutterBin      = 2.0  // histogram resolution in points
+	minGutterWidth = 12.0 // narrowest gap accepted between columns
+	minColumnShare = 0.15 // share of the page's characters each column needs
+	minColumnLines = 3
+)
+
+// splitColumns finds a vertical gutter separating two columns of text and
+// returns the page's text as regions in reading order: full-width bands
+// as they occur, and within each band the left column before the right.
+func splitColumns(items []pdf.Text) []region {
+	gutterStart, _, ok := findGutter(items)
+	if !ok {
+		return []region{{items: items}}
+	}
+
+	sorted := append([]pdf.Text(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Y > sorted[j].Y
+	})
+
+	var regions []region
+	var left, right, full []pdf.Text
+	flushColumns := func() {
+		if len(left) > 0 {
+			regions = append(regions, region{column: 1, items: left})
+		}
+		if len(right) > 0 {
+			regions = append(regions, region{column: 2, items: right})
+		}
+		left, right = nil, nil
+	}
+	flushFull := func() {
+		if len(full) > 0 {
+			regions = append(regions, region{column: 0, items: full})
+		}
+		full = nil
+	}
+
+	for _, t := range sorted {
+		switch {
+		case t.X < gutterStart && t.X+t.W > gutterStart:
+			// Text running from the left column across the gutter, such as
+			// a name above both columns, spans the page
+			flushColumns()
+			full = append(full, t)
+		case t.X < gutterStart:
+			flushFull()
+			left = append(left, t)
+		default:
+			flushFull()
+			right = append(right, t)
+		}
+	}
+	flushColumns()
+	flushFull()
+	return regions
+}
+
+// findGutter looks for the widest vertical strip that almost no text
+// crosses and that has a substantial column of text on either side.
+func findGutter(items []pdf.Text) (float64, float64, bool) {
+	if len(items) < 2*minColumnLines {
+		return 0, 0, false
+	}
+
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, t := range items {
+		minX = math.Min(minX, t.X)
+		maxX = math.Max(maxX, t.X+t.W)
+	}
+	if maxX-minX < 4*minGutterWidth {
+		return 0, 0, false
+	}
+
+	bins := make([]int, int((maxX-minX)/gutterBin)+1)
+	for _, t := range items {
+		from := int((t.X - minX) / gutterBin)
+		to := int((t.X + t.W - minX) / gutterBin)
+		for b := max(from, 0); b <= to && b < len(bins); b++ {
+			bins[b]++
+		}
+	}
+
+	// A few items such as a centered name may cross the gutter
+	allowed := max(1, len(items)*3/100)
+	margin := len(bins) / 10
+
+	bestStart, bestEnd := -1, -1
+	for b := margin; b < len(bins)-margin; {
+		if bins[b] > allowed {
+			b++
+			continue
+		}
+		start := b
+		for b < len(bins)-margin && bins[b] <= allowed {
+			b++
+		}
+		if b-start > bestEnd-bestStart {
+			bestStart, bestEnd = start, b
+		}
+	}
+	if bestStart < 0 || float64(bestEnd-bestStart)*gutterBin < minGutterWidth {
+		return 0, 0, false
+	}
+
+	// The strip may start inside the widest line of the left column, which
+	// the allowance lets cross it. The gutter runs from the end of the left
+	// column's lines beside the right column to where the right column
+	// starts; lines above or below it may still cross.
+	stripStart := minX + float64(bestStart)*gutterBin
+	gutterStart := stripStart
+	gutterEnd := minX + float64(bestEnd)*gutterBin
+	top, bottom := math.Inf(-1), math.Inf(1)
+	for _, t := range items {
+		if t.X >= gutterEnd-gutterBin {
+			gutterEnd = math.Min(gutterEnd, t.X)
+			top, bottom = math.Max(top, t.Y), math.Min(bottom, t.Y)
+		}
+	}
+	for _, t := range items {
+		if t.X < stripStart && t.X+t.W < gutterEnd && t.Y <= top && t.Y >= bottom {
+			gutterStart = math.Max(gutterStart, t.X+t.W)
+		}
+	}
+	if !balancedColumns(items, gutterStart, gutterEnd) {
+		return 0, 0, false
+	}
+	return gutterStart, gutterEnd, true
+}
+
+// balancedColumns rejects gutters that only separate short fragments, such
+// as right-aligned dates, from the body text. A narrow sidebar of short
+// words, such as a list of skills, still counts as a left column unless
+// its lines are mostly dates, as beside the entries of some templates.
+func balancedColumns(items []pdf.Text, gutterStart, gutterEnd float64) bool {
+	var total, leftChars, rightChars int
+	leftLines := make(map[int]bool) // baselines, true where the line holds a digit
+	rightLines := make(map[int]bool)
+	for _, t := range items {
+		n := len(strings.TrimSpace(t.S))
+		total += n
+		baseline := int(math.Round(t.Y))
+		switch {
+		case t.X+t.W <= gutterStart:
+			leftChars += n
+			leftLines[baseline] = leftLines[baseline] || strings.ContainsAny(t.S, "0123456789")
+		case t.X >= gutterEnd:
+			rightChars += n
+			rightLines[baseline] = true
+		}
+	}
+	if total == 0 || len(leftLines) < minColumnLines || len(rightLines) < minColumnLines {
+		return false
+	}
+	dated := 0
+	for _, digits := range leftLines {
+		if digits {
+			dated++
+		}
+	}
+	sidebar := dated*2 < len(leftLines)
+	return (sidebar || float64(leftChars) >= float64(total)*minColumnShare) &&
+		float64(rightChars) >= float64(total)*minColumnShare
+}
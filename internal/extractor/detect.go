@@ -0,0 +1,176 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ContentType identifies a document format by its MIME type
+type ContentType string
+
+const (
+	TypeUnknown  ContentType = "application/octet-stream"
+	TypePDF      ContentType = "application/pdf"
+	TypeDOCX     ContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	TypeXLSX     ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	TypePPTX     ContentType = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	TypeODT      ContentType = "application/vnd.oasis.opendocument.text"
+	TypeZIP      ContentType = "application/zip"
+	TypeRTF      ContentType = "application/rtf"
+	TypeHTML     ContentType = "text/html"
+	TypeText     ContentType = "text/plain"
+	TypeMarkdown ContentType = "text/markdown"
+	TypeLaTeX    ContentType = "application/x-tex"
+)
+
+// sniffLen is how much of the file is inspected for text-based formats
+const sniffLen = 1024
+
+// Detect identifies a document format from its leading magic bytes, and
+// for ZIP containers from the names of the archive members.
+func Detect(data []byte) ContentType {
+	head := data[:min(len(data), sniffLen)]
+
+	if isPDF(head) {
+		return TypePDF
+	}
+	if bytes.HasPrefix(head, []byte("PK\x03\x04")) || bytes.HasPrefix(head, []byte("PK\x05\x06")) {
+		return detectZip(data)
+	}
+
+	text := bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	text = bytes.TrimLeft(text, " \t\r\n")
+	if bytes.HasPrefix(text, []byte(`{\rtf`)) {
+		return TypeRTF
+	}
+	if isHTML(text) {
+		return TypeHTML
+	}
+	if isText(head) || isLegacyText(head) {
+		if isLaTeX(head) {
+			return TypeLaTeX
+		}
+		if looksLikeMarkdown(head) {
+			return TypeMarkdown
+		}
+		return TypeText
+	}
+	return TypeUnknown
+}
+
+// isPDF reports whether the file starts with a PDF header. Readers accept
+// junk bytes before the header, but a header that follows words is only a
+// mention of the format in a text file.
+func isPDF(head []byte) bool {
+	i := bytes.Index(head, []byte("%PDF-"))
+	if i < 0 || i+5 >= len(head) || head[i+5] < '0' || head[i+5] > '9' {
+		return false
+	}
+	junk := head[:i]
+	if !isText(junk) && !isLegacyText(junk) {
+		return true
+	}
+	return !bytes.ContainsFunc(junk, func(r rune) bool { return r < utf8.RuneSelf && unicode.IsLetter(r) })
+}
+
+// detectZip distinguishes OOXML and ODF packages from plain archives
+func detectZip(data []byte) ContentType {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return TypeUnknown
+	}
+
+	for _, f := range zr.File {
+		// ODF packages store their MIME type in an uncompressed first entry
+		if f.Name == "mimetype" {
+			rc, err := f.Open()
+			if err != nil {
+				break
+			}
+			mime, _ := io.ReadAll(io.LimitReader(rc, 128))
+			rc.Close()
+			if strings.TrimSpace(string(mime)) == string(TypeODT) {
+				return TypeODT
+			}
+			return TypeZIP
+		}
+	}
+
+	for _, f := range zr.File {
+		switch {
+		case f.Name == "word/document.xml":
+			return TypeDOCX
+		case strings.HasPrefix(f.Name, "xl/"):
+			return TypeXLSX
+		case strings.HasPrefix(f.Name, "ppt/"):
+			return TypePPTX
+		}
+	}
+	return TypeZIP
+}
+
+func isHTML(text []byte) bool {
+	lower := bytes.ToLower(text)
+	if bytes.HasPrefix(lower, []byte("<?xml")) {
+		return bytes.Contains(lower, []byte("<html"))
+	}
+	for _, prefix := range []string{"<!doctype html", "<html", "<head", "<body", "<!--"} {
+		if bytes.HasPrefix(lower, []byte(prefix)) {
+			return prefix != "<!--" || bytes.Contains(lower, []byte("<html"))
+		}
+	}
+	return false
+}
+
+// isLaTeX reports whether text is LaTeX source: a document, or a section
+// file of a template split into parts
+func isLaTeX(text []byte) bool {
+	for _, marker := range []string{`\documentclass`, `\begin{document}`, `\section{`, `\cvsection{`} {
+		if bytes.Contains(text, []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isText reports whether the sample is UTF-8 text without control bytes
+func isText(sample []byte) bool {
+	if len(sample) == 0 {
+		return false
+	}
+	// A multi-byte character may be cut off at the end of the sample
+	for i := 0; i < utf8.UTFMax && len(sample) > 0 && !utf8.Valid(sample); i++ {
+		sample = sample[:len(sample)-1]
+	}
+	if !utf8.Valid(sample) {
+		return false
+	}
+	for _, b := range sample {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' && b != '\f' {
+			return false
+		}
+	}
+	return true
+}
+
+// isLegacyText reports whether the sample is single-byte text, such as a
+// Windows-1252 or Latin-1 file that isn't valid UTF-8: printable bytes,
+// line breaks and tabs, without the codes Windows-1252 leaves undefined
+func isLegacyText(sample []byte) bool {
+	if len(sample) == 0 {
+		return false
+	}
+	for _, b := range sample {
+		switch {
+		case b < 0x20 && b != '\n' && b != '\r' && b != '\t' && b != '\f':
+			return false
+		case b == 0x7f || b == 0x81 || b == 0x8d || b == 0x8f || b == 0x90 || b == 0x9d:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,663 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"resumeparser/internal/models"
+	"strconv"
+	"strings"
+)
+
+// docxExtractor reads Word (OOXML) documents
+type docxExtractor struct{}
+
+// NewDocx creates a LayoutExtractor for .docx files
+func NewDocx() LayoutExtractor {
+	return &docxExtractor{}
+}
+
+func (e *docxExtractor) Extract(ctx context.Context, path string) (string, error) {
+	doc, err := e.ExtractLayout(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+func (e *docxExtractor) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DOCX: %w", err)
+	}
+	defer zr.Close()
+
+	return readDocx(ctx, &zr.Reader)
+}
+
+func (e *docxExtractor) ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DOCX: %w", err)
+	}
+	return readDocx(ctx, zr)
+}
+
+type docxStyle struct {
+	name    string
+	basedOn string
+	outline int // outline level + 1, 0 when unset
+	bold    *bool
+	italic  *bool
+	size    float64
+	numID   string
+	ilvl    int
+}
+
+type docxLevel struct {
+	format string
+	text   string
+	indent float64
+}
+
+// docxReader walks word/document.xml and builds the layout document
+type docxReader struct {
+	ctx       context.Context
+	styles    map[string]docxStyle
+	numbering map[string]map[int]docxLevel
+	rels      map[string]string
+	counters  map[string][]int
+	doc       *models.Document
+	page      models.Page
+	order     int
+	err       error
+}
+
+func readDocx(ctx context.Context, zr *zip.Reader) (*models.Document, error) {
+	body, err := readZipXML(zr, "word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("invalid DOCX: %w", err)
+	}
+
+	d := &docxReader{
+		ctx:       ctx,
+		styles:    make(map[string]docxStyle),
+		numbering: make(map[string]map[int]docxLevel),
+		rels:      make(map[string]string),
+		counters:  make(map[string][]int),
+		doc:       &models.Document{},
+		page:      models.Page{Number: 1},
+	}
+	d.doc.SetMeta(models.MetaExtractor, backendDocx)
+	officeMetadata(d.doc, zr)
+	if styles, err := readZipXML(zr, "word/styles.xml"); err == nil {
+		d.loadStyles(styles)
+		if _, ok := d.doc.Metadata[models.MetaLanguage]; !ok {
+			// Fall back to the default proofing language
+			if defaults := styles.find("docDefaults"); defaults != nil {
+				if lang := defaults.find("lang"); lang != nil {
+					d.doc.SetMeta(models.MetaLanguage, lang.attr("val"))
+				}
+			}
+		}
+	}
+	if numbering, err := readZipXML(zr, "word/numbering.xml"); err == nil {
+		d.loadNumbering(numbering)
+	}
+	if rels, err := readZipXML(zr, "word/_rels/document.xml.rels"); err == nil {
+		d.rels = readRelationships(rels)
+	}
+
+	d.walkBlocks(body.find("body"), 0)
+	if d.err != nil {
+		return nil, d.err
+	}
+	d.doc.Pages = append(d.doc.Pages, d.page)
+	return d.doc, nil
+}
+
+// readZipXML parses one XML part of an office document archive
+func readZipXML(zr *zip.Reader, name string) (*xmlNode, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return parseXMLTree(rc)
+	}
+	return nil, fmt.Errorf("missing %s", name)
+}
+
+// readRelationships maps relationship IDs to their targets
+func readRelationships(root *xmlNode) map[string]string {
+	rels := make(map[string]string)
+	var walk func(n *xmlNode)
+	walk = func(n *xmlNode) {
+		for _, c := range n.children {
+			if c.name.Local == "Relationship" {
+				rels[c.attr("Id")] = c.attr("Target")
+			}
+			walk(c)
+		}
+	}
+	walk(root)
+	return rels
+}
+
+func onOff(n *xmlNode) *bool {
+	if n == nil {
+		return nil
+	}
+	v := n.attr("val")
+	on := v == "" || v == "1" || v == "true" || v == "on"
+	return &on
+}
+
+func (d *docxReader) loadStyles(root *xmlNode) {
+	styles := root.find("styles")
+	if styles == nil {
+		return
+	}
+	for _, s := range styles.children {
+		if s.name.Local != "style" {
+			continue
+		}
+		style := docxStyle{
+			name:    strings.ToLower(s.child("name").attr("val")),
+			basedOn: s.child("basedOn").attr("val"),
+			ilvl:    -1,
+		}
+		if ppr := s.child("pPr"); ppr != nil {
+			if lvl, err := strconv.Atoi(ppr.child("outlineLvl").attr("val")); err == nil && lvl < 9 {
+				style.outline = lvl + 1
+			}
+			if numPr := ppr.child("numPr"); numPr != nil {
+				style.numID = numPr.child("numId").attr("val")
+				if lvl, err := strconv.Atoi(numPr.child("ilvl").attr("val")); err == nil {
+					style.ilvl = lvl
+				}
+			}
+		}
+		if rpr := s.child("rPr"); rpr != nil {
+			style.bold = onOff(rpr.child("b"))
+			style.italic = onOff(rpr.child("i"))
+			if sz, err := strconv.ParseFloat(rpr.child("sz").attr("val"), 64); err == nil {
+				style.size = sz / 2
+			}
+		}
+		d.styles[s.attr("styleId")] = style
+	}
+}
+
+// style resolves a style with the properties it inherits through basedOn
+func (d *docxReader) style(id string) docxStyle {
+	var result docxStyle
+	result.ilvl = -1
+	for depth := 0; id != "" && depth < 16; depth++ {
+		s, ok := d.styles[id]
+		if !ok {
+			break
+		}
+		if depth == 0 {
+			result.name = s.name
+		}
+		if result.outline == 0 {
+			result.outline = s.outline
+		}
+		if result.bold == nil {
+			result.bold = s.bold
+		}
+		if result.italic == nil {
+			result.italic = s.italic
+		}
+		if result.size == 0 {
+			result.size = s.size
+		}
+		if result.numID == "" {
+			result.numID, result.ilvl = s.numID, s.ilvl
+		}
+		id = s.basedOn
+	}
+	return result
+}
+
+var headingStyleName = regexp.MustCompile(`^heading\s*(\d)$`)
+
+// headingLevel returns the heading level of a paragraph style
+func (s docxStyle) headingLevel() int {
+	if m := headingStyleName.FindStringSubmatch(s.name); m != nil {
+		level, _ := strconv.Atoi(m[1])
+		return level
+	}
+	return s.outline
+}
+
+func (d *docxReader) loadNumbering(root *xmlNode) {
+	numbering := root.find("numbering")
+	if numbering == nil {
+		return
+	}
+
+	abstract := make(map[string]map[int]docxLevel)
+	for _, n := range numbering.children {
+		if n.name.Local != "abstractNum" {
+			continue
+		}
+		levels := make(map[int]docxLevel)
+		for _, lvl := range n.children {
+			if lvl.name.Local != "lvl" {
+				continue
+			}
+			ilvl, _ := strconv.Atoi(lvl.attr("ilvl"))
+			level := docxLevel{
+				format: lvl.child("numFmt").attr("val"),
+				text:   lvl.child("lvlText").attr("val"),
+			}
+			if left, err := strconv.ParseFloat(lvl.child("pPr").child("ind").attr("left"), 64); err == nil {
+				level.indent = left / 20
+			}
+			levels[ilvl] = level
+		}
+		abstract[n.attr("abstractNumId")] = levels
+	}
+
+	for _, n := range numbering.children {
+		if n.name.Local == "num" {
+			d.numbering[n.attr("numId")] = abstract[n.child("abstractNumId").attr("val")]
+		}
+	}
+}
+
+// listMarker returns the bullet or number for a list paragraph and
+// advances the list counters
+func (d *docxReader) listMarker(numID string, ilvl int) (string, float64) {
+	levels, ok := d.numbering[numID]
+	if !ok || numID == "0" || ilvl < 0 || ilvl > 8 {
+		return "", 0
+	}
+	level := levels[ilvl]
+
+	counters := d.counters[numID]
+	for len(counters) <= ilvl {
+		counters = append(counters, 0)
+	}
+	counters[ilvl]++
+	for i := ilvl + 1; i < len(counters); i++ {
+		counters[i] = 0
+	}
+	d.counters[numID] = counters
+
+	indent := level.indent
+	if indent == 0 {
+		indent = float64(ilvl+1) * 18
+	}
+
+	switch level.format {
+	case "bullet", "none", "":
+		return "•", indent
+	}
+
+	marker := level.text
+	if marker == "" {
+		marker = "%" + strconv.Itoa(ilvl+1) + "."
+	}
+	for i := 0; i <= ilvl && i < len(counters); i++ {
+		format := levels[i].format
+		marker = strings.ReplaceAll(marker, "%"+strconv.Itoa(i+1), formatListNumber(counters[i], format))
+	}
+	return marker, indent
+}
+
+func formatListNumber(n int, format string) string {
+	switch format {
+	case "lowerLetter":
+		return string(rune('a' + (n-1)%26))
+	case "upperLetter":
+		return string(rune('A' + (n-1)%26))
+	case "lowerRoman":
+		return strings.ToLower(romanNumeral(n))
+	case "upperRoman":
+		return romanNumeral(n)
+	}
+	return strconv.Itoa(n)
+}
+
+func romanNumeral(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+	var b strings.Builder
+	for i, v := range values {
+		for n >= v {
+			b.WriteString(symbols[i])
+			n -= v
+		}
+	}
+	return b.String()
+}
+
+// walkBlocks emits the paragraphs and tables inside a block container
+func (d *docxReader) walkBlocks(n *xmlNode, column int) {
+	if n == nil {
+		return
+	}
+	for _, c := range n.children {
+		if d.err != nil {
+			return
+		}
+		switch c.name.Local {
+		case "p":
+			for _, block := range d.paragraph(c) {
+				if block.pageBreak {
+					d.pageBreak()
+					continue
+				}
+				block.line.Column = column
+				d.addLine(block.line)
+			}
+		case "tbl":
+			d.table(c, column)
+		case "sdt", "sdtContent", "customXml", "ins", "smartTag":
+			d.walkBlocks(c, column)
+		}
+	}
+}
+
+func (d *docxReader) pageBreak() {
+	d.doc.Pages = append(d.doc.Pages, d.page)
+	d.page = models.Page{Number: d.page.Number + 1}
+}
+
+func (d *docxReader) addLine(line models.TextLine) {
+	for i := range line.Runs {
+		line.Runs[i].Order = d.order
+		d.order++
+	}
+	d.page.Lines = append(d.page.Lines, line)
+}
+
+// table emits rows whose cells hold a single line as one line with the
+// cells separated by " | ". Rows with multi-paragraph cells are layout
+// tables, and each cell is emitted as its own column.
+func (d *docxReader) table(tbl *xmlNode, column int) {
+	if err := d.ctx.Err(); err != nil {
+		d.err = err
+		return
+	}
+	for _, tr := range tbl.children {
+		if tr.name.Local != "tr" {
+			continue
+		}
+
+		var cells [][]models.TextLine
+		for _, tc := range tr.children {
+			if tc.name.Local != "tc" {
+				continue
+			}
+			sub := &docxReader{
+				ctx: d.ctx, styles: d.styles, numbering: d.numbering, rels: d.rels,
+				counters: d.counters, doc: &models.Document{}, page: models.Page{Number: d.page.Number},
+			}
+			sub.walkBlocks(tc, 0)
+			var lines []models.TextLine
+			for _, p := range append(sub.doc.Pages, sub.page) {
+				for _, line := range p.Lines {
+					if line.Text() != "" {
+						lines = append(lines, line)
+					}
+				}
+			}
+			d.doc.Links = append(d.doc.Links, sub.doc.Links...)
+			if len(lines) > 0 {
+				cells = append(cells, lines)
+			}
+		}
+
+		simple := true
+		for _, cell := range cells {
+			if len(cell) > 1 {
+				simple = false
+			}
+		}
+
+		switch {
+		case len(cells) == 0:
+		case simple:
+			var row models.TextLine
+			row.Column = column
+			for i, cell := range cells {
+				if i > 0 {
+					row.Runs = append(row.Runs, models.TextRun{Text: " | "})
+				}
+				row.Runs = append(row.Runs, cell[0].Runs...)
+				row.Heading = max(row.Heading, cell[0].Heading)
+			}
+			d.addLine(row)
+		default:
+			for i, cell := range cells {
+				for _, line := range cell {
+					if len(cells) > 1 {
+						line.Column = i + 1
+					} else {
+						line.Column = column
+					}
+					d.addLine(line)
+				}
+			}
+		}
+	}
+}
+
+var hyperlinkField = regexp.MustCompile(`HYPERLINK\s+"([^"]+)"`)
+
+// docxBlock is a line of a paragraph or a page break between lines
+type docxBlock struct {
+	line      models.TextLine
+	pageBreak bool
+}
+
+// paragraph converts a w:p element into lines, splitting at line breaks
+func (d *docxReader) paragraph(p *xmlNode) []docxBlock {
+	ppr := p.child("pPr")
+	style := d.style(ppr.child("pStyle").attr("val"))
+
+	heading := style.headingLevel()
+	if lvl, err := strconv.Atoi(ppr.child("outlineLvl").attr("val")); err == nil && lvl < 9 {
+		heading = lvl + 1
+	}
+
+	numID, ilvl := style.numID, style.ilvl
+	if numPr := ppr.child("numPr"); numPr != nil {
+		if id := numPr.child("numId").attr("val"); id != "" {
+			numID = id
+		}
+		if lvl, err := strconv.Atoi(numPr.child("ilvl").attr("val")); err == nil {
+			ilvl = lvl
+		} else if ilvl < 0 {
+			ilvl = 0
+		}
+	}
+
+	var indent float64
+	if left, err := strconv.ParseFloat(ppr.child("ind").attr("left"), 64); err == nil {
+		indent = left / 20
+	}
+
+	var blocks []docxBlock
+	current := models.TextLine{Heading: heading}
+	if marker, listIndent := d.listMarker(numID, ilvl); marker != "" {
+		if indent == 0 {
+			indent = listIndent
+		}
+		current.Runs = append(current.Runs, models.TextRun{Text: marker + " ", FontSize: style.size})
+	}
+
+	var fieldURL, fieldText string
+	inFieldResult := false
+
+	var emit func(n *xmlNode)
+	emit = func(n *xmlNode) {
+		for _, c := range n.children {
+			switch c.name.Local {
+			case "r":
+				text, breaks := d.runText(c)
+				if fld := c.child("fldChar"); fld != nil {
+					switch fld.attr("fldCharType") {
+					case "separate":
+						inFieldResult = fieldURL != ""
+					case "end":
+						if inFieldResult && fieldURL != "" {
+							d.addLink(fieldURL, fieldText)
+						}
+						fieldURL, fieldText, inFieldResult = "", "", false
+					}
+				}
+				if instr := c.child("instrText"); instr != nil {
+					if m := hyperlinkField.FindStringSubmatch(instr.textContent()); m != nil {
+						fieldURL = m[1]
+					}
+				}
+
+				for i, segment := range strings.Split(text, "\n") {
+					if i > 0 {
+						blocks = append(blocks, docxBlock{line: current})
+						current = models.TextLine{Heading: heading}
+					}
+					if segment == "" {
+						continue
+					}
+					current.Runs = append(current.Runs, d.textRun(segment, c.child("rPr"), style))
+					if inFieldResult {
+						fieldText += segment
+					}
+				}
+				if breaks {
+					blocks = append(blocks, docxBlock{line: current}, docxBlock{pageBreak: true})
+					current = models.TextLine{Heading: heading}
+				}
+			case "hyperlink":
+				target := d.rels[c.attr("id")]
+				before := len(current.Runs)
+				emit(c)
+				if target != "" {
+					var anchor strings.Builder
+					for _, run := range current.Runs[min(before, len(current.Runs)):] {
+						anchor.WriteString(run.Text)
+					}
+					d.addLink(target, anchor.String())
+				}
+			case "fldSimple":
+				before := len(current.Runs)
+				emit(c)
+				if m := hyperlinkField.FindStringSubmatch(c.attr("instr")); m != nil {
+					var anchor strings.Builder
+					for _, run := range current.Runs[min(before, len(current.Runs)):] {
+						anchor.WriteString(run.Text)
+					}
+					d.addLink(m[1], anchor.String())
+				}
+			case "ins", "smartTag", "sdt", "sdtContent", "customXml":
+				emit(c)
+			}
+		}
+	}
+	emit(p)
+
+	blocks = append(blocks, docxBlock{line: current})
+	var result []docxBlock
+	for _, block := range blocks {
+		if !block.pageBreak && strings.TrimSpace(block.line.Text()) == "" {
+			continue
+		}
+		for i := range block.line.Runs {
+			block.line.Runs[i].X = indent
+		}
+		result = append(result, block)
+	}
+	if len(result) == 0 {
+		// Keep empty paragraphs as blank lines
+		result = append(result, docxBlock{line: models.TextLine{Runs: []models.TextRun{{}}}})
+	}
+	return result
+}
+
+func (d *docxReader) addLink(uri, text string) {
+	d.doc.Links = append(d.doc.Links, models.Link{
+		URI:  uri,
+		Text: strings.TrimSpace(text),
+		Page: d.page.Number,
+	})
+}
+
+// runText returns the text of a w:r element with line breaks as newlines,
+// and whether the run ends with a page break
+func (d *docxReader) runText(r *xmlNode) (string, bool) {
+	var b strings.Builder
+	pageBreak := false
+	for _, c := range r.children {
+		switch c.name.Local {
+		case "t":
+			b.WriteString(c.textContent())
+		case "tab", "ptab":
+			b.WriteString("\t")
+		case "br", "cr":
+			if c.attr("type") == "page" {
+				pageBreak = true
+				continue
+			}
+			b.WriteString("\n")
+		case "noBreakHyphen", "softHyphen":
+			b.WriteString("-")
+		case "sym":
+			if v, err := strconv.ParseUint(c.attr("char"), 16, 32); err == nil {
+				b.WriteRune(rune(v))
+			}
+		}
+	}
+	return b.String(), pageBreak
+}
+
+func (d *docxReader) textRun(text string, rpr *xmlNode, style docxStyle) models.TextRun {
+	run := models.TextRun{
+		Text:     text,
+		FontSize: style.size,
+	}
+	if style.bold != nil {
+		run.Bold = *style.bold
+	}
+	if style.italic != nil {
+		run.Italic = *style.italic
+	}
+
+	if rStyle := rpr.child("rStyle").attr("val"); rStyle != "" {
+		cs := d.style(rStyle)
+		if cs.bold != nil {
+			run.Bold = *cs.bold
+		}
+		if cs.italic != nil {
+			run.Italic = *cs.italic
+		}
+		if cs.size > 0 {
+			run.FontSize = cs.size
+		}
+	}
+	if b := onOff(rpr.child("b")); b != nil {
+		run.Bold = *b
+	}
+	if i := onOff(rpr.child("i")); i != nil {
+		run.Italic = *i
+	}
+	if sz, err := strconv.ParseFloat(rpr.child("sz").attr("val"), 64); err == nil {
+		run.FontSize = sz / 2
+	}
+	if fonts := rpr.child("rFonts"); fonts != nil {
+		run.FontName = fonts.attr("ascii")
+	}
+	run.Height = run.FontSize
+	return run
+}
@@ -0,0 +1,147 @@
+package extractor
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+const testDocumentXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+  xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<w:body>
+  <w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>Jane Smith</w:t></w:r></w:p>
+  <w:p>
+    <w:r><w:t xml:space="preserve">jane@example.com | </w:t></w:r>
+    <w:hyperlink r:id="rId9"><w:r><w:t>LinkedIn</w:t></w:r></w:hyperlink>
+  </w:p>
+  <w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Work History</w:t></w:r></w:p>
+  <w:tbl>
+    <w:tr>
+      <w:tc><w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Initech</w:t></w:r></w:p></w:tc>
+      <w:tc><w:p><w:r><w:t>2021 - Present</w:t></w:r></w:p></w:tc>
+    </w:tr>
+  </w:tbl>
+  <w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Built APIs</w:t></w:r></w:p>
+  <w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="2"/></w:numPr></w:pPr><w:r><w:t>First</w:t></w:r></w:p>
+  <w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="2"/></w:numPr></w:pPr><w:r><w:t>Second</w:t><w:br w:type="page"/></w:r></w:p>
+  <w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Skills</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+
+const testStylesXML = `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:rPr><w:sz w:val="40"/></w:rPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:pPr><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/></w:rPr></w:style>
+</w:styles>`
+
+const testNumberingXML = `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:numFmt w:val="bullet"/><w:lvlText w:val="•"/></w:lvl></w:abstractNum>
+  <w:abstractNum w:abstractNumId="1"><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/><w:lvlText w:val="%1."/></w:lvl></w:abstractNum>
+  <w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>
+  <w:num w:numId="2"><w:abstractNumId w:val="1"/></w:num>
+</w:numbering>`
+
+const testRelsXML = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId9" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://www.linkedin.com/in/janesmith" TargetMode="External"/>
+</Relationships>`
+
+const testCoreXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+<dc:creator>Jane Smith</dc:creator>
+<dcterms:created xsi:type="dcterms:W3CDTF">2024-03-01T09:30:00Z</dcterms:created>
+</cp:coreProperties>`
+
+const testAppXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Application>Microsoft Office Word</Application></Properties>`
+
+func writeTestDocx(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "resume.docx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	parts := map[string]string{
+		"word/document.xml":            testDocumentXML,
+		"word/styles.xml":              testStylesXML,
+		"word/numbering.xml":           testNumberingXML,
+		"word/_rels/document.xml.rels": testRelsXML,
+		"docProps/core.xml":            testCoreXML,
+		"docProps/app.xml":             testAppXML,
+	}
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDocxExtractor(t *testing.T) {
+	doc, err := NewDocx().ExtractLayout(context.Background(), writeTestDocx(t))
+	if err != nil {
+		t.Fatalf("ExtractLayout() error = %v", err)
+	}
+
+	if len(doc.Pages) != 2 {
+		t.Fatalf("got %d pages, want 2", len(doc.Pages))
+	}
+
+	var texts []string
+	var headings []string
+	for _, line := range doc.Pages[0].Lines {
+		texts = append(texts, line.Text())
+		if line.Heading > 0 {
+			headings = append(headings, line.Text())
+		}
+	}
+	want := []string{
+		"Jane Smith",
+		"jane@example.com | LinkedIn",
+		"Work History",
+		"Initech | 2021 - Present",
+		"• Built APIs",
+		"1. First",
+		"2. Second",
+	}
+	if strings.Join(texts, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines = %q, want %q", texts, want)
+	}
+	if len(headings) != 1 || headings[0] != "Work History" {
+		t.Errorf("headings = %q, want [Work History]", headings)
+	}
+	if got := doc.Pages[1].Lines[0]; got.Heading != 1 || !got.Bold() {
+		t.Errorf("page 2 heading = %+v, want bold level 1 heading", got)
+	}
+	if doc.Pages[0].Lines[0].FontSize() != 20 {
+		t.Errorf("title size = %v, want 20", doc.Pages[0].Lines[0].FontSize())
+	}
+
+	if len(doc.Links) != 1 || doc.Links[0].URI != "https://www.linkedin.com/in/janesmith" || doc.Links[0].Text != "LinkedIn" {
+		t.Errorf("links = %+v", doc.Links)
+	}
+
+	wantMeta := map[string]string{
+		models.MetaAuthor:    "Jane Smith",
+		models.MetaCreator:   "Microsoft Office Word",
+		models.MetaCreated:   "2024-03-01T09:30:00Z",
+		models.MetaExtractor: "docx",
+	}
+	for key, want := range wantMeta {
+		if got := doc.Metadata[key]; got != want {
+			t.Errorf("Metadata[%q] = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package extractor
+
+import (
+	"resumeparser/internal/models"
+	"strings"
+)
+
+// runStyle is the character formatting applied to a piece of text
+type runStyle struct {
+	bold   bool
+	italic bool
+	size   float64
+	font   string
+}
+
+// flowWriter builds the layout of reflowable documents such as RTF, ODT
+// and HTML, which have paragraphs, lists and tables but no positions.
+// Runs are placed at their paragraph indent so list nesting survives.
+type flowWriter struct {
+	doc     *models.Document
+	page    models.Page
+	line    models.TextLine
+	indent  float64 // left indent of the current paragraph in points
+	heading int     // heading level of the current paragraph
+	column  int
+	order   int
+}
+
+func newFlowWriter() *flowWriter {
+	return &flowWriter{
+		doc:  &models.Document{},
+		page: models.Page{Number: 1},
+	}
+}
+
+// write appends text to the current paragraph; newlines start a new line
+// within it
+func (w *flowWriter) write(text string, style runStyle) {
+	for i, segment := range strings.Split(text, "\n") {
+		if i > 0 {
+			w.breakLine()
+		}
+		if segment == "" {
+			continue
+		}
+		w.line.Runs = append(w.line.Runs, models.TextRun{
+			Text:     segment,
+			FontName: style.font,
+			FontSize: style.size,
+			Height:   style.size,
+			Bold:     style.bold,
+			Italic:   style.italic,
+		})
+	}
+}
+
+// breakLine ends the current line without ending the paragraph
+func (w *flowWriter) breakLine() {
+	if strings.TrimSpace(w.line.Text()) != "" {
+		w.addLine(w.line)
+	}
+	w.line = models.TextLine{}
+}
+
+// endParagraph flushes the current line. Empty paragraphs are kept as a
+// single blank line, since they often separate entries.
+func (w *flowWriter) endParagraph() {
+	if strings.TrimSpace(w.line.Text()) != "" {
+		w.addLine(w.line)
+	} else if n := len(w.page.Lines); n > 0 && w.page.Lines[n-1].Text() != "" {
+		w.addLine(models.TextLine{Runs: []models.TextRun{{}}})
+	}
+	w.line = models.TextLine{}
+	w.indent, w.heading = 0, 0
+}
+
+func (w *flowWriter) addLine(line models.TextLine) {
+	line.Heading = max(line.Heading, w.heading)
+	if line.Column == 0 {
+		line.Column = w.column
+	}
+	for i := range line.Runs {
+		if line.Runs[i].X == 0 {
+			line.Runs[i].X = w.indent
+		}
+		line.Runs[i].Order = w.order
+		w.order++
+	}
+	w.page.Lines = append(w.page.Lines, line)
+}
+
+func (w *flowWriter) pageBreak() {
+	w.endPage()
+	w.page = models.Page{Number: w.page.Number + 1}
+}
+
+// endPage adds the current page to the document, dropping the blank line
+// left by a final empty paragraph
+func (w *flowWriter) endPage() {
+	w.endParagraph()
+	if n := len(w.page.Lines); n > 0 && w.page.Lines[n-1].Text() == "" {
+		w.page.Lines = w.page.Lines[:n-1]
+	}
+	w.doc.Pages = append(w.doc.Pages, w.page)
+}
+
+func (w *flowWriter) addLink(uri, text string) {
+	uri = strings.TrimSpace(uri)
+	if uri == "" || strings.HasPrefix(uri, "#") {
+		return
+	}
+	w.doc.Links = append(w.doc.Links, models.Link{
+		URI:  uri,
+		Text: strings.TrimSpace(text),
+		Page: w.page.Number,
+	})
+}
+
+// lineText joins the current line's runs from index before on, which
+// gives the anchor text of a link that started there
+func (w *flowWriter) lineText(before int) string {
+	var b strings.Builder
+	for _, run := range w.line.Runs[min(before, len(w.line.Runs)):] {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// cell returns a writer for the contents of a table cell
+func (w *flowWriter) cell() *flowWriter {
+	return &flowWriter{doc: &models.Document{}, page: models.Page{Number: w.page.Number}}
+}
+
+// tableRow emits the cells of a table row, read back from their writers.
+// Rows whose cells hold a single line become one line with the cells
+// separated by " | "; other rows are layout tables and each cell becomes
+// its own column, as in DOCX files.
+func (w *flowWriter) tableRow(cellWriters []*flowWriter) {
+	w.breakLine()
+	var cells [][]models.TextLine
+	for _, cw := range cellWriters {
+		cw.endParagraph()
+		w.doc.Links = append(w.doc.Links, cw.doc.Links...)
+		var lines []models.TextLine
+		for _, p := range append(cw.doc.Pages, cw.page) {
+			for _, line := range p.Lines {
+				if line.Text() != "" {
+					lines = append(lines, line)
+				}
+			}
+		}
+		if len(lines) > 0 {
+			cells = append(cells, lines)
+		}
+	}
+
+	simple := true
+	for _, cell := range cells {
+		if len(cell) > 1 {
+			simple = false
+		}
+	}
+
+	switch {
+	case len(cells) == 0:
+	case simple:
+		var row models.TextLine
+		for i, cell := range cells {
+			if i > 0 {
+				row.Runs = append(row.Runs, models.TextRun{Text: " | "})
+			}
+			row.Runs = append(row.Runs, cell[0].Runs...)
+			row.Heading = max(row.Heading, cell[0].Heading)
+		}
+		w.addLine(row)
+	default:
+		for i, cell := range cells {
+			for _, line := range cell {
+				if len(cells) > 1 {
+					line.Column = i + 1
+				}
+				w.addLine(line)
+			}
+		}
+	}
+}
+
+// finish returns the document with its last page
+func (w *flowWriter) finish() *models.Document {
+	w.endPage()
+	return w.doc
+}
+
+// collapseSpace replaces each run of white space with a single space, as
+// ODF and HTML render paragraph text
+func collapseSpace(s string) string {
+	if !strings.ContainsAny(s, "\t\r\n") && !strings.Contains(s, "  ") {
+		return s
+	}
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == '\f' {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		b.WriteRune(r)
+		space = false
+	}
+	return b.String()
+}
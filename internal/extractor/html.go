@@ -0,0 +1,442 @@
+package extractor
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"os"
+	"resumeparser/internal/models"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// htmlExtractor reads HTML resumes such as job board exports
+type htmlExtractor struct{}
+
+// NewHTML creates a LayoutExtractor for .html files
+func NewHTML() LayoutExtractor {
+	return &htmlExtractor{}
+}
+
+func (e *htmlExtractor) Extract(ctx context.Context, path string) (string, error) {
+	doc, err := e.ExtractLayout(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+func (e *htmlExtractor) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTML: %w", err)
+	}
+	return e.ExtractLayoutBytes(ctx, data)
+}
+
+func (e *htmlExtractor) ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error) {
+	text := string(data)
+	if !utf8.ValidString(text) {
+		// Pages that aren't UTF-8 are nearly always Windows-1252
+		var b strings.Builder
+		for _, c := range data {
+			if r, ok := cp1252[c]; ok {
+				b.WriteRune(r)
+			} else {
+				b.WriteRune(rune(c))
+			}
+		}
+		text = b.String()
+	}
+
+	root := parseHTMLTree(text)
+	h := &htmlReader{ctx: ctx, w: newFlowWriter()}
+	body := root.find("body")
+	if body == nil {
+		body = root
+	}
+	h.walk(h.w, body, runStyle{})
+	if h.err != nil {
+		return nil, h.err
+	}
+
+	doc := h.w.finish()
+	doc.SetMeta(models.MetaExtractor, backendHTML)
+	htmlMetadata(doc, root)
+	return doc, nil
+}
+
+// htmlMetadata records the title, meta tags and declared language
+func htmlMetadata(doc *models.Document, root *xmlNode) {
+	if title := root.find("title"); title != nil {
+		doc.SetMeta(models.MetaTitle, collapseSpace(title.textContent()))
+	}
+	doc.SetMeta(models.MetaLanguage, root.find("html").attr("lang"))
+
+	var walk func(n *xmlNode)
+	walk = func(n *xmlNode) {
+		for _, c := range n.children {
+			if c.name.Local != "meta" {
+				walk(c)
+				continue
+			}
+			content := c.attr("content")
+			switch strings.ToLower(c.attr("name")) {
+			case "author":
+				doc.SetMeta(models.MetaAuthor, content)
+			case "generator":
+				doc.SetMeta(models.MetaCreator, content)
+				doc.SetMeta(models.MetaProducer, content)
+			}
+			if strings.EqualFold(c.attr("http-equiv"), "content-language") {
+				doc.SetMeta(models.MetaLanguage, content)
+			}
+		}
+	}
+	walk(root)
+}
+
+// htmlList tracks a ul or ol element being walked
+type htmlList struct {
+	ordered bool
+	counter int
+}
+
+// htmlReader renders an HTML element tree as lines
+type htmlReader struct {
+	ctx   context.Context
+	w     *flowWriter
+	lists []*htmlList
+	pre   int // depth of nested pre elements
+	err   error
+}
+
+// htmlSkipped are elements whose content is never shown
+var htmlSkipped = map[string]bool{
+	"head": true, "script": true, "style": true, "template": true, "noscript": true,
+	"svg": true, "math": true, "iframe": true, "object": true, "select": true,
+}
+
+// htmlBlocks are elements that start and end a line
+var htmlBlocks = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true, "footer": true,
+	"main": true, "aside": true, "nav": true, "blockquote": true, "address": true,
+	"figure": true, "figcaption": true, "dl": true, "dt": true, "dd": true, "form": true,
+	"fieldset": true, "legend": true, "center": true, "caption": true, "details": true,
+	"summary": true, "hr": true, "body": true, "html": true,
+}
+
+func (h *htmlReader) walk(w *flowWriter, n *xmlNode, rs runStyle) {
+	h.text(w, n.text, rs)
+	for _, c := range n.children {
+		if h.err != nil {
+			return
+		}
+		h.element(w, c, rs)
+		h.text(w, c.tail, rs)
+	}
+}
+
+func (h *htmlReader) text(w *flowWriter, s string, rs runStyle) {
+	if h.pre > 0 {
+		w.write(strings.TrimPrefix(s, "\r"), rs)
+		return
+	}
+	w.write(collapseSpace(s), rs)
+}
+
+func (h *htmlReader) element(w *flowWriter, c *xmlNode, rs runStyle) {
+	if err := h.ctx.Err(); err != nil {
+		h.err = err
+		return
+	}
+	name := c.name.Local
+	switch {
+	case htmlSkipped[name]:
+	case htmlBlocks[name]:
+		w.breakLine()
+		h.walk(w, c, rs)
+		w.breakLine()
+	case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+		w.breakLine()
+		w.heading = int(name[1] - '0')
+		rs.bold = true
+		h.walk(w, c, rs)
+		w.breakLine()
+		w.heading = 0
+	case name == "ul" || name == "ol" || name == "menu":
+		list := &htmlList{ordered: name == "ol"}
+		if start, err := strconv.Atoi(c.attr("start")); err == nil {
+			list.counter = start - 1
+		}
+		h.lists = append(h.lists, list)
+		w.breakLine()
+		h.walk(w, c, rs)
+		w.breakLine()
+		h.lists = h.lists[:len(h.lists)-1]
+		w.indent = float64(len(h.lists)) * 18
+	case name == "li":
+		w.breakLine()
+		w.indent = float64(max(len(h.lists), 1)) * 18
+		marker := "•"
+		if len(h.lists) > 0 && h.lists[len(h.lists)-1].ordered {
+			list := h.lists[len(h.lists)-1]
+			list.counter++
+			marker = strconv.Itoa(list.counter) + "."
+		}
+		w.write(marker+" ", rs)
+		h.walk(w, c, rs)
+		w.breakLine()
+	case name == "table":
+		h.table(w, c, rs)
+	case name == "pre":
+		w.breakLine()
+		h.pre++
+		h.walk(w, c, rs)
+		h.pre--
+		w.breakLine()
+	case name == "br":
+		w.write("\n", rs)
+	case name == "a":
+		before := len(w.line.Runs)
+		h.walk(w, c, rs)
+		w.addLink(c.attr("href"), w.lineText(before))
+	case name == "b" || name == "strong" || name == "th":
+		rs.bold = true
+		h.walk(w, c, rs)
+	case name == "i" || name == "em" || name == "cite":
+		rs.italic = true
+		h.walk(w, c, rs)
+	default:
+		h.walk(w, c, rs)
+	}
+}
+
+// table emits each row through the writer's table handling
+func (h *htmlReader) table(w *flowWriter, tbl *xmlNode, rs runStyle) {
+	for _, c := range tbl.children {
+		switch c.name.Local {
+		case "tr":
+			var cells []*flowWriter
+			for _, td := range c.children {
+				if td.name.Local != "td" && td.name.Local != "th" {
+					continue
+				}
+				cw := w.cell()
+				cw.indent = w.indent
+				cellStyle := rs
+				cellStyle.bold = rs.bold || td.name.Local == "th"
+				h.walk(cw, td, cellStyle)
+				cells = append(cells, cw)
+			}
+			w.tableRow(cells)
+		case "thead", "tbody", "tfoot":
+			h.table(w, c, rs)
+		case "caption":
+			h.element(w, c, rs)
+		}
+	}
+}
+
+// htmlVoid are elements that never have content or an end tag
+var htmlVoid = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "param": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// htmlRawText are elements whose content is not markup
+var htmlRawText = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// htmlClosedBy lists, for elements with optional end tags, the start tags
+// that implicitly close them
+var htmlClosedBy = map[string]map[string]bool{
+	"p": {"p": true, "div": true, "ul": true, "ol": true, "table": true, "h1": true, "h2": true,
+		"h3": true, "h4": true, "h5": true, "h6": true, "pre": true, "blockquote": true,
+		"section": true, "header": true, "footer": true, "dl": true, "hr": true, "form": true},
+	"li":     {"li": true},
+	"dt":     {"dt": true, "dd": true},
+	"dd":     {"dt": true, "dd": true},
+	"tr":     {"tr": true, "tbody": true, "tfoot": true},
+	"td":     {"td": true, "th": true, "tr": true, "tbody": true, "tfoot": true},
+	"th":     {"td": true, "th": true, "tr": true, "tbody": true, "tfoot": true},
+	"thead":  {"tbody": true, "tfoot": true},
+	"tbody":  {"tbody": true, "tfoot": true},
+	"option": {"option": true},
+}
+
+// htmlScopes are elements an implied end tag does not reach past, so a
+// list inside a list item doesn't close the item
+var htmlScopes = map[string]bool{
+	"ul": true, "ol": true, "table": true, "td": true, "th": true, "div": true, "body": true, "html": true,
+}
+
+// parseHTMLTree builds an element tree from HTML, tolerating the missing
+// end tags and unquoted attributes that browsers accept. Tag and attribute
+// names are lower case.
+func parseHTMLTree(s string) *xmlNode {
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	appendText := func(text string) {
+		text = html.UnescapeString(text)
+		top := stack[len(stack)-1]
+		if n := len(top.children); n > 0 {
+			top.children[n-1].tail += text
+		} else {
+			top.text += text
+		}
+	}
+
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			appendText(s)
+			break
+		}
+		if lt > 0 {
+			appendText(s[:lt])
+			s = s[lt:]
+		}
+
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s[4:], "-->")
+			if end < 0 {
+				return root
+			}
+			s = s[4+end+3:]
+		case strings.HasPrefix(s, "<!") || strings.HasPrefix(s, "<?"):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return root
+			}
+			s = s[end+1:]
+		case strings.HasPrefix(s, "</") && len(s) > 2 && isASCIILetter(s[2]):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return root
+			}
+			name := strings.ToLower(strings.TrimSpace(s[2:end]))
+			if i := strings.IndexAny(name, " \t\r\n/"); i >= 0 {
+				name = name[:i]
+			}
+			s = s[end+1:]
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name.Local == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		case len(s) > 1 && isASCIILetter(s[1]):
+			node, rest, selfClosing := parseHTMLTag(s)
+			s = rest
+			name := node.name.Local
+
+			// Close elements whose end tag is implied by this one
+			for i := len(stack) - 1; i > 0; i-- {
+				open := stack[i].name.Local
+				if htmlClosedBy[open][name] {
+					stack = stack[:i]
+					break
+				}
+				if htmlScopes[open] {
+					break
+				}
+			}
+
+			top := stack[len(stack)-1]
+			top.children = append(top.children, node)
+			if htmlRawText[name] {
+				end := strings.Index(strings.ToLower(s), "</"+name)
+				if end < 0 {
+					end = len(s)
+				}
+				node.text = s[:end]
+				if name == "title" || name == "textarea" {
+					node.text = html.UnescapeString(node.text)
+				}
+				s = s[end:]
+				if gt := strings.IndexByte(s, '>'); gt >= 0 {
+					s = s[gt+1:]
+				}
+				continue
+			}
+			if !selfClosing && !htmlVoid[name] {
+				stack = append(stack, node)
+			}
+		default:
+			appendText("<")
+			s = s[1:]
+		}
+	}
+	return root
+}
+
+// parseHTMLTag reads a start tag at the beginning of s
+func parseHTMLTag(s string) (*xmlNode, string, bool) {
+	i := 1
+	for i < len(s) && !strings.ContainsRune(" \t\r\n/>", rune(s[i])) {
+		i++
+	}
+	node := &xmlNode{name: xml.Name{Local: strings.ToLower(s[1:i])}}
+	selfClosing := false
+
+	for i < len(s) {
+		for i < len(s) && strings.ContainsRune(" \t\r\n", rune(s[i])) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		if s[i] == '>' {
+			i++
+			break
+		}
+		if s[i] == '/' {
+			selfClosing = true
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(s) && !strings.ContainsRune(" \t\r\n/>=", rune(s[i])) {
+			i++
+		}
+		attr := xml.Attr{Name: xml.Name{Local: strings.ToLower(s[start:i])}}
+		for i < len(s) && strings.ContainsRune(" \t\r\n", rune(s[i])) {
+			i++
+		}
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && strings.ContainsRune(" \t\r\n", rune(s[i])) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				quote := s[i]
+				end := strings.IndexByte(s[i+1:], quote)
+				if end < 0 {
+					end = len(s) - i - 1
+				}
+				attr.Value = s[i+1 : i+1+end]
+				i = min(i+1+end+1, len(s))
+			} else {
+				start := i
+				for i < len(s) && !strings.ContainsRune(" \t\r\n>", rune(s[i])) {
+					i++
+				}
+				attr.Value = s[start:i]
+			}
+		}
+		if attr.Name.Local != "" {
+			attr.Value = html.UnescapeString(attr.Value)
+			node.attrs = append(node.attrs, attr)
+		} else {
+			i++ // stray character
+		}
+	}
+	return node, s[i:], selfClosing
+}
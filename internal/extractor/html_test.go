@@ -0,0 +1,72 @@
+package extractor
+
+import (
+	"context"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+const testHTML = `<!DOCTYPE html>
+<html lang="en-US"><head><title>Jane Smith &ndash; Resume</title>
+<meta name=author content="Jane Smith"><style>p { color: red }</style></head>
+<body>
+<!-- exported by a job board -->
+<h1>Jane Smith</h1>
+<p>jane@example.com | <a href="https://github.com/jsmith">GitHub</a>
+<h2>Experience</h2>
+<p><b>Initech</b> &mdash; Engineer<br>2021 - Present
+<ul>
+  <li>Built <em>APIs</em>
+  <li>Led team
+    <ol><li>Hiring<li>Mentoring</ol>
+</ul>
+<table><tr><th>Python<td>Go</tr></table>
+<script>if (a < b) { document.write("<p>hidden</p>") }</script>
+</body></html>`
+
+func TestHTMLExtractor(t *testing.T) {
+	doc, err := NewHTML().(BytesExtractor).ExtractLayoutBytes(context.Background(), []byte(testHTML))
+	if err != nil {
+		t.Fatalf("ExtractLayoutBytes() error = %v", err)
+	}
+
+	want := []string{
+		"Jane Smith",
+		"jane@example.com | GitHub",
+		"Experience",
+		"Initech — Engineer",
+		"2021 - Present",
+		"• Built APIs",
+		"• Led team",
+		"1. Hiring",
+		"2. Mentoring",
+		"Python | Go",
+	}
+	if got := docLines(doc); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+
+	lines := doc.Pages[0].Lines
+	if lines[0].Heading != 1 || lines[2].Heading != 2 || !lines[2].Bold() {
+		t.Errorf("headings = %+v, %+v", lines[0], lines[2])
+	}
+	if lines[5].X() != 18 || lines[7].X() != 36 {
+		t.Errorf("list indents = %v, %v, want 18, 36", lines[5].X(), lines[7].X())
+	}
+	if len(doc.Links) != 1 || doc.Links[0].URI != "https://github.com/jsmith" || doc.Links[0].Text != "GitHub" {
+		t.Errorf("links = %+v", doc.Links)
+	}
+
+	wantMeta := map[string]string{
+		models.MetaTitle:     "Jane Smith – Resume",
+		models.MetaAuthor:    "Jane Smith",
+		models.MetaLanguage:  "en-US",
+		models.MetaExtractor: "html",
+	}
+	for key, value := range wantMeta {
+		if doc.Metadata[key] != value {
+			t.Errorf("Metadata[%s] = %q, want %q", key, doc.Metadata[key], value)
+		}
+	}
+}
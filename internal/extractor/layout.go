@@ -0,0 +1,196 @@
+package extractor
+
+import (
+	"math"
+	"resumeparser/internal/models"
+	"resumeparser/internal/pdf"
+	"sort"
+	"strings"
+)
+
+// pageLayout splits the page into reading regions, groups each region's
+// text into lines sorted top to bottom and left to right, and merges
+// adjacent text drawn with the same font into runs.
+func pageLayout(page *pdf.Page, content []pdf.Text) models.Page {
+	box := page.MediaBox
+	result := models.Page{
+		Number: page.Number,
+		Width:  box.X1 - box.X0,
+		Height: box.Y1 - box.Y0,
+	}
+
+	items := make([]pdf.Text, 0, len(content))
+	for _, t := range content {
+		if strings.TrimSpace(t.S) != "" {
+			items = append(items, t)
+		}
+	}
+
+	order := 0
+	for _, reg := range splitColumns(items) {
+		for _, group := range groupLines(reg.items) {
+			line := buildLine(group, box, &order)
+			line.Column = reg.column
+			result.Lines = append(result.Lines, line)
+		}
+	}
+	return result
+}
+
+// buildLine converts one baseline group into runs, numbering them from order
+func buildLine(group []pdf.Text, box pdf.Rect, order *int) models.TextLine {
+	var line models.TextLine
+	var prev *pdf.Text
+	for i := range group {
+		t := &group[i]
+		if prev != nil {
+			// Skip text drawn twice to simulate bold
+			if t.S == prev.S && math.Abs(t.X-prev.X) < 1 {
+				continue
+			}
+			gap := t.X - (prev.X + prev.W)
+			last := &line.Runs[len(line.Runs)-1]
+			if t.Font == prev.Font && t.FontSize == prev.FontSize && gap < t.FontSize {
+				if gap > t.FontSize*0.15 && !strings.HasSuffix(last.Text, " ") && !strings.HasPrefix(t.S, " ") {
+					last.Text += " "
+				}
+				last.Text += t.S
+				last.Width = t.X + t.W - box.X0 - last.X
+				prev = t
+				continue
+			}
+		}
+
+		line.Runs = append(line.Runs, models.TextRun{
+			Text:     t.S,
+			X:        t.X - box.X0,
+			Y:        box.Y1 - t.Y - t.FontSize*0.8,
+			Width:    t.W,
+			Height:   t.FontSize,
+			FontName: t.Font.BaseFont,
+			FontSize: math.Round(t.FontSize*100) / 100,
+			Bold:     t.Font.Bold,
+			Italic:   t.Font.Italic,
+			Order:    *order,
+		})
+		*order++
+		prev = t
+	}
+	return line
+}
+
+// groupLines clusters text by baseline, top to bottom, with each line's
+// text sorted left to right
+func groupLines(items []pdf.Text) [][]pdf.Text {
+	sorted := append([]pdf.Text(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Y > sorted[j].Y
+	})
+
+	var groups [][]pdf.Text
+	for _, t := range sorted {
+		if n := len(groups); n > 0 {
+			first := groups[n-1][0]
+			tolerance := math.Max(math.Min(first.FontSize, t.FontSize)*0.5, 1)
+			if math.Abs(first.Y-t.Y) <= tolerance {
+				groups[n-1] = append(groups[n-1], t)
+				continue
+			}
+		}
+		groups = append(groups, []pdf.Text{t})
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].X < group[j].X
+		})
+	}
+	return groups
+}
+
+// textLayout wraps plain text lines, such as PDFBox output, as a document
+// without geometry. Form feeds start a new page.
+func textLayout(text string) *models.Document {
+	doc := &models.Document{}
+	for i, pageText := range strings.Split(text, "\f") {
+		page := models.Page{Number: i + 1}
+		for _, line := range strings.Split(pageText, "\n") {
+			line = strings.TrimRight(line, "\r")
+			page.Lines = append(page.Lines, models.TextLine{
+				Runs: []models.TextRun{{Text: line, Order: len(page.Lines)}},
+			})
+		}
+		// Drop the empty line left by a trailing newline
+		if n := len(page.Lines); n > 0 && page.Lines[n-1].Runs[0].Text == "" {
+			page.Lines = page.Lines[:n-1]
+		}
+		doc.Pages = append(doc.Pages, page)
+	}
+	return doc
+}
+
+// pageLinks converts the page's URI annotations, taking the anchor text
+// from the characters drawn inside each link's area
+func pageLinks(page *pdf.Page, content []pdf.Text) []models.Link {
+	box := page.MediaBox
+	var links []models.Link
+	for _, l := range page.Links() {
+		links = append(links, models.Link{
+			URI:    l.URI,
+			Text:   anchorText(content, l.Rect),
+			Page:   page.Number,
+			X:      l.Rect.X0 - box.X0,
+			Y:      box.Y1 - l.Rect.Y1,
+			Width:  l.Rect.X1 - l.Rect.X0,
+			Height: l.Rect.Y1 - l.Rect.Y0,
+		})
+	}
+	return links
+}
+
+// anchorText returns the text whose baseline lies within rect. Characters
+// are assumed to share a text item's width evenly, so links covering part
+// of a longer item only pick up their own words.
+func anchorText(content []pdf.Text, rect pdf.Rect) string {
+	var inside []pdf.Text
+	for _, t := range content {
+		slack := t.FontSize * 0.3
+		if t.Y < rect.Y0-slack || t.Y > rect.Y1 {
+			continue
+		}
+		chars := []rune(t.S)
+		if len(chars) == 0 {
+			continue
+		}
+		step := t.W / float64(len(chars))
+		first, last := -1, -1
+		for i := range chars {
+			mid := t.X + step*(float64(i)+0.5)
+			if mid >= rect.X0 && mid <= rect.X1 {
+				if first < 0 {
+					first = i
+				}
+				last = i
+			}
+		}
+		if first >= 0 {
+			t.S = string(chars[first : last+1])
+			t.X += step * float64(first)
+			t.W = step * float64(last+1-first)
+			inside = append(inside, t)
+		}
+	}
+
+	var parts []string
+	for _, group := range groupLines(inside) {
+		var b strings.Builder
+		for i, t := range group {
+			if i > 0 && t.X-(group[i-1].X+group[i-1].W) > t.FontSize*0.15 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(t.S)
+		}
+		parts = append(parts, strings.TrimSpace(b.String()))
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
@@ -0,0 +1,187 @@
+package extractor
+
+import (
+	"resumeparser/internal/pdf"
+	"testing"
+)
+
+func TestPageLayout(t *testing.T) {
+	regular := &pdf.Font{BaseFont: "Helvetica"}
+	bold := &pdf.Font{BaseFont: "Helvetica-Bold", Bold: true}
+	page := &pdf.Page{Number: 1, MediaBox: pdf.Rect{X1: 612, Y1: 792}}
+
+	content := []pdf.Text{
+		{S: "Acme", X: 72, Y: 700, W: 30, FontSize: 10, Font: regular},
+		{S: "EXPERIENCE", X: 72, Y: 720, W: 80, FontSize: 14, Font: bold},
+		{S: "Corp", X: 105, Y: 700.4, W: 25, FontSize: 10, Font: regular},
+		{S: "2020", X: 400, Y: 700, W: 25, FontSize: 10, Font: regular},
+	}
+
+	got := pageLayout(page, content)
+	if len(got.Lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(got.Lines))
+	}
+
+	header := got.Lines[0]
+	if header.Text() != "EXPERIENCE" || !header.Bold() || header.FontSize() != 14 {
+		t.Errorf("header = %q bold=%v size=%v", header.Text(), header.Bold(), header.FontSize())
+	}
+	if header.Runs[0].Y < 0 || header.Runs[0].Y > 792-720 {
+		t.Errorf("header top = %v, want measured from the top of the page", header.Runs[0].Y)
+	}
+
+	body := got.Lines[1]
+	if len(body.Runs) != 2 {
+		t.Fatalf("got %d runs, want adjacent text merged into 2", len(body.Runs))
+	}
+	if body.Text() != "Acme Corp 2020" {
+		t.Errorf("body = %q, want %q", body.Text(), "Acme Corp 2020")
+	}
+	if body.Runs[1].Order <= body.Runs[0].Order || body.Runs[0].Order <= header.Runs[0].Order {
+		t.Errorf("runs are not in reading order")
+	}
+}
+
+func TestTextLayout(t *testing.T) {
+	doc := textLayout("JOHN DOE\nEXPERIENCE\n\fSKILLS\n")
+	if len(doc.Pages) != 2 || len(doc.Pages[0].Lines) != 2 || len(doc.Pages[1].Lines) != 1 {
+		t.Fatalf("unexpected layout: %+v", doc)
+	}
+	if got := doc.Text(); got != "JOHN DOE\nEXPERIENCE\n\fSKILLS\n" {
+		t.Errorf("Text() = %q", got)
+	}
+}
+
+func TestSplitColumns(t *testing.T) {
+	font := &pdf.Font{BaseFont: "Helvetica"}
+	text := func(s string, x, y float64) pdf.Text {
+		return pdf.Text{S: s, X: x, Y: y, W: float64(len(s)) * 5, FontSize: 10, Font: font}
+	}
+
+	t.Run("sidebar layout", func(t *testing.T) {
+		items := []pdf.Text{
+			text("Jane Smith, jane@example.com, Berlin", 40, 750),
+			text("SKILLS", 40, 700),
+			text("Go, Rust, Python", 40, 686),
+			text("PostgreSQL, Redis", 40, 672),
+			text("EXPERIENCE", 230, 700),
+			text("Initech GmbH, Berlin", 230, 686),
+			text("Backend Engineer 2021", 230, 672),
+			text("Designed billing APIs", 230, 658),
+		}
+		regions := splitColumns(items)
+		if len(regions) != 3 {
+			t.Fatalf("got %d regions, want 3", len(regions))
+		}
+		wantColumns := []int{0, 1, 2}
+		wantFirst := []string{"Jane Smith, jane@example.com, Berlin", "SKILLS", "EXPERIENCE"}
+		for i, reg := range regions {
+			if reg.column != wantColumns[i] || reg.items[0].S != wantFirst[i] {
+				t.Errorf("region %d = column %d starting %q, want column %d starting %q",
+					i, reg.column, reg.items[0].S, wantColumns[i], wantFirst[i])
+			}
+		}
+	})
+
+	t.Run("widest sidebar line in the middle", func(t *testing.T) {
+		items := []pdf.Text{
+			text("SKILLS", 40, 700),
+			text("Go, Rust", 40, 686),
+			text("LANGUAGES", 40, 658),
+			text("German, English, French", 40, 644),
+			text("INTERESTS", 40, 616),
+			text("Hiking", 40, 602),
+			text("EXPERIENCE", 200, 700),
+			text("Acme Corp, Berlin", 200, 686),
+			text("Backend Engineer 2021", 200, 672),
+			text("Designed billing APIs for the platform", 200, 658),
+			text("Globex, Munich", 200, 630),
+			text("Engineer 2018", 200, 616),
+			text("Wrote payment services", 200, 602),
+		}
+		regions := splitColumns(items)
+		if len(regions) != 2 || regions[0].column != 1 || len(regions[0].items) != 6 || regions[1].column != 2 {
+			t.Errorf("got %+v, want the sidebar and the main column", regions)
+		}
+	})
+
+	t.Run("narrow sidebar", func(t *testing.T) {
+		items := []pdf.Text{
+			text("SKILLS", 40, 700),
+			text("Go", 40, 686),
+			text("Rust", 40, 672),
+			text("EXPERIENCE", 120, 700),
+			text("Acme Corp, Berlin", 120, 686),
+			text("Backend Engineer 2021", 120, 672),
+			text("Designed billing APIs for the platform team", 120, 658),
+			text("Globex, Munich", 120, 630),
+			text("Engineer 2018", 120, 616),
+			text("Wrote payment services for every customer", 120, 602),
+		}
+		regions := splitColumns(items)
+		if len(regions) != 2 || regions[0].column != 1 || len(regions[0].items) != 3 {
+			t.Errorf("got %+v, want the sidebar and the main column", regions)
+		}
+	})
+
+	t.Run("dates beside entries", func(t *testing.T) {
+		items := []pdf.Text{
+			text("2020-2023", 40, 700),
+			text("Backend Engineer at Acme Corp, Berlin", 120, 700),
+			text("Designed billing APIs for the platform team", 120, 686),
+			text("2018-2020", 40, 658),
+			text("Engineer at Globex, Munich", 120, 658),
+			text("Wrote payment services for every customer", 120, 644),
+			text("2014-2018", 40, 616),
+			text("B.Sc. Informatics, TU Munich", 120, 616),
+		}
+		if regions := splitColumns(items); len(regions) != 1 {
+			t.Errorf("got %d regions, want dates kept with their lines", len(regions))
+		}
+	})
+
+	t.Run("right-aligned dates", func(t *testing.T) {
+		items := []pdf.Text{
+			text("Acme Corp, San Francisco", 72, 700),
+			text("Jan 2020", 450, 700),
+			text("Senior Software Engineer", 72, 686),
+			text("Built distributed systems", 80, 672),
+			text("Globex Inc, New York", 72, 640),
+			text("Jun 2017", 450, 640),
+			text("Software Engineer", 72, 626),
+			text("Wrote payment services", 80, 612),
+			text("State University", 72, 580),
+			text("Sep 2013", 450, 580),
+		}
+		if regions := splitColumns(items); len(regions) != 1 {
+			t.Errorf("got %d regions, want dates kept with their lines", len(regions))
+		}
+	})
+}
+
+func TestAnchorText(t *testing.T) {
+	font := &pdf.Font{BaseFont: "Helvetica"}
+	content := []pdf.Text{
+		// "GitHub | LinkedIn" drawn as one item, 17 characters 3pt apart
+		{S: "GitHub | LinkedIn", X: 72, Y: 700, W: 51, FontSize: 10, Font: font},
+		{S: "Projects", X: 72, Y: 680, W: 40, FontSize: 10, Font: font},
+	}
+
+	tests := []struct {
+		name string
+		rect pdf.Rect
+		want string
+	}{
+		{name: "first word", rect: pdf.Rect{X0: 71, Y0: 698, X1: 90, Y1: 710}, want: "GitHub"},
+		{name: "last word", rect: pdf.Rect{X0: 99, Y0: 698, X1: 124, Y1: 710}, want: "LinkedIn"},
+		{name: "other line", rect: pdf.Rect{X0: 72, Y0: 678, X1: 112, Y1: 690}, want: "Projects"},
+		{name: "empty area", rect: pdf.Rect{X0: 300, Y0: 698, X1: 320, Y1: 710}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := anchorText(content, tt.rect); got != tt.want {
+				t.Errorf("anchorText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
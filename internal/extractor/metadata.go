@@ -0,0 +1,85 @@
+package extractor
+
+import (
+	"archive/zip"
+	"resumeparser/internal/models"
+	"resumeparser/internal/pdf"
+	"strconv"
+	"time"
+)
+
+// Backend names recorded under models.MetaExtractor
+const (
+	backendNative   = "native"
+	backendPDFBox   = "pdfbox"
+	backendDocx     = "docx"
+	backendRTF      = "rtf"
+	backendODT      = "odt"
+	backendHTML     = "html"
+	backendText     = "text"
+	backendMarkdown = "markdown"
+)
+
+// pdfMetadata records the information dictionary and declared language
+func pdfMetadata(doc *models.Document, r *pdf.Reader) {
+	info := r.Info()
+	doc.SetMeta(models.MetaTitle, info.Title)
+	doc.SetMeta(models.MetaAuthor, info.Author)
+	doc.SetMeta(models.MetaCreator, info.Creator)
+	doc.SetMeta(models.MetaProducer, info.Producer)
+	doc.SetMeta(models.MetaCreated, formatTime(info.Created))
+	doc.SetMeta(models.MetaModified, formatTime(info.Modified))
+	doc.SetMeta(models.MetaLanguage, r.Lang())
+}
+
+// pdfFileMetadata reads metadata for backends that only return text,
+// such as PDFBox, along with the page count, which their text may not
+// show. Files the Go reader can't open are skipped.
+func pdfFileMetadata(doc *models.Document, data []byte, password string) {
+	if r, err := pdf.OpenWithPassword(data, password); err == nil {
+		pdfMetadata(doc, r)
+		doc.SetMeta(models.MetaPages, strconv.Itoa(r.NumPage()))
+	}
+}
+
+// officeMetadata records the core and extended properties of an OOXML
+// package (docProps/core.xml and docProps/app.xml)
+func officeMetadata(doc *models.Document, zr *zip.Reader) {
+	if core, err := readZipXML(zr, "docProps/core.xml"); err == nil {
+		text := func(local string) string {
+			if n := core.find(local); n != nil {
+				return n.textContent()
+			}
+			return ""
+		}
+		doc.SetMeta(models.MetaTitle, text("title"))
+		doc.SetMeta(models.MetaAuthor, text("creator"))
+		doc.SetMeta(models.MetaLanguage, text("language"))
+		doc.SetMeta(models.MetaCreated, normalizeTime(text("created")))
+		doc.SetMeta(models.MetaModified, normalizeTime(text("modified")))
+	}
+	if app, err := readZipXML(zr, "docProps/app.xml"); err == nil {
+		if n := app.find("Application"); n != nil {
+			doc.SetMeta(models.MetaCreator, n.textContent())
+			doc.SetMeta(models.MetaProducer, n.textContent())
+		}
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// normalizeTime reformats W3C date-times as RFC 3339, keeping values it
+// can't parse as they are
+func normalizeTime(s string) string {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return formatTime(t)
+		}
+	}
+	return s
+}
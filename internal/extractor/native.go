@@ -0,0 +1,67 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"resumeparser/internal/models"
+	"resumeparser/internal/pdf"
+)
+
+// nativeExtractor reads PDF files directly in Go, so no Java runtime is needed
+type nativeExtractor struct {
+	opts options
+}
+
+// NewNative creates a LayoutExtractor backed by the pure Go PDF reader
+func NewNative(opts ...Option) LayoutExtractor {
+	return &nativeExtractor{opts: newOptions(opts)}
+}
+
+func (e *nativeExtractor) Extract(ctx context.Context, path string) (string, error) {
+	doc, err := e.ExtractLayout(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+func (e *nativeExtractor) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	return e.ExtractLayoutBytes(ctx, data)
+}
+
+func (e *nativeExtractor) ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error) {
+	r, err := pdf.OpenWithPassword(data, e.opts.password)
+	if err != nil {
+		if encErr := encryptionError(err, e.opts.password); encErr != nil {
+			return nil, encErr
+		}
+		return nil, fmt.Errorf("PDF extraction failed: %w", err)
+	}
+
+	doc := &models.Document{}
+	doc.SetMeta(models.MetaExtractor, backendNative)
+	pdfMetadata(doc, r)
+	for i := 1; i <= r.NumPage(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page := r.Page(i)
+		content, err := page.Content()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page %d: %w", i, err)
+		}
+		doc.Pages = append(doc.Pages, pageLayout(page, content))
+		doc.Links = append(doc.Links, pageLinks(page, content)...)
+	}
+
+	if err := applyOCR(ctx, doc, r, e.opts.ocr); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
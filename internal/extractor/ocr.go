@@ -0,0 +1,203 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"resumeparser/internal/models"
+	"resumeparser/internal/pdf"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// OCR recognizes the text of a page image
+type OCR interface {
+	Recognize(ctx context.Context, img PageImage) (string, error)
+}
+
+// PageImage is an encoded image taken from a page that has no text layer
+type PageImage struct {
+	Page   int    // page number, starting at 1
+	Format string // "png", "jpeg", "jp2" or "tiff"
+	Data   []byte
+}
+
+// CommandOCR runs an external program for every image and reads the
+// recognized text from its standard output. The placeholder {image} in
+// Args is replaced by the path of a temporary file holding the image.
+type CommandOCR struct {
+	Args []string
+}
+
+// NewTesseract runs a locally installed tesseract, optionally restricted
+// to a language such as "eng" or "deu+eng"
+func NewTesseract(lang string) *CommandOCR {
+	args := []string{"tesseract", "{image}", "stdout"}
+	if lang != "" {
+		args = append(args, "-l", lang)
+	}
+	return &CommandOCR{Args: args}
+}
+
+func (c *CommandOCR) Recognize(ctx context.Context, img PageImage) (string, error) {
+	if len(c.Args) == 0 {
+		return "", fmt.Errorf("OCR command not set")
+	}
+
+	f, err := os.CreateTemp("", "ocr-page-*."+img.Format)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp image file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(img.Data); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write temp image file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temp image file: %w", err)
+	}
+
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = strings.ReplaceAll(arg, "{image}", f.Name())
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("OCR failed on page %d: %w\nDetailed error: %s\nCommand details: %v",
+			img.Page,
+			err,
+			stderr.String(),
+			cmd.Args,
+		)
+	}
+	return stdout.String(), nil
+}
+
+// minPageText is the number of non-space characters below which a page
+// is treated as a scan and sent to OCR
+const minPageText = 20
+
+// minImageArea is the fraction of the page an image has to cover to be
+// worth recognizing, which skips logos and icons
+const minImageArea = 0.1
+
+// applyOCR replaces the lines of pages without a usable text layer with
+// the text recognized in their images. Scanned pages are usually a single
+// image covering the page, so the embedded images are recognized as they
+// are rather than rendering the page. Scanned pages whose images the PDF
+// reader can't decode, such as JBIG2, are listed under ocr_skipped_pages.
+func applyOCR(ctx context.Context, doc *models.Document, r *pdf.Reader, ocr OCR) error {
+	if ocr == nil {
+		return nil
+	}
+
+	var ocrPages, skipped []string
+	for i := range doc.Pages {
+		if countText(doc.Pages[i].Text()) >= minPageText || i >= r.NumPage() {
+			continue
+		}
+		page := r.Page(i + 1)
+		images, err := page.Images()
+		if err != nil {
+			return fmt.Errorf("failed to read images on page %d: %w", page.Number, err)
+		}
+		images = pageScans(page, images)
+		if len(images) == 0 {
+			continue
+		}
+
+		var texts []string
+		unreadable := false
+		for _, img := range images {
+			data, format, err := img.Encode()
+			if err != nil {
+				unreadable = true
+				continue
+			}
+			text, err := ocr.Recognize(ctx, PageImage{Page: page.Number, Format: format, Data: data})
+			if err != nil {
+				return err
+			}
+			texts = append(texts, strings.ReplaceAll(text, "\f", "\n"))
+		}
+		if countText(strings.Join(texts, "")) == 0 {
+			if unreadable {
+				skipped = append(skipped, strconv.Itoa(page.Number))
+			}
+			continue
+		}
+
+		lines := textLayout(strings.Join(texts, "\n")).Pages[0].Lines
+		doc.Pages[i].Lines = lines
+		ocrPages = append(ocrPages, strconv.Itoa(page.Number))
+	}
+
+	if len(ocrPages) > 0 {
+		doc.SetMeta(models.MetaOCR, "true")
+		doc.SetMeta(models.MetaOCRPages, strings.Join(ocrPages, ","))
+	}
+	doc.SetMeta(models.MetaOCRSkippedPages, strings.Join(skipped, ","))
+	return nil
+}
+
+// pdfOCR runs applyOCR for backends that only return text, opening the
+// document with the Go reader to find its images. Documents whose pages
+// all have text are left alone without being opened.
+func pdfOCR(ctx context.Context, doc *models.Document, data []byte, password string, ocr OCR) error {
+	if ocr == nil || !hasScannedPage(doc) {
+		return nil
+	}
+	r, err := pdf.OpenWithPassword(data, password)
+	if err != nil {
+		return fmt.Errorf("failed to open PDF for OCR: %w", err)
+	}
+	// PDFBox may report fewer pages when trailing pages are empty
+	for len(doc.Pages) < r.NumPage() {
+		doc.Pages = append(doc.Pages, models.Page{Number: len(doc.Pages) + 1})
+	}
+	return applyOCR(ctx, doc, r, ocr)
+}
+
+// pageScans keeps the images large enough to hold page text, ordered top
+// to bottom
+func pageScans(page *pdf.Page, images []pdf.Image) []pdf.Image {
+	box := page.MediaBox
+	pageArea := (box.X1 - box.X0) * (box.Y1 - box.Y0)
+	var scans []pdf.Image
+	for _, img := range images {
+		area := (img.Rect.X1 - img.Rect.X0) * (img.Rect.Y1 - img.Rect.Y0)
+		if pageArea <= 0 || area >= pageArea*minImageArea {
+			scans = append(scans, img)
+		}
+	}
+	sort.SliceStable(scans, func(i, j int) bool {
+		return scans[i].Rect.Y1 > scans[j].Rect.Y1
+	})
+	return scans
+}
+
+func hasScannedPage(doc *models.Document) bool {
+	for _, page := range doc.Pages {
+		if countText(page.Text()) < minPageText {
+			return true
+		}
+	}
+	return len(doc.Pages) == 0
+}
+
+func countText(s string) int {
+	n := 0
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,124 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"resumeparser/internal/models"
+	"resumeparser/internal/pdf/pdftest"
+	"strings"
+	"testing"
+)
+
+// scannedPDF builds a two-page PDF: a page with a text layer followed by a
+// page holding only a full-page grayscale image
+func scannedPDF() []byte {
+	return scannedPDFImage("<< /Type /XObject /Subtype /Image /Width 2 /Height 2 /ColorSpace /DeviceGray /BitsPerComponent 8 /Length 4 >>\nstream\n\xff\x00\x00\xff\nendstream")
+}
+
+// scannedPDFImage is scannedPDF with the given image object on page 2
+func scannedPDFImage(image string) []byte {
+	text := "BT /F1 12 Tf 72 720 Td (John Doe - Software Engineer, Berlin) Tj ET"
+	scan := "q 612 0 0 792 0 0 cm /Im1 Do Q"
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 7 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 6 0 R /Resources << /XObject << /Im1 8 0 R >> >> >>",
+		pdftest.Stream(text),
+		pdftest.Stream(scan),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		image,
+	}
+	return pdftest.Build(objects)
+}
+
+type stubOCR struct {
+	images []PageImage
+	text   string
+	err    error
+}
+
+func (s *stubOCR) Recognize(ctx context.Context, img PageImage) (string, error) {
+	s.images = append(s.images, img)
+	return s.text, s.err
+}
+
+func TestOCR(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("scanned page", func(t *testing.T) {
+		ocr := &stubOCR{text: "EXPERIENCE\nAcme Corp\f"}
+		doc, err := NewNative(WithOCR(ocr)).(BytesExtractor).ExtractLayoutBytes(ctx, scannedPDF())
+		if err != nil {
+			t.Fatalf("ExtractLayoutBytes() error = %v", err)
+		}
+
+		if len(ocr.images) != 1 || ocr.images[0].Page != 2 || ocr.images[0].Format != "png" {
+			t.Fatalf("recognized images = %+v, want one png from page 2", ocr.images)
+		}
+		if !bytes.HasPrefix(ocr.images[0].Data, []byte("\x89PNG")) {
+			t.Errorf("image data is not a PNG")
+		}
+		if len(doc.Pages) != 2 || !strings.Contains(doc.Pages[0].Text(), "John Doe") {
+			t.Fatalf("pages = %+v", doc.Pages)
+		}
+		if got := doc.Pages[1].Text(); got != "EXPERIENCE\nAcme Corp\n" {
+			t.Errorf("page 2 text = %q", got)
+		}
+		if doc.Metadata[models.MetaOCR] != "true" || doc.Metadata[models.MetaOCRPages] != "2" {
+			t.Errorf("Metadata = %v", doc.Metadata)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		doc, err := NewNative().(BytesExtractor).ExtractLayoutBytes(ctx, scannedPDF())
+		if err != nil {
+			t.Fatalf("ExtractLayoutBytes() error = %v", err)
+		}
+		if _, ok := doc.Metadata[models.MetaOCR]; ok {
+			t.Errorf("Metadata = %v, want no OCR flag", doc.Metadata)
+		}
+	})
+
+	t.Run("fax scan", func(t *testing.T) {
+		ocr := &stubOCR{text: "EXPERIENCE\nAcme Corp"}
+		data := scannedPDFImage("<< /Type /XObject /Subtype /Image /Width 1728 /Height 2200 /ImageMask true /Filter /CCITTFaxDecode /DecodeParms << /K -1 /Columns 1728 >> /Length 4 >>\nstream\n\x26\xa0\x00\x10\nendstream")
+		doc, err := NewNative(WithOCR(ocr)).(BytesExtractor).ExtractLayoutBytes(ctx, data)
+		if err != nil {
+			t.Fatalf("ExtractLayoutBytes() error = %v", err)
+		}
+		if len(ocr.images) != 1 || ocr.images[0].Format != "tiff" || !bytes.HasPrefix(ocr.images[0].Data, []byte("II*\x00")) {
+			t.Fatalf("recognized images = %+v, want one tiff", ocr.images)
+		}
+		if !bytes.HasSuffix(ocr.images[0].Data, []byte("\x26\xa0\x00\x10")) {
+			t.Errorf("tiff doesn't hold the fax data")
+		}
+		if doc.Metadata[models.MetaOCRPages] != "2" {
+			t.Errorf("Metadata = %v", doc.Metadata)
+		}
+	})
+
+	t.Run("unsupported image", func(t *testing.T) {
+		ocr := &stubOCR{text: "EXPERIENCE"}
+		data := scannedPDFImage("<< /Type /XObject /Subtype /Image /Width 1728 /Height 2200 /ImageMask true /Filter /JBIG2Decode /Length 4 >>\nstream\n\x00\x00\x00\x00\nendstream")
+		doc, err := NewNative(WithOCR(ocr)).(BytesExtractor).ExtractLayoutBytes(ctx, data)
+		if err != nil {
+			t.Fatalf("ExtractLayoutBytes() error = %v", err)
+		}
+		if len(ocr.images) != 0 {
+			t.Errorf("recognized images = %+v, want none", ocr.images)
+		}
+		if doc.Metadata[models.MetaOCRSkippedPages] != "2" || doc.Metadata[models.MetaOCR] != "" {
+			t.Errorf("Metadata = %v, want page 2 skipped", doc.Metadata)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ocr := &stubOCR{err: fmt.Errorf("engine crashed")}
+		_, err := NewNative(WithOCR(ocr)).(BytesExtractor).ExtractLayoutBytes(ctx, scannedPDF())
+		if err == nil || !strings.Contains(err.Error(), "engine crashed") {
+			t.Errorf("ExtractLayoutBytes() error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,404 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"resumeparser/internal/models"
+	"strconv"
+	"strings"
+)
+
+// odtExtractor reads OpenDocument text files written by LibreOffice and
+// other ODF applications
+type odtExtractor struct{}
+
+// NewODT creates a LayoutExtractor for .odt files
+func NewODT() LayoutExtractor {
+	return &odtExtractor{}
+}
+
+func (e *odtExtractor) Extract(ctx context.Context, path string) (string, error) {
+	doc, err := e.ExtractLayout(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+func (e *odtExtractor) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ODT: %w", err)
+	}
+	defer zr.Close()
+
+	return readODT(ctx, &zr.Reader)
+}
+
+func (e *odtExtractor) ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ODT: %w", err)
+	}
+	return readODT(ctx, zr)
+}
+
+type odtStyle struct {
+	parent    string
+	outline   int // default outline level, 0 for body text
+	bold      *bool
+	italic    *bool
+	size      float64
+	pageBreak bool // paragraph starts on a new page
+	listStyle string
+}
+
+type odtListLevel struct {
+	bullet string // bullet character, empty for numbered levels
+	format string // number format such as "1", "a" or "I"
+	prefix string
+	suffix string
+}
+
+// odtReader walks content.xml and builds the layout document
+type odtReader struct {
+	ctx        context.Context
+	styles     map[string]odtStyle
+	listStyles map[string]map[int]odtListLevel
+	w          *flowWriter
+	err        error
+}
+
+func readODT(ctx context.Context, zr *zip.Reader) (*models.Document, error) {
+	content, err := readZipXML(zr, "content.xml")
+	if err != nil {
+		return nil, fmt.Errorf("invalid ODT: %w", err)
+	}
+
+	o := &odtReader{
+		ctx:        ctx,
+		styles:     make(map[string]odtStyle),
+		listStyles: make(map[string]map[int]odtListLevel),
+		w:          newFlowWriter(),
+	}
+	if styles, err := readZipXML(zr, "styles.xml"); err == nil {
+		o.loadStyles(styles)
+	}
+	o.loadStyles(content)
+
+	if text := content.find("body").child("text"); text != nil {
+		o.walkBlocks(o.w, text, nil)
+	}
+	if o.err != nil {
+		return nil, o.err
+	}
+
+	doc := o.w.finish()
+	doc.SetMeta(models.MetaExtractor, backendODT)
+	if meta, err := readZipXML(zr, "meta.xml"); err == nil {
+		odfMetadata(doc, meta)
+	}
+	return doc, nil
+}
+
+// odfMetadata records the properties in an ODF meta.xml part
+func odfMetadata(doc *models.Document, meta *xmlNode) {
+	text := func(local string) string {
+		if n := meta.find(local); n != nil {
+			return n.textContent()
+		}
+		return ""
+	}
+	doc.SetMeta(models.MetaTitle, text("title"))
+	// dc:creator is whoever saved the file last, so prefer the initial creator
+	doc.SetMeta(models.MetaAuthor, text("creator"))
+	doc.SetMeta(models.MetaAuthor, text("initial-creator"))
+	doc.SetMeta(models.MetaLanguage, text("language"))
+	doc.SetMeta(models.MetaCreated, normalizeTime(text("creation-date")))
+	doc.SetMeta(models.MetaModified, normalizeTime(text("date")))
+	// The generator is e.g. "LibreOffice/7.6.4.1$Linux_X86_64 LibreOffice_project/..."
+	if generator, _, _ := strings.Cut(text("generator"), "$"); generator != "" {
+		doc.SetMeta(models.MetaCreator, generator)
+		doc.SetMeta(models.MetaProducer, generator)
+	}
+}
+
+// loadStyles reads the paragraph, text and list styles defined in a part
+func (o *odtReader) loadStyles(root *xmlNode) {
+	var walk func(n *xmlNode)
+	walk = func(n *xmlNode) {
+		for _, c := range n.children {
+			switch c.name.Local {
+			case "style":
+				style := odtStyle{
+					parent:    c.attr("parent-style-name"),
+					listStyle: c.attr("list-style-name"),
+				}
+				style.outline, _ = strconv.Atoi(c.attr("default-outline-level"))
+				if tp := c.child("text-properties"); tp != nil {
+					if w := tp.attr("font-weight"); w != "" {
+						weight, _ := strconv.Atoi(w)
+						bold := w == "bold" || weight >= 600
+						style.bold = &bold
+					}
+					if s := tp.attr("font-style"); s != "" {
+						italic := s == "italic" || s == "oblique"
+						style.italic = &italic
+					}
+					style.size = odfLength(tp.attr("font-size"))
+				}
+				if pp := c.child("paragraph-properties"); pp != nil {
+					style.pageBreak = pp.attr("break-before") == "page"
+				}
+				o.styles[c.attr("name")] = style
+			case "list-style":
+				levels := make(map[int]odtListLevel)
+				for _, lvl := range c.children {
+					level, _ := strconv.Atoi(lvl.attr("level"))
+					switch lvl.name.Local {
+					case "list-level-style-bullet":
+						levels[level] = odtListLevel{bullet: lvl.attr("bullet-char")}
+					case "list-level-style-number":
+						levels[level] = odtListLevel{
+							format: lvl.attr("num-format"),
+							prefix: lvl.attr("num-prefix"),
+							suffix: lvl.attr("num-suffix"),
+						}
+					}
+				}
+				o.listStyles[c.attr("name")] = levels
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(root)
+}
+
+// style resolves a style with the properties it inherits from its parents
+func (o *odtReader) style(name string) odtStyle {
+	var result odtStyle
+	for depth := 0; name != "" && depth < 16; depth++ {
+		s, ok := o.styles[name]
+		if !ok {
+			break
+		}
+		if result.outline == 0 {
+			result.outline = s.outline
+		}
+		if result.bold == nil {
+			result.bold = s.bold
+		}
+		if result.italic == nil {
+			result.italic = s.italic
+		}
+		if result.size == 0 {
+			result.size = s.size
+		}
+		if result.listStyle == "" {
+			result.listStyle = s.listStyle
+		}
+		if depth == 0 {
+			result.pageBreak = s.pageBreak
+		}
+		name = s.parent
+	}
+	return result
+}
+
+func (s odtStyle) runStyle() runStyle {
+	var rs runStyle
+	if s.bold != nil {
+		rs.bold = *s.bold
+	}
+	if s.italic != nil {
+		rs.italic = *s.italic
+	}
+	rs.size = s.size
+	return rs
+}
+
+// odtList tracks a text:list being walked
+type odtList struct {
+	style   string
+	level   int // 1 for the outermost list
+	counter int
+	marked  bool // the current item's marker has been written
+}
+
+// walkBlocks emits the paragraphs, lists and tables inside a container
+func (o *odtReader) walkBlocks(w *flowWriter, n *xmlNode, list *odtList) {
+	for _, c := range n.children {
+		if o.err != nil {
+			return
+		}
+		switch c.name.Local {
+		case "p", "h":
+			o.paragraph(w, c, list)
+		case "list":
+			nested := &odtList{style: c.attr("style-name"), level: 1}
+			if list != nil {
+				nested.level = list.level + 1
+				if nested.style == "" {
+					nested.style = list.style
+				}
+			}
+			if start, err := strconv.Atoi(c.attr("start-value")); err == nil {
+				nested.counter = start - 1
+			}
+			o.walkBlocks(w, c, nested)
+		case "list-item", "list-header":
+			if list != nil {
+				if c.name.Local == "list-item" {
+					list.counter++
+				}
+				list.marked = c.name.Local == "list-header"
+			}
+			o.walkBlocks(w, c, list)
+		case "table":
+			o.table(w, c)
+		case "section", "index-body":
+			o.walkBlocks(w, c, list)
+		}
+	}
+}
+
+// paragraph writes a text:p or text:h element
+func (o *odtReader) paragraph(w *flowWriter, p *xmlNode, list *odtList) {
+	if err := o.ctx.Err(); err != nil {
+		o.err = err
+		return
+	}
+	style := o.style(p.attr("style-name"))
+	if style.pageBreak && len(w.page.Lines) > 0 {
+		w.pageBreak()
+	}
+
+	if p.name.Local == "h" {
+		w.heading, _ = strconv.Atoi(p.attr("outline-level"))
+		if w.heading == 0 {
+			w.heading = 1
+		}
+	} else {
+		w.heading = style.outline
+	}
+
+	if list != nil {
+		w.indent = float64(list.level) * 18
+		if !list.marked {
+			list.marked = true
+			if marker := o.listMarker(list, style); marker != "" {
+				w.write(marker+" ", runStyle{size: style.size})
+			}
+		}
+	}
+	o.inline(w, p, style.runStyle())
+	w.endParagraph()
+}
+
+// listMarker returns the bullet or number of the current list item
+func (o *odtReader) listMarker(list *odtList, style odtStyle) string {
+	name := list.style
+	if name == "" {
+		name = style.listStyle
+	}
+	level, ok := o.listStyles[name][list.level]
+	if !ok || level.bullet != "" {
+		return "•"
+	}
+	var number string
+	switch level.format {
+	case "":
+		return ""
+	case "a":
+		number = formatListNumber(list.counter, "lowerLetter")
+	case "A":
+		number = formatListNumber(list.counter, "upperLetter")
+	case "i":
+		number = formatListNumber(list.counter, "lowerRoman")
+	case "I":
+		number = formatListNumber(list.counter, "upperRoman")
+	default:
+		number = strconv.Itoa(list.counter)
+	}
+	return level.prefix + number + level.suffix
+}
+
+// inline writes the text and spans of a paragraph
+func (o *odtReader) inline(w *flowWriter, n *xmlNode, rs runStyle) {
+	w.write(collapseSpace(n.text), rs)
+	for _, c := range n.children {
+		switch c.name.Local {
+		case "span":
+			span := rs
+			s := o.style(c.attr("style-name"))
+			if s.bold != nil {
+				span.bold = *s.bold
+			}
+			if s.italic != nil {
+				span.italic = *s.italic
+			}
+			if s.size > 0 {
+				span.size = s.size
+			}
+			o.inline(w, c, span)
+		case "a":
+			before := len(w.line.Runs)
+			o.inline(w, c, rs)
+			w.addLink(c.attr("href"), w.lineText(before))
+		case "s":
+			count, err := strconv.Atoi(c.attr("c"))
+			if err != nil || count < 1 {
+				count = 1
+			}
+			w.write(strings.Repeat(" ", count), rs)
+		case "tab":
+			w.write("\t", rs)
+		case "line-break":
+			w.write("\n", rs)
+		case "note", "annotation", "bookmark", "bookmark-start", "bookmark-end",
+			"soft-page-break", "reference-mark", "change", "tracked-changes":
+		default:
+			o.inline(w, c, rs)
+		}
+		w.write(collapseSpace(c.tail), rs)
+	}
+}
+
+// table emits each table:table-row through the writer's table handling
+func (o *odtReader) table(w *flowWriter, tbl *xmlNode) {
+	for _, c := range tbl.children {
+		switch c.name.Local {
+		case "table-row":
+			var cells []*flowWriter
+			for _, tc := range c.children {
+				if tc.name.Local != "table-cell" {
+					continue
+				}
+				cw := w.cell()
+				o.walkBlocks(cw, tc, nil)
+				cells = append(cells, cw)
+			}
+			w.tableRow(cells)
+		case "table-header-rows", "table-rows", "table-row-group":
+			o.table(w, c)
+		}
+	}
+}
+
+// odfLength converts a length such as "12pt" or "0.5in" to points
+func odfLength(s string) float64 {
+	units := map[string]float64{"pt": 1, "in": 72, "cm": 72 / 2.54, "mm": 72 / 25.4, "pc": 12, "px": 0.75}
+	for unit, factor := range units {
+		if v, ok := strings.CutSuffix(s, unit); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return 0
+			}
+			return f * factor
+		}
+	}
+	return 0
+}
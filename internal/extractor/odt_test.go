@@ -0,0 +1,153 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+const testODTContent = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+  xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+  xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+  xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+  xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"
+  xmlns:xlink="http://www.w3.org/1999/xlink">
+<office:automatic-styles>
+  <style:style style:name="T1" style:family="text"><style:text-properties fo:font-weight="bold"/></style:style>
+  <style:style style:name="P2" style:family="paragraph" style:parent-style-name="Standard"><style:paragraph-properties fo:break-before="page"/></style:style>
+  <text:list-style style:name="L2"><text:list-level-style-number text:level="1" style:num-format="1" style:num-suffix="."/></text:list-style>
+</office:automatic-styles>
+<office:body><office:text>
+  <text:p text:style-name="Title">Jane Smith</text:p>
+  <text:p>jane@example.com |<text:s/><text:a xlink:href="https://github.com/jsmith">GitHub</text:a></text:p>
+  <text:h text:style-name="Heading_20_1" text:outline-level="1">Experience</text:h>
+  <text:p><text:span text:style-name="T1">Initech</text:span>, Engineer</text:p>
+  <text:list text:style-name="L1">
+    <text:list-item><text:p>Built APIs</text:p>
+      <text:list><text:list-item><text:p>Go services</text:p></text:list-item></text:list>
+    </text:list-item>
+  </text:list>
+  <text:list text:style-name="L2">
+    <text:list-item><text:p>First</text:p></text:list-item>
+    <text:list-item><text:p>Second</text:p></text:list-item>
+  </text:list>
+  <table:table><table:table-row>
+    <table:table-cell><text:p>Python</text:p></table:table-cell>
+    <table:table-cell><text:p>Go</text:p></table:table-cell>
+  </table:table-row></table:table>
+  <text:p/>
+  <text:p text:style-name="P2">Skills</text:p>
+</office:text></office:body>
+</office:document-content>`
+
+const testODTStyles = `<office:document-styles xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+  xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+  xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+  xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0">
+<office:styles>
+  <style:style style:name="Title" style:family="paragraph"><style:text-properties fo:font-size="20pt"/></style:style>
+  <style:style style:name="Heading_20_1" style:display-name="Heading 1" style:family="paragraph" style:default-outline-level="1">
+    <style:text-properties fo:font-weight="bold" fo:font-size="14pt"/>
+  </style:style>
+  <text:list-style style:name="L1"><text:list-level-style-bullet text:level="1" text:bullet-char="•"/></text:list-style>
+</office:styles>
+</office:document-styles>`
+
+const testODTMeta = `<office:document-meta xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+  xmlns:meta="urn:oasis:names:tc:opendocument:xmlns:meta:1.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<office:meta>
+  <meta:generator>LibreOffice/7.6.4.1$Linux_X86_64 LibreOffice_project/e19e193f88cd6c0525a17fb7a176ed8e6a3e2aa1</meta:generator>
+  <meta:initial-creator>Jane Smith</meta:initial-creator>
+  <dc:creator>Recruiter</dc:creator>
+  <meta:creation-date>2024-03-01T09:30:00.123456789</meta:creation-date>
+  <dc:language>en-GB</dc:language>
+</office:meta>
+</office:document-meta>`
+
+func testODT(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(TypeODT))
+	for name, content := range map[string]string{
+		"content.xml": testODTContent,
+		"styles.xml":  testODTStyles,
+		"meta.xml":    testODTMeta,
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestODTExtractor(t *testing.T) {
+	data := testODT(t)
+	if got := Detect(data); got != TypeODT {
+		t.Fatalf("Detect() = %s, want %s", got, TypeODT)
+	}
+	doc, err := NewODT().(BytesExtractor).ExtractLayoutBytes(context.Background(), data)
+	if err != nil {
+		t.Fatalf("ExtractLayoutBytes() error = %v", err)
+	}
+
+	want := []string{
+		"Jane Smith",
+		"jane@example.com | GitHub",
+		"Experience",
+		"Initech, Engineer",
+		"• Built APIs",
+		"• Go services",
+		"1. First",
+		"2. Second",
+		"Python | Go",
+		"\f",
+		"Skills",
+	}
+	if got := docLines(doc); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+
+	lines := doc.Pages[0].Lines
+	if lines[0].FontSize() != 20 {
+		t.Errorf("title size = %v, want 20", lines[0].FontSize())
+	}
+	if lines[2].Heading != 1 || !lines[2].Bold() {
+		t.Errorf("heading line = %+v", lines[2])
+	}
+	if !lines[3].Runs[0].Bold || lines[3].Runs[1].Bold {
+		t.Errorf("span formatting = %+v", lines[3].Runs)
+	}
+	if lines[4].X() != 18 || lines[5].X() != 36 {
+		t.Errorf("list indents = %v, %v, want 18, 36", lines[4].X(), lines[5].X())
+	}
+	if len(doc.Links) != 1 || doc.Links[0].URI != "https://github.com/jsmith" || doc.Links[0].Text != "GitHub" {
+		t.Errorf("links = %+v", doc.Links)
+	}
+
+	wantMeta := map[string]string{
+		models.MetaAuthor:    "Jane Smith",
+		models.MetaCreator:   "LibreOffice/7.6.4.1",
+		models.MetaCreated:   "2024-03-01T09:30:00Z",
+		models.MetaLanguage:  "en-GB",
+		models.MetaExtractor: "odt",
+	}
+	for key, value := range wantMeta {
+		if doc.Metadata[key] != value {
+			t.Errorf("Metadata[%s] = %q, want %q", key, doc.Metadata[key], value)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package extractor
+
+import (
+	"errors"
+	"resumeparser/internal/models"
+	"resumeparser/internal/pdf"
+	"strings"
+)
+
+// Option configures an extractor
+type Option func(*options)
+
+type options struct {
+	password string
+	ocr      OCR
+}
+
+// WithPassword sets the password used to open encrypted PDFs. Documents
+// restricted only by an owner password open without one.
+func WithPassword(password string) Option {
+	return func(o *options) {
+		o.password = password
+	}
+}
+
+// WithOCR recognizes the text of scanned PDF pages, those with almost no
+// text layer, using ocr
+func WithOCR(ocr OCR) Option {
+	return func(o *options) {
+		o.ocr = ocr
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// encryptionError converts errors from opening encrypted PDFs into
+// models.EncryptedError, returning nil for unrelated errors
+func encryptionError(err error, password string) error {
+	switch {
+	case errors.Is(err, pdf.ErrPassword):
+		return &models.EncryptedError{PasswordRequired: password == "", Err: err}
+	case errors.Is(err, pdf.ErrEncrypted):
+		return &models.EncryptedError{Err: err}
+	}
+	return nil
+}
+
+// isPDFBoxPasswordError reports whether PDFBox output shows it could not
+// decrypt the document
+func isPDFBoxPasswordError(output string) bool {
+	return strings.Contains(output, "InvalidPasswordException")
+}
@@ -1,75 +1,94 @@
 package extractor
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
-
-	_ "embed"
-	"io/ioutil"
-	"os/exec"
+	"resumeparser/internal/models"
 )
 
 type PdfExtractor interface {
 	Extract(ctx context.Context, path string) (string, error)
 }
 
+// LayoutExtractor returns pages of positioned text runs. Extract is a thin
+// wrapper that flattens the layout into plain text.
+type LayoutExtractor interface {
+	PdfExtractor
+	ExtractLayout(ctx context.Context, path string) (*models.Document, error)
+}
+
 type pdfExtractor struct {
 	jarPath string
+	command []string // worker command line in place of java, for tests
+	opts    options
+}
+
+func New(opts ...Option) LayoutExtractor {
+	return &pdfExtractor{
+		jarPath: findJar(),
+		opts:    newOptions(opts),
+	}
 }
 
-func New() PdfExtractor {
-	// Look for PDFBox JAR in standard locations
+// findJar looks for the PDFBox JAR in standard locations
+func findJar() string {
 	jarPath := "assets/pdfbox-app-3.0.3.jar"
 	if _, err := os.Stat(jarPath); os.IsNotExist(err) {
 		// Try alternate location
 		jarPath = "internal/extractor/assets/pdfbox-app-3.0.3.jar"
 	}
-	return &pdfExtractor{
-		jarPath: jarPath,
-	}
+	return jarPath
 }
 
 func (e *pdfExtractor) Extract(ctx context.Context, path string) (string, error) {
-	// Create a temporary output file for text extraction
-	outputFile, err := os.CreateTemp("", "pdf-extract-*.txt")
+	doc, err := e.ExtractLayout(ctx, path)
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp output file: %w", err)
+		return "", err
 	}
-	defer os.Remove(outputFile.Name())
-	outputFile.Close()
-
-	// Prepare the Java command to extract text with comprehensive options
-	cmd := exec.CommandContext(ctx,
-		"java",
-		"-jar", e.jarPath,
-		"export:text",
-		"-i="+path,
-		"-o="+outputFile.Name(),
-		"-encoding=UTF-8",
-		"-sort",
-		"-startPage=1",
-	)
-
-	// Capture potential error output
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
+	return doc.Text(), nil
+}
 
-	// Run the command with verbose logging
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("PDF extraction failed: %w\nDetailed error: %s\nCommand details: %v",
-			err,
-			stderr.String(),
-			cmd.Args,
-		)
+// ExtractLayout runs PDFBox and wraps its sorted text output as lines.
+// PDFBox does not report positions or fonts, so runs carry text only.
+func (e *pdfExtractor) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	text, err := e.extractText(ctx, data)
+	if err != nil {
+		return nil, err
 	}
+	doc := textLayout(text)
+	doc.SetMeta(models.MetaExtractor, backendPDFBox)
+	pdfFileMetadata(doc, data, e.opts.password)
+	if err := pdfOCR(ctx, doc, data, e.opts.password, e.opts.ocr); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
 
-	// Read the extracted text
-	textBytes, err := ioutil.ReadFile(outputFile.Name())
+// extractText runs PDFBox on the document once, through a worker process
+// that ends every page with a form feed and reads the password from its
+// stdin. The command line tool marks no page breaks, and the JVM start
+// costs more than the extraction, so pages aren't extracted one by one.
+func (e *pdfExtractor) extractText(ctx context.Context, data []byte) (string, error) {
+	w, err := NewPDFBoxWorker(WorkerOptions{JarPath: e.jarPath, Command: e.command})
 	if err != nil {
-		return "", fmt.Errorf("failed to read extracted text: %w", err)
+		return "", err
 	}
+	defer w.Close()
+	return w.extract(ctx, data, e.opts.password)
+}
 
-	return string(textBytes), nil
+// ExtractLayoutBytes hands the document to PDFBox through a temporary file,
+// since the command line tool only reads from disk
+func (e *pdfExtractor) ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error) {
+	path, cleanup, err := writeTempInput(data)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return e.ExtractLayout(ctx, path)
 }
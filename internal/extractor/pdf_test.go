@@ -2,6 +2,11 @@ package extractor
 
 import (
 	"context"
+	"errors"
+	"os"
+	"resumeparser/internal/models"
+	"resumeparser/internal/pdf/pdftest"
+	"strings"
 	"testing"
 )
 
@@ -29,3 +34,67 @@ func TestPDFExtractor(t *testing.T) {
 		})
 	}
 }
+
+func TestPDFExtractorPages(t *testing.T) {
+	e := New().(*pdfExtractor)
+	e.command = []string{os.Args[0], "-test.run=^TestWorkerHelperProcess$"}
+	t.Setenv("PDFBOX_WORKER_HELPER", "1")
+
+	page := "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>"
+	data := pdftest.Build([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R] /Count 3 >>",
+		page, page, page,
+	})
+	doc, err := e.ExtractLayoutBytes(context.Background(), data)
+	if err != nil {
+		t.Fatalf("ExtractLayoutBytes() error = %v", err)
+	}
+
+	want := []string{"Page 1\n", "Page 2\n", "Page 3\n"}
+	if len(doc.Pages) != len(want) {
+		t.Fatalf("got %d pages, want %d", len(doc.Pages), len(want))
+	}
+	for i, page := range doc.Pages {
+		if got := page.Text(); got != want[i] {
+			t.Errorf("page %d text = %q, want %q", i+1, got, want[i])
+		}
+	}
+	if got := doc.Metadata[models.MetaPages]; got != "3" {
+		t.Errorf("Metadata[%s] = %q, want 3", models.MetaPages, got)
+	}
+}
+
+func TestPDFExtractorPassword(t *testing.T) {
+	t.Setenv("PDFBOX_WORKER_HELPER", "1")
+	tests := []struct {
+		name      string
+		doc       string
+		password  string
+		wantErr   bool
+		encrypted bool
+	}{
+		{name: "right password", doc: "locked", password: "secret"},
+		{name: "wrong password", doc: "locked", password: "hunter2", wantErr: true, encrypted: true},
+		{name: "failure", doc: "fail", password: "hunter2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithPassword(tt.password)).(*pdfExtractor)
+			e.command = []string{os.Args[0], "-test.run=^TestWorkerHelperProcess$"}
+			_, err := e.ExtractLayoutBytes(context.Background(), []byte(tt.doc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtractLayoutBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var encrypted *models.EncryptedError
+			if errors.As(err, &encrypted) != tt.encrypted {
+				t.Errorf("ExtractLayoutBytes() error = %v, want encrypted %v", err, tt.encrypted)
+			}
+			// The password travels over stdin, never on the command line or
+			// into error messages
+			if err != nil && strings.Contains(err.Error(), tt.password) {
+				t.Errorf("error %q reveals the password", err)
+			}
+		})
+	}
+}
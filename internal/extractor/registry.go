@@ -0,0 +1,142 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"resumeparser/internal/models"
+	"sync"
+)
+
+// ErrUnsupportedType is returned when no extractor handles a document's format
+var ErrUnsupportedType = errors.New("unsupported document type")
+
+// Sniffer inspects file content and returns its type, or TypeUnknown to
+// defer to the remaining detectors
+type Sniffer func(data []byte) ContentType
+
+// Registry chooses an extractor by the detected content type of a file
+// rather than its extension. It implements LayoutExtractor itself.
+type Registry struct {
+	mu         sync.RWMutex
+	extractors map[ContentType]LayoutExtractor
+	sniffers   []Sniffer
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		extractors: make(map[ContentType]LayoutExtractor),
+	}
+}
+
+// NewDefaultRegistry creates a registry with the built-in extractors
+func NewDefaultRegistry(opts ...Option) *Registry {
+	r := NewRegistry()
+	r.Register(TypePDF, NewNative(opts...))
+	r.Register(TypeDOCX, NewDocx())
+	r.Register(TypeODT, NewODT())
+	r.Register(TypeRTF, NewRTF())
+	r.Register(TypeHTML, NewHTML())
+	r.Register(TypeText, NewText())
+	r.Register(TypeMarkdown, NewMarkdown())
+	// LaTeX resumes are read from their commands by parser.ParseLaTeX;
+	// as a document the source is plain text
+	r.Register(TypeLaTeX, NewText())
+	return r
+}
+
+// Register sets the extractor for a content type, replacing any existing one
+func (r *Registry) Register(t ContentType, e LayoutExtractor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extractors[t] = e
+}
+
+// RegisterSniffer adds a detector that runs before the built-in magic byte
+// checks, for formats the registry doesn't know about
+func (r *Registry) RegisterSniffer(s Sniffer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sniffers = append(r.sniffers, s)
+}
+
+// Lookup returns the extractor registered for a content type
+func (r *Registry) Lookup(t ContentType) (LayoutExtractor, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.extractors[t]
+	return e, ok
+}
+
+// Detect identifies content using the registered sniffers, then the
+// built-in magic byte checks
+func (r *Registry) Detect(data []byte) ContentType {
+	r.mu.RLock()
+	sniffers := r.sniffers
+	r.mu.RUnlock()
+
+	for _, sniff := range sniffers {
+		if t := sniff(data); t != "" && t != TypeUnknown {
+			return t
+		}
+	}
+	return Detect(data)
+}
+
+func (r *Registry) Extract(ctx context.Context, path string) (string, error) {
+	doc, err := r.ExtractLayout(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+func (r *Registry) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	e, t, err := r.extractorFor(data)
+	if err != nil {
+		return nil, err
+	}
+	// Avoid reading the file a second time when the backend can take bytes
+	var doc *models.Document
+	if be, ok := e.(BytesExtractor); ok {
+		doc, err = be.ExtractLayoutBytes(ctx, data)
+	} else {
+		doc, err = e.ExtractLayout(ctx, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc.SetMeta(models.MetaFormat, string(t))
+	return doc, nil
+}
+
+// ExtractLayoutBytes detects the format of an in-memory document and
+// dispatches it to the matching extractor
+func (r *Registry) ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error) {
+	e, t, err := r.extractorFor(data)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := ExtractLayoutBytes(ctx, e, data)
+	if err != nil {
+		return nil, err
+	}
+	doc.SetMeta(models.MetaFormat, string(t))
+	return doc, nil
+}
+
+func (r *Registry) extractorFor(data []byte) (LayoutExtractor, ContentType, error) {
+	t := r.Detect(data)
+	e, ok := r.Lookup(t)
+	if !ok {
+		return nil, t, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+	return e, t, nil
+}
@@ -0,0 +1,142 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	docx, err := os.ReadFile(writeTestDocx(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want ContentType
+	}{
+		{name: "pdf", data: []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj"), want: TypePDF},
+		{name: "docx", data: docx, want: TypeDOCX},
+		{name: "rtf", data: []byte("{\\rtf1\\ansi Jane}"), want: TypeRTF},
+		{name: "html", data: []byte("\xef\xbb\xbf  <!DOCTYPE html><html><body>Jane</body></html>"), want: TypeHTML},
+		{name: "text", data: []byte("Jane Smith\nEXPERIENCE\n"), want: TypeText},
+		{name: "markdown", data: []byte("# Jane Smith\n## Experience\n"), want: TypeMarkdown},
+		{name: "latex", data: []byte("\\documentclass[11pt]{moderncv}\n\\name{Jane}{Smith}\n"), want: TypeLaTeX},
+		{name: "pdf after junk", data: []byte("\x00\x00\x1bjunk\r\n%PDF-1.4\n1 0 obj"), want: TypePDF},
+		{name: "markdown mentioning pdf", data: []byte("# Jane Smith\nExports reports as %PDF-1.4 files\n"), want: TypeMarkdown},
+		{name: "text mentioning pdf", data: []byte("Jane Smith\nWrote a %PDF-1.7 parser\n"), want: TypeText},
+		{name: "windows-1252 text", data: []byte("Jos\xe9 Garc\xeda\nEXPERIENCE \x96 Caf\xe9\n"), want: TypeText},
+		{name: "binary", data: []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00}, want: TypeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.data); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type stubExtractor struct {
+	text string
+}
+
+func (s stubExtractor) Extract(ctx context.Context, path string) (string, error) {
+	return s.text, nil
+}
+
+func (s stubExtractor) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	return textLayout(s.text), nil
+}
+
+func TestRegistry(t *testing.T) {
+	// A Word document saved with the wrong extension
+	docx, err := os.ReadFile(writeTestDocx(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mislabelled := filepath.Join(t.TempDir(), "resume.pdf")
+	if err := os.WriteFile(mislabelled, docx, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewDefaultRegistry()
+	doc, err := r.ExtractLayout(context.Background(), mislabelled)
+	if err != nil {
+		t.Fatalf("ExtractLayout() error = %v", err)
+	}
+	if got := doc.Pages[0].Lines[0].Text(); got != "Jane Smith" {
+		t.Errorf("first line = %q, want %q", got, "Jane Smith")
+	}
+
+	custom := filepath.Join(t.TempDir(), "resume.custom")
+	if err := os.WriteFile(custom, []byte("CUSTOM\x00data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Extract(context.Background(), custom); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Extract() error = %v, want ErrUnsupportedType", err)
+	}
+
+	const customType ContentType = "application/x-custom"
+	r.RegisterSniffer(func(data []byte) ContentType {
+		if strings.HasPrefix(string(data), "CUSTOM") {
+			return customType
+		}
+		return TypeUnknown
+	})
+	r.Register(customType, stubExtractor{text: "from custom extractor"})
+	if text, err := r.Extract(context.Background(), custom); err != nil || text != "from custom extractor\n" {
+		t.Errorf("Extract() = %q, %v", text, err)
+	}
+}
+
+// pathExtractor only accepts paths and returns the file content as text
+type pathExtractor struct{}
+
+func (pathExtractor) Extract(ctx context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+func (e pathExtractor) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	text, err := e.Extract(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return textLayout(text), nil
+}
+
+func TestExtractReader(t *testing.T) {
+	ctx := context.Background()
+	docx, err := os.ReadFile(writeTestDocx(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewDefaultRegistry()
+	doc, err := r.ExtractLayoutBytes(ctx, docx)
+	if err != nil {
+		t.Fatalf("ExtractLayoutBytes() error = %v", err)
+	}
+	if got := doc.Pages[0].Lines[0].Text(); got != "Jane Smith" {
+		t.Errorf("first line = %q, want %q", got, "Jane Smith")
+	}
+
+	text, err := ExtractReader(ctx, NewDocx(), bytes.NewReader(docx))
+	if err != nil || !strings.HasPrefix(text, "Jane Smith\n") {
+		t.Errorf("ExtractReader() = %q, %v", text, err)
+	}
+
+	// Path-only extractors read from a temporary copy
+	text, err = ExtractReader(ctx, pathExtractor{}, strings.NewReader("Jane Smith\nEXPERIENCE"))
+	if err != nil || text != "Jane Smith\nEXPERIENCE\n" {
+		t.Errorf("ExtractReader() = %q, %v", text, err)
+	}
+}
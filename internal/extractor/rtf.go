@@ -0,0 +1,500 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"resumeparser/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rtfExtractor reads Rich Text Format documents
+type rtfExtractor struct{}
+
+// NewRTF creates a LayoutExtractor for .rtf files
+func NewRTF() LayoutExtractor {
+	return &rtfExtractor{}
+}
+
+func (e *rtfExtractor) Extract(ctx context.Context, path string) (string, error) {
+	doc, err := e.ExtractLayout(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+func (e *rtfExtractor) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RTF: %w", err)
+	}
+	return e.ExtractLayoutBytes(ctx, data)
+}
+
+func (e *rtfExtractor) ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error) {
+	if !strings.HasPrefix(strings.TrimLeft(string(data[:min(len(data), 16)]), " \t\r\n\xef\xbb\xbf"), `{\rtf`) {
+		return nil, fmt.Errorf("invalid RTF: missing {\\rtf header")
+	}
+	r := &rtfReader{
+		ctx:    ctx,
+		data:   data,
+		w:      newFlowWriter(),
+		fonts:  make(map[int]string),
+		styles: make(map[int]int),
+		info:   make(map[string]string),
+		dates:  make(map[string][]int),
+		state:  rtfState{uc: 1},
+	}
+	if err := r.read(); err != nil {
+		return nil, err
+	}
+	return r.finish(), nil
+}
+
+// rtfState is the formatting and destination of an RTF group
+type rtfState struct {
+	style    runStyle
+	font     int
+	uc       int    // fallback characters that follow a \u character
+	dest     string // destination whose text is collected rather than shown
+	skip     bool   // text of this group is dropped
+	listText bool   // group holds a list marker
+	field    bool   // group is a \field
+	result   bool   // group is a field result
+}
+
+// rtfReader interprets the control words of an RTF document
+type rtfReader struct {
+	ctx    context.Context
+	data   []byte
+	pos    int
+	w      *flowWriter
+	state  rtfState
+	groups []rtfState
+
+	// paragraph properties, kept until \pard
+	indent  float64
+	heading int
+	inTable bool
+	cell    *flowWriter
+	cells   []*flowWriter
+
+	fonts      map[int]string // font number to name
+	symbolFont map[int]bool
+	styles     map[int]int // style number to heading level
+	entryNum   int         // number of the font or style table entry being read
+	entryName  strings.Builder
+
+	info  map[string]string // \info destination text such as title and author
+	dates map[string][]int  // \creatim and \revtim fields
+
+	fieldInst strings.Builder // instruction of the open \field
+	fieldText strings.Builder // result text of the open \field
+
+	skipChars int // fallback characters still to skip after \u
+}
+
+// rtfDestinations are the destinations whose text is never shown
+var rtfDestinations = map[string]bool{
+	"colortbl": true, "pict": true, "object": true, "header": true, "headerl": true,
+	"headerr": true, "headerf": true, "footer": true, "footerl": true, "footerr": true,
+	"footerf": true, "footnote": true, "annotation": true, "bkmkstart": true, "bkmkend": true,
+	"listtable": true, "listoverridetable": true, "rsidtbl": true, "themedata": true,
+	"colorschememapping": true, "latentstyles": true, "datastore": true, "xmlnstbl": true,
+	"mmathPr": true, "shppict": true, "nonshppict": true, "revtbl": true, "operator": true,
+	"comment": true, "company": true, "doccomm": true, "keywords": true, "subject": true,
+}
+
+func (r *rtfReader) read() error {
+	for r.pos < len(r.data) {
+		if r.pos%4096 == 0 {
+			if err := r.ctx.Err(); err != nil {
+				return err
+			}
+		}
+		c := r.data[r.pos]
+		switch c {
+		case '{':
+			r.pos++
+			r.groups = append(r.groups, r.state)
+			r.state.field = false
+		case '}':
+			r.pos++
+			r.closeGroup()
+		case '\\':
+			r.control()
+		case '\r', '\n':
+			r.pos++
+		default:
+			start := r.pos
+			for r.pos < len(r.data) && !strings.ContainsRune("{}\\\r\n", rune(r.data[r.pos])) {
+				r.pos++
+			}
+			for _, b := range r.data[start:r.pos] {
+				r.char(b)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *rtfReader) closeGroup() {
+	if len(r.groups) == 0 {
+		return
+	}
+	closed := r.state
+	r.state = r.groups[len(r.groups)-1]
+	r.groups = r.groups[:len(r.groups)-1]
+
+	switch {
+	case closed.dest == "fonttbl" && r.state.dest != "fonttbl",
+		closed.dest == "stylesheet" && r.state.dest != "stylesheet":
+		r.tableEntry(closed.dest)
+	case closed.field:
+		if m := hyperlinkField.FindStringSubmatch(r.fieldInst.String()); m != nil {
+			r.w.addLink(m[1], r.fieldText.String())
+		}
+		r.fieldInst.Reset()
+		r.fieldText.Reset()
+	}
+}
+
+// control reads a control word or symbol at the current position
+func (r *rtfReader) control() {
+	r.pos++ // backslash
+	if r.pos >= len(r.data) {
+		return
+	}
+	c := r.data[r.pos]
+	if !isASCIILetter(c) {
+		r.pos++
+		switch c {
+		case '\'':
+			if r.pos+2 <= len(r.data) {
+				if v, err := strconv.ParseUint(string(r.data[r.pos:r.pos+2]), 16, 8); err == nil {
+					r.char(byte(v))
+				}
+				r.pos += 2
+			}
+		case '*':
+			// The next control word names a destination to skip when unknown
+			r.pos = skipSpace(r.data, r.pos)
+			if r.pos+1 < len(r.data) && r.data[r.pos] == '\\' && isASCIILetter(r.data[r.pos+1]) {
+				word, _, _, _ := r.word(r.pos + 1)
+				switch word {
+				case "fldinst", "generator":
+				default:
+					r.state.skip = true
+				}
+			}
+		case '~':
+			r.text(" ")
+		case '_':
+			r.text("-")
+		case '\\', '{', '}':
+			r.text(string(c))
+		case '\r', '\n':
+			r.par()
+		}
+		return
+	}
+
+	word, param, hasParam, end := r.word(r.pos)
+	r.pos = end
+	if r.pos < len(r.data) && r.data[r.pos] == ' ' {
+		r.pos++
+	}
+	r.apply(word, param, hasParam)
+}
+
+// word reads the control word starting at i with its numeric parameter,
+// returning the position after them
+func (r *rtfReader) word(i int) (string, int, bool, int) {
+	start := i
+	for i < len(r.data) && isASCIILetter(r.data[i]) {
+		i++
+	}
+	word := string(r.data[start:i])
+	numStart := i
+	if i < len(r.data) && r.data[i] == '-' {
+		i++
+	}
+	for i < len(r.data) && r.data[i] >= '0' && r.data[i] <= '9' {
+		i++
+	}
+	param, err := strconv.Atoi(string(r.data[numStart:i]))
+	if err != nil {
+		i = numStart
+	}
+	return word, param, err == nil, i
+}
+
+func (r *rtfReader) apply(word string, param int, hasParam bool) {
+	on := !hasParam || param != 0
+	s := &r.state
+	if s.dest != "" || s.skip {
+		// Paragraph and table properties only apply to body text
+		switch word {
+		case "s", "pard", "li", "intbl", "cell", "nestcell", "row", "nestrow", "page", "par":
+			if s.dest == "stylesheet" && word == "s" {
+				r.entryNum = param
+			}
+			return
+		case "outlinelevel":
+			if s.dest == "stylesheet" && param < 9 {
+				r.styles[r.entryNum] = param + 1
+			}
+			return
+		}
+	}
+
+	switch word {
+	case "fonttbl", "stylesheet", "info", "fldinst":
+		s.dest = word
+		r.entryName.Reset()
+	case "title", "author", "generator", "creatim", "revtim":
+		s.dest = word
+	case "fldrslt":
+		s.result = true
+	case "field":
+		s.field = true
+		r.fieldInst.Reset()
+		r.fieldText.Reset()
+	case "listtext", "pntext":
+		s.listText = true
+	case "f":
+		if s.dest == "fonttbl" {
+			r.entryNum = param
+		}
+		s.font = param
+		s.style.font = r.fonts[param]
+	case "s":
+		r.heading = r.styles[param]
+		r.out().heading = r.heading
+	case "yr", "mo", "dy", "hr", "min":
+		if s.dest == "creatim" || s.dest == "revtim" {
+			fields := r.dates[s.dest]
+			if fields == nil {
+				fields = []int{0, 1, 1, 0, 0}
+			}
+			fields[map[string]int{"yr": 0, "mo": 1, "dy": 2, "hr": 3, "min": 4}[word]] = param
+			r.dates[s.dest] = fields
+		}
+	case "uc":
+		s.uc = param
+	case "u":
+		if param < 0 {
+			param += 0x10000
+		}
+		r.text(string(rune(param)))
+		r.skipChars = s.uc
+	case "b":
+		s.style.bold = on
+	case "i":
+		s.style.italic = on
+	case "fs":
+		s.style.size = float64(param) / 2
+	case "plain":
+		s.style = runStyle{font: r.fonts[s.font]}
+	case "pard":
+		r.indent, r.heading, r.inTable = 0, 0, false
+		r.out().indent, r.out().heading = 0, 0
+	case "li":
+		r.indent = float64(param) / 20
+		r.out().indent = r.indent
+	case "outlinelevel":
+		if param < 9 {
+			r.heading = param + 1
+			r.out().heading = r.heading
+		}
+	case "intbl":
+		r.inTable = true
+	case "cell", "nestcell":
+		r.par()
+		if r.cell == nil {
+			r.cell = r.w.cell()
+		}
+		r.cells = append(r.cells, r.cell)
+		r.cell = nil
+	case "row", "nestrow":
+		r.endTable()
+	case "par":
+		r.par()
+	case "sect":
+		r.par()
+	case "line":
+		r.text("\n")
+	case "page":
+		r.endTable()
+		r.w.pageBreak()
+		r.w.indent, r.w.heading = r.indent, r.heading
+	case "tab":
+		if s.listText {
+			r.text(" ")
+		} else {
+			r.text("\t")
+		}
+	case "emdash":
+		r.text("—")
+	case "endash":
+		r.text("–")
+	case "bullet":
+		r.text("•")
+	case "lquote":
+		r.text("‘")
+	case "rquote":
+		r.text("’")
+	case "ldblquote":
+		r.text("“")
+	case "rdblquote":
+		r.text("”")
+	default:
+		if rtfDestinations[word] {
+			s.skip = true
+		}
+	}
+}
+
+// out returns the writer for body text: the open table cell, if any
+func (r *rtfReader) out() *flowWriter {
+	if r.inTable || r.cell != nil {
+		if r.cell == nil {
+			r.cell = r.w.cell()
+		}
+		return r.cell
+	}
+	return r.w
+}
+
+// par ends a paragraph; its formatting carries on until \pard
+func (r *rtfReader) par() {
+	if r.state.skip || r.state.dest != "" {
+		return
+	}
+	w := r.out()
+	w.endParagraph()
+	w.indent, w.heading = r.indent, r.heading
+}
+
+// endTable emits the row being read
+func (r *rtfReader) endTable() {
+	if r.cell != nil {
+		r.cells = append(r.cells, r.cell)
+		r.cell = nil
+	}
+	if len(r.cells) > 0 {
+		r.w.tableRow(r.cells)
+		r.w.indent, r.w.heading = r.indent, r.heading
+	}
+	r.cells = nil
+}
+
+// char decodes a byte of text in the document's Windows-1252 code page,
+// or as a bullet when set in a symbol font
+func (r *rtfReader) char(b byte) {
+	if r.skipChars > 0 {
+		r.skipChars--
+		return
+	}
+	if r.state.skip {
+		return
+	}
+	if r.state.dest == "fonttbl" || r.state.dest == "stylesheet" {
+		if b == ';' {
+			r.tableEntry(r.state.dest)
+		} else {
+			r.entryName.WriteByte(b)
+		}
+		return
+	}
+	if r.symbolFont[r.state.font] && (r.state.listText || b == 0xB7) {
+		r.text("•")
+		return
+	}
+	if rn, ok := cp1252[b]; ok {
+		r.text(string(rn))
+		return
+	}
+	r.text(string(rune(b)))
+}
+
+// tableEntry records the font or style whose name has just been read
+func (r *rtfReader) tableEntry(dest string) {
+	name := strings.TrimSpace(r.entryName.String())
+	r.entryName.Reset()
+	if name == "" {
+		return
+	}
+	switch dest {
+	case "fonttbl":
+		r.fonts[r.entryNum] = name
+		if lower := strings.ToLower(name); lower == "symbol" || strings.HasPrefix(lower, "wingdings") {
+			if r.symbolFont == nil {
+				r.symbolFont = make(map[int]bool)
+			}
+			r.symbolFont[r.entryNum] = true
+		}
+	case "stylesheet":
+		if m := headingStyleName.FindStringSubmatch(strings.ToLower(name)); m != nil {
+			level, _ := strconv.Atoi(m[1])
+			r.styles[r.entryNum] = level
+		}
+	}
+}
+
+// text routes decoded text to the destination of the current group
+func (r *rtfReader) text(s string) {
+	st := r.state
+	switch {
+	case st.skip:
+	case st.dest == "fldinst":
+		r.fieldInst.WriteString(s)
+	case st.dest == "title" || st.dest == "author" || st.dest == "generator":
+		r.info[st.dest] += s
+	case st.dest != "":
+	default:
+		if st.result {
+			r.fieldText.WriteString(s)
+		}
+		r.out().write(s, st.style)
+	}
+}
+
+func (r *rtfReader) finish() *models.Document {
+	r.endTable()
+	doc := r.w.finish()
+	doc.SetMeta(models.MetaExtractor, backendRTF)
+	doc.SetMeta(models.MetaTitle, r.info["title"])
+	doc.SetMeta(models.MetaAuthor, r.info["author"])
+	generator := strings.TrimRight(strings.TrimSpace(r.info["generator"]), ";")
+	doc.SetMeta(models.MetaCreator, generator)
+	doc.SetMeta(models.MetaProducer, generator)
+	for dest, key := range map[string]string{"creatim": models.MetaCreated, "revtim": models.MetaModified} {
+		if f := r.dates[dest]; f != nil && f[0] > 0 {
+			doc.SetMeta(key, formatTime(time.Date(f[0], time.Month(f[1]), f[2], f[3], f[4], 0, 0, time.UTC)))
+		}
+	}
+	return doc
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\r' || data[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// cp1252 lists the Windows-1252 characters that differ from Latin-1
+var cp1252 = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ',
+	0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“',
+	0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›',
+	0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
@@ -0,0 +1,81 @@
+package extractor
+
+import (
+	"context"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+const testRTF = `{\rtf1\ansi\ansicpg1252\deff0{\fonttbl{\f0\fswiss\fcharset0 Arial;}{\f1\fnil\fcharset2 Symbol;}}
+{\*\generator Riched20 10.0.19041;}{\info{\title Resume}{\author Jane Smith}{\creatim\yr2024\mo3\dy1\hr9\min30}}
+{\stylesheet{\s0 Normal;}{\s1\b\fs32 heading 1;}}
+{\header\pard Confidential\par}
+\pard\fs40 Jane Smith\par
+jane@example.com | {\field{\*\fldinst{HYPERLINK "https://github.com/jsmith"}}{\fldrslt{\ul GitHub}}}\par
+\pard\s1\b\fs32 Experience\par
+\pard\plain\fs22 Initech \endash  Caf\'e9 \'80\par
+{\listtext\pard\plain\f1 \'b7\tab}\pard\li720 Built APIs\par
+\pard\intbl Python\cell Go\cell\row
+\pard\par
+\page Skills\par
+}`
+
+// docLines returns the text of every line, with pages separated by "\f"
+func docLines(doc *models.Document) []string {
+	var lines []string
+	for i, page := range doc.Pages {
+		if i > 0 {
+			lines = append(lines, "\f")
+		}
+		for _, line := range page.Lines {
+			lines = append(lines, line.Text())
+		}
+	}
+	return lines
+}
+
+func TestRTFExtractor(t *testing.T) {
+	doc, err := NewRTF().(BytesExtractor).ExtractLayoutBytes(context.Background(), []byte(testRTF))
+	if err != nil {
+		t.Fatalf("ExtractLayoutBytes() error = %v", err)
+	}
+
+	want := []string{
+		"Jane Smith",
+		"jane@example.com | GitHub",
+		"Experience",
+		"Initech – Café €",
+		"• Built APIs",
+		"Python | Go",
+		"\f",
+		"Skills",
+	}
+	if got := docLines(doc); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+
+	lines := doc.Pages[0].Lines
+	if lines[2].Heading != 1 || !lines[2].Bold() || lines[2].FontSize() != 16 {
+		t.Errorf("heading line = %+v", lines[2])
+	}
+	if lines[4].X() != 36 {
+		t.Errorf("list item indent = %v, want 36", lines[4].X())
+	}
+	if len(doc.Links) != 1 || doc.Links[0].URI != "https://github.com/jsmith" || doc.Links[0].Text != "GitHub" {
+		t.Errorf("links = %+v", doc.Links)
+	}
+
+	wantMeta := map[string]string{
+		models.MetaTitle:     "Resume",
+		models.MetaAuthor:    "Jane Smith",
+		models.MetaCreator:   "Riched20 10.0.19041",
+		models.MetaCreated:   "2024-03-01T09:30:00Z",
+		models.MetaExtractor: "rtf",
+	}
+	for key, value := range wantMeta {
+		if doc.Metadata[key] != value {
+			t.Errorf("Metadata[%s] = %q, want %q", key, doc.Metadata[key], value)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"resumeparser/internal/models"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// textExtractor reads plain text and Markdown resumes
+type textExtractor struct {
+	markdown bool
+}
+
+// NewText creates a LayoutExtractor for plain text files. Form feeds
+// separate pages.
+func NewText() LayoutExtractor {
+	return &textExtractor{}
+}
+
+// NewMarkdown creates a LayoutExtractor for Markdown files. Headings are
+// marked as such and list items become bullets, so sections don't depend
+// on the casing of their titles.
+func NewMarkdown() LayoutExtractor {
+	return &textExtractor{markdown: true}
+}
+
+func (e *textExtractor) Extract(ctx context.Context, path string) (string, error) {
+	doc, err := e.ExtractLayout(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+func (e *textExtractor) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read text file: %w", err)
+	}
+	return e.ExtractLayoutBytes(ctx, data)
+}
+
+func (e *textExtractor) ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error) {
+	text := strings.ReplaceAll(DecodeText(data), "\r\n", "\n")
+	if !e.markdown {
+		doc := textLayout(text)
+		doc.SetMeta(models.MetaExtractor, backendText)
+		return doc, nil
+	}
+
+	doc := readMarkdown(text)
+	doc.SetMeta(models.MetaExtractor, backendMarkdown)
+	return doc, nil
+}
+
+// windows1252 maps the bytes 0x80 to 0x9F of Windows-1252 to Unicode. The
+// other bytes are the Latin-1 code points of the same value; the undefined
+// codes are kept as such.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+// DecodeText returns the text of a plain text file without its byte order
+// mark. Files that aren't valid UTF-8 are read as Windows-1252, the usual
+// encoding of text saved by older Windows editors, which covers Latin-1.
+func DecodeText(data []byte) string {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	var b strings.Builder
+	b.Grow(len(data))
+	for _, c := range data {
+		if c >= 0x80 && c < 0xa0 {
+			b.WriteRune(windows1252[c-0x80])
+		} else {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+var (
+	mdHeading     = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	mdSetext      = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	mdRule        = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdBullet      = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdOrdered     = regexp.MustCompile(`^(\s*)(\d{1,9})[.)]\s+(.*)$`)
+	mdFence       = regexp.MustCompile("^ {0,3}(```|~~~)")
+	mdTableRule   = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdImage       = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink        = regexp.MustCompile(`\[([^\]]+)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	mdAutolink    = regexp.MustCompile(`<((?:https?://|mailto:)[^>\s]+)>`)
+	mdStrong      = regexp.MustCompile(`\*\*(.+?)\*\*|\*([^*\s](?:[^*]*?[^*\s])?)\*`)
+	mdUnderscore  = regexp.MustCompile(`(^|[\s(])__?([^_\s](?:[^_]*?[^_\s])?)__?($|[\s).,;:!?])`)
+	mdInlineCode  = regexp.MustCompile("`([^`]*)`")
+	mdFrontMatter = regexp.MustCompile(`^(\w+)\s*:\s*(.*)$`)
+)
+
+// readMarkdown converts Markdown into lines. ATX and setext headings set
+// the line's heading level, list items get a bullet or their number and
+// are indented by nesting, and inline markup is removed with link targets
+// kept as document links.
+func readMarkdown(text string) *models.Document {
+	w := newFlowWriter()
+	lines := strings.Split(text, "\n")
+
+	// YAML front matter may carry the title and author
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if t := strings.TrimSpace(lines[i]); t == "---" || t == "..." {
+				for _, field := range lines[1:i] {
+					if m := mdFrontMatter.FindStringSubmatch(field); m != nil {
+						value := strings.Trim(strings.TrimSpace(m[2]), `"'`)
+						switch strings.ToLower(m[1]) {
+						case "title":
+							w.doc.SetMeta(models.MetaTitle, value)
+						case "author", "name":
+							w.doc.SetMeta(models.MetaAuthor, value)
+						case "lang", "language":
+							w.doc.SetMeta(models.MetaLanguage, value)
+						}
+					}
+				}
+				lines = lines[i+1:]
+				break
+			}
+		}
+	}
+
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+
+		// Code blocks are kept verbatim
+		if m := mdFence.FindStringSubmatch(line); m != nil && (fence == "" || m[1] == fence) {
+			if fence == "" {
+				fence = m[1]
+			} else {
+				fence = ""
+			}
+			w.endParagraph()
+			continue
+		}
+		if fence != "" {
+			w.write(line, runStyle{})
+			w.breakLine()
+			continue
+		}
+
+		if strings.Contains(line, "\f") {
+			w.pageBreak()
+			line = strings.ReplaceAll(line, "\f", "")
+		}
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			w.endParagraph()
+		case mdHeading.MatchString(line):
+			m := mdHeading.FindStringSubmatch(line)
+			mdParagraph(w, len(m[1]), 0, "", m[2])
+		case i+1 < len(lines) && mdSetext.MatchString(lines[i+1]) && !mdRule.MatchString(line) &&
+			!mdBullet.MatchString(line) && !strings.HasPrefix(strings.TrimSpace(lines[i+1]), "- "):
+			level := 1
+			if strings.TrimSpace(lines[i+1])[0] == '-' {
+				level = 2
+			}
+			mdParagraph(w, level, 0, "", line)
+			i++
+		case mdRule.MatchString(line):
+			w.endParagraph()
+		case mdBullet.MatchString(line):
+			m := mdBullet.FindStringSubmatch(line)
+			mdParagraph(w, 0, mdIndent(m[1]), "•", m[2])
+		case mdOrdered.MatchString(line):
+			m := mdOrdered.FindStringSubmatch(line)
+			n, _ := strconv.Atoi(m[2])
+			mdParagraph(w, 0, mdIndent(m[1]), strconv.Itoa(n)+".", m[3])
+		case mdTableRule.MatchString(line) && strings.Contains(line, "-"):
+			// Separator under a table header
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+			for j := range cells {
+				cells[j] = strings.TrimSpace(cells[j])
+			}
+			mdParagraph(w, 0, 0, "", strings.Join(cells, " | "))
+		default:
+			// Lines of a paragraph are kept as separate lines, as resumes
+			// written in Markdown rely on them
+			text := strings.TrimSpace(line)
+			for strings.HasPrefix(text, ">") {
+				text = strings.TrimSpace(strings.TrimPrefix(text, ">"))
+			}
+			text = strings.TrimSuffix(text, "\\")
+			mdInline(w, text, runStyle{})
+			w.breakLine()
+		}
+	}
+	return w.finish()
+}
+
+// mdIndent converts list indentation to points, one level per two spaces
+func mdIndent(space string) float64 {
+	width := 0
+	for _, r := range space {
+		if r == '\t' {
+			width += 4
+		} else {
+			width++
+		}
+	}
+	return float64(width/2+1) * 18
+}
+
+// mdParagraph writes a heading, list item or table row as its own line
+func mdParagraph(w *flowWriter, heading int, indent float64, marker, text string) {
+	w.breakLine()
+	w.heading, w.indent = heading, indent
+	style := runStyle{bold: heading > 0}
+	if marker != "" {
+		w.write(marker+" ", style)
+	}
+	mdInline(w, text, style)
+	w.breakLine()
+	w.indent, w.heading = 0, 0
+}
+
+// mdInline writes text with its inline markup removed. Text that is
+// entirely strong or emphasized keeps that formatting.
+func mdInline(w *flowWriter, text string, style runStyle) {
+	text = mdImage.ReplaceAllString(text, "")
+	text = mdAutolink.ReplaceAllStringFunc(text, func(s string) string {
+		uri := mdAutolink.FindStringSubmatch(s)[1]
+		w.addLink(uri, strings.TrimPrefix(uri, "mailto:"))
+		return strings.TrimPrefix(uri, "mailto:")
+	})
+	text = mdLink.ReplaceAllStringFunc(text, func(s string) string {
+		m := mdLink.FindStringSubmatch(s)
+		w.addLink(m[2], mdPlain(m[1]))
+		return m[1]
+	})
+
+	trimmed := strings.TrimSpace(text)
+	for _, marker := range []string{"***", "___", "**", "__", "*", "_"} {
+		if len(trimmed) <= 2*len(marker) || !strings.HasPrefix(trimmed, marker) || !strings.HasSuffix(trimmed, marker) {
+			continue
+		}
+		inner := trimmed[len(marker) : len(trimmed)-len(marker)]
+		if strings.Contains(inner, marker) {
+			continue
+		}
+		style.bold = style.bold || len(marker) >= 2
+		style.italic = style.italic || len(marker) != 2
+		text = inner
+		break
+	}
+	w.write(mdPlain(text), style)
+}
+
+// mdPlain removes emphasis, code spans and escapes
+func mdPlain(text string) string {
+	text = mdInlineCode.ReplaceAllString(text, "$1")
+	// Underscores only mark emphasis at word boundaries, so identifiers
+	// such as snake_case survive
+	for {
+		next := mdUnderscore.ReplaceAllString(mdStrong.ReplaceAllString(text, "$1$2"), "$1$2$3")
+		if next == text {
+			break
+		}
+		text = next
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_{}[]()#+-.!|", text[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// looksLikeMarkdown reports whether text uses Markdown headings or links
+func looksLikeMarkdown(text []byte) bool {
+	for _, line := range strings.Split(string(text), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if m := mdHeading.FindStringSubmatch(line); m != nil && m[2] != "" {
+			return true
+		}
+		if mdLink.MatchString(line) && !mdImage.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
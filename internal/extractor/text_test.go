@@ -0,0 +1,104 @@
+package extractor
+
+import (
+	"context"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+const testMarkdown = `---
+title: Resume
+author: Jane Smith
+---
+# Jane Smith
+jane@example.com | [GitHub](https://github.com/jsmith)
+
+## Work Experience
+
+**Initech** — *Senior Engineer*
+- Built ` + "`payment_api`" + ` services
+  - Reduced latency by 40%
+
+Education
+---------
+1. B.S. Computer Science
+`
+
+func TestMarkdownExtractor(t *testing.T) {
+	data := []byte(testMarkdown)
+	if got := Detect(data); got != TypeMarkdown {
+		t.Fatalf("Detect() = %s, want %s", got, TypeMarkdown)
+	}
+	doc, err := NewMarkdown().(BytesExtractor).ExtractLayoutBytes(context.Background(), data)
+	if err != nil {
+		t.Fatalf("ExtractLayoutBytes() error = %v", err)
+	}
+
+	want := []string{
+		"Jane Smith",
+		"jane@example.com | GitHub",
+		"",
+		"Work Experience",
+		"",
+		"Initech — Senior Engineer",
+		"• Built payment_api services",
+		"• Reduced latency by 40%",
+		"",
+		"Education",
+		"1. B.S. Computer Science",
+	}
+	if got := docLines(doc); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+
+	lines := doc.Pages[0].Lines
+	for i, level := range map[int]int{0: 1, 3: 2, 9: 2, 5: 0} {
+		if lines[i].Heading != level {
+			t.Errorf("line %q heading = %d, want %d", lines[i].Text(), lines[i].Heading, level)
+		}
+	}
+	if lines[6].X() != 18 || lines[7].X() != 36 {
+		t.Errorf("list indents = %v, %v, want 18, 36", lines[6].X(), lines[7].X())
+	}
+	if len(doc.Links) != 1 || doc.Links[0].URI != "https://github.com/jsmith" || doc.Links[0].Text != "GitHub" {
+		t.Errorf("links = %+v", doc.Links)
+	}
+	if doc.Metadata[models.MetaTitle] != "Resume" || doc.Metadata[models.MetaAuthor] != "Jane Smith" {
+		t.Errorf("Metadata = %v", doc.Metadata)
+	}
+}
+
+func TestTextExtractor(t *testing.T) {
+	data := []byte("\xef\xbb\xbfJane Smith\r\nEXPERIENCE\r\n\fSKILLS\r\n")
+	if got := Detect(data); got != TypeText {
+		t.Fatalf("Detect() = %s, want %s", got, TypeText)
+	}
+	doc, err := NewText().(BytesExtractor).ExtractLayoutBytes(context.Background(), data)
+	if err != nil {
+		t.Fatalf("ExtractLayoutBytes() error = %v", err)
+	}
+	want := []string{"Jane Smith", "EXPERIENCE", "\f", "SKILLS"}
+	if got := docLines(doc); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "utf-8", data: "\xef\xbb\xbfJosé García", want: "José García"},
+		{name: "latin-1", data: "Jos\xe9 Garc\xeda", want: "José García"},
+		{name: "windows-1252", data: "\x93Caf\xe9\x94 \x96 \x80100", want: "“Café” – €100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeText([]byte(tt.data)); got != tt.want {
+				t.Errorf("DecodeText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,443 @@
+package extractor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"resumeparser/internal/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed assets/PdfBoxWorker.java
+var workerSource []byte
+
+// Worker protocol operations and response statuses. Every message is a
+// frame: a 4-byte big-endian payload length followed by the payload.
+const (
+	opPing    = 'P'
+	opExtract = 'X'
+
+	statusOK    = 'O'
+	statusError = 'E'
+
+	maxFrameSize = 256 << 20
+)
+
+// ErrWorkerClosed is returned by calls on a closed worker pool
+var ErrWorkerClosed = errors.New("pdfbox worker pool is closed")
+
+// WorkerOptions configures a pool of persistent PDFBox processes
+type WorkerOptions struct {
+	// JarPath locates pdfbox-app; it defaults to the same locations as New
+	JarPath string
+	// Command overrides the worker command line, e.g. to run a stand-in
+	Command []string
+	// MaxWorkers limits how many processes, and so extractions, run at once
+	MaxWorkers int
+	// HealthCheckInterval is how long a worker may sit idle before it is
+	// pinged ahead of its next request
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout bounds how long a ping may take
+	HealthCheckTimeout time.Duration
+	// Password opens encrypted PDFs
+	Password string
+	// OCR recognizes the text of scanned pages
+	OCR OCR
+}
+
+// PDFBoxWorker extracts text through long-lived PDFBox processes spoken to
+// over stdin/stdout, avoiding a JVM start for every file. Crashed or
+// unresponsive processes are restarted on their next use.
+type PDFBoxWorker struct {
+	opts    WorkerOptions
+	command []string
+	tempDir string
+	slots   chan *workerProcess
+
+	mu     sync.Mutex
+	closed bool
+	procs  map[*workerProcess]bool
+}
+
+// NewPDFBoxWorker creates a worker pool. Processes are started lazily.
+func NewPDFBoxWorker(opts WorkerOptions) (*PDFBoxWorker, error) {
+	if opts.MaxWorkers <= 0 {
+		opts.MaxWorkers = 1
+	}
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = 30 * time.Second
+	}
+	if opts.HealthCheckTimeout <= 0 {
+		opts.HealthCheckTimeout = 5 * time.Second
+	}
+
+	w := &PDFBoxWorker{
+		opts:    opts,
+		command: opts.Command,
+		slots:   make(chan *workerProcess, opts.MaxWorkers),
+		procs:   make(map[*workerProcess]bool),
+	}
+
+	if len(w.command) == 0 {
+		jarPath := opts.JarPath
+		if jarPath == "" {
+			jarPath = findJar()
+		}
+		dir, err := os.MkdirTemp("", "pdfbox-worker-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create worker directory: %w", err)
+		}
+		source := filepath.Join(dir, "PdfBoxWorker.java")
+		if err := os.WriteFile(source, workerSource, 0o644); err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("failed to write worker source: %w", err)
+		}
+		w.tempDir = dir
+		w.command = []string{"java", "-cp", jarPath, source}
+	}
+
+	for i := 0; i < opts.MaxWorkers; i++ {
+		w.slots <- nil
+	}
+	return w, nil
+}
+
+func (w *PDFBoxWorker) Extract(ctx context.Context, path string) (string, error) {
+	doc, err := w.ExtractLayout(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+func (w *PDFBoxWorker) ExtractLayout(ctx context.Context, path string) (*models.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	return w.ExtractLayoutBytes(ctx, data)
+}
+
+func (w *PDFBoxWorker) ExtractLayoutBytes(ctx context.Context, data []byte) (*models.Document, error) {
+	text, err := w.extract(ctx, data, w.opts.Password)
+	if err != nil {
+		return nil, err
+	}
+	doc := textLayout(text)
+	doc.SetMeta(models.MetaExtractor, backendPDFBox)
+	pdfFileMetadata(doc, data, w.opts.Password)
+	if err := pdfOCR(ctx, doc, data, w.opts.Password, w.opts.OCR); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (w *PDFBoxWorker) extract(ctx context.Context, data []byte, password string) (string, error) {
+	if len(password) > 0xFFFF {
+		return "", fmt.Errorf("password too long")
+	}
+	body := make([]byte, 0, 3+len(password)+len(data))
+	body = append(body, opExtract)
+	body = binary.BigEndian.AppendUint16(body, uint16(len(password)))
+	body = append(body, password...)
+	body = append(body, data...)
+
+	reply, err := w.call(ctx, body)
+	if err != nil {
+		var remote *workerError
+		if errors.As(err, &remote) && isPDFBoxPasswordError(remote.message) {
+			return "", &models.EncryptedError{PasswordRequired: password == "", Err: err}
+		}
+		return "", fmt.Errorf("PDF extraction failed: %w", err)
+	}
+	// The form feed after the last page ends it rather than starting another
+	return strings.TrimSuffix(string(reply), "\f"), nil
+}
+
+// Ping checks that a worker process responds, starting one if needed
+func (w *PDFBoxWorker) Ping(ctx context.Context) error {
+	_, err := w.call(ctx, []byte{opPing})
+	return err
+}
+
+// call sends one request to an available worker and returns its reply
+func (w *PDFBoxWorker) call(ctx context.Context, request []byte) ([]byte, error) {
+	var proc *workerProcess
+	select {
+	case proc = <-w.slots:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var err error
+	proc, err = w.ready(ctx, proc)
+	if err != nil {
+		w.release(nil)
+		return nil, err
+	}
+
+	reply, err := proc.roundTrip(ctx, request)
+	var remote *workerError
+	if err != nil && !errors.As(err, &remote) {
+		// The process is in an unknown state; replace it on next use
+		w.discard(proc)
+		w.release(nil)
+		return nil, err
+	}
+	proc.lastUsed = time.Now()
+	w.release(proc)
+	return reply, err
+}
+
+// ready returns a live process for the slot, health checking idle ones
+// and starting a replacement when the previous one died
+func (w *PDFBoxWorker) ready(ctx context.Context, proc *workerProcess) (*workerProcess, error) {
+	w.mu.Lock()
+	closed := w.closed
+	w.mu.Unlock()
+	if closed {
+		if proc != nil {
+			w.discard(proc)
+		}
+		return nil, ErrWorkerClosed
+	}
+
+	if proc != nil && proc.exited() {
+		w.discard(proc)
+		proc = nil
+	}
+	if proc != nil && time.Since(proc.lastUsed) > w.opts.HealthCheckInterval {
+		pingCtx, cancel := context.WithTimeout(ctx, w.opts.HealthCheckTimeout)
+		_, err := proc.roundTrip(pingCtx, []byte{opPing})
+		cancel()
+		if err != nil {
+			w.discard(proc)
+			proc = nil
+		}
+	}
+	if proc != nil {
+		return proc, nil
+	}
+
+	proc, err := startWorker(w.command)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	w.procs[proc] = true
+	w.mu.Unlock()
+	return proc, nil
+}
+
+func (w *PDFBoxWorker) release(proc *workerProcess) {
+	w.slots <- proc
+}
+
+func (w *PDFBoxWorker) discard(proc *workerProcess) {
+	proc.kill()
+	w.mu.Lock()
+	delete(w.procs, proc)
+	w.mu.Unlock()
+}
+
+// Close stops all worker processes and removes temporary files
+func (w *PDFBoxWorker) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	procs := w.procs
+	w.procs = make(map[*workerProcess]bool)
+	w.mu.Unlock()
+
+	for proc := range procs {
+		proc.stop()
+	}
+	if w.tempDir != "" {
+		return os.RemoveAll(w.tempDir)
+	}
+	return nil
+}
+
+// workerError is an error reported by the worker for one request; the
+// process itself is still usable
+type workerError struct {
+	message string
+}
+
+func (e *workerError) Error() string {
+	return "worker error: " + e.message
+}
+
+type workerProcess struct {
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	stdout   *bufio.Reader
+	stderr   *bytes.Buffer
+	done     chan struct{}
+	lastUsed time.Time
+}
+
+func startWorker(command []string) (*workerProcess, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &limitedBuffer{buf: &stderr, limit: 64 << 10}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start pdfbox worker: %w", err)
+	}
+
+	proc := &workerProcess{
+		cmd:      cmd,
+		stdin:    stdin,
+		stdout:   bufio.NewReader(stdout),
+		stderr:   &stderr,
+		done:     make(chan struct{}),
+		lastUsed: time.Now(),
+	}
+	go func() {
+		cmd.Wait()
+		close(proc.done)
+	}()
+	return proc, nil
+}
+
+func (p *workerProcess) exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// roundTrip writes a request frame and reads the response frame. If the
+// context ends first the process is killed, since the stream position is
+// lost.
+func (p *workerProcess) roundTrip(ctx context.Context, request []byte) ([]byte, error) {
+	type result struct {
+		payload []byte
+		err     error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		if err := writeFrame(p.stdin, request); err != nil {
+			ch <- result{err: err}
+			return
+		}
+		payload, err := readFrame(p.stdout)
+		ch <- result{payload: payload, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-ch:
+	case <-ctx.Done():
+		p.kill()
+		<-ch
+		return nil, ctx.Err()
+	}
+
+	if res.err != nil {
+		if p.waitExit(100 * time.Millisecond) {
+			return nil, fmt.Errorf("pdfbox worker exited: %w\nDetailed error: %s", res.err, p.stderr.String())
+		}
+		return nil, res.err
+	}
+	if len(res.payload) == 0 {
+		return nil, fmt.Errorf("empty response from pdfbox worker")
+	}
+	switch res.payload[0] {
+	case statusOK:
+		return res.payload[1:], nil
+	case statusError:
+		return nil, &workerError{message: string(res.payload[1:])}
+	}
+	return nil, fmt.Errorf("invalid response status %q from pdfbox worker", res.payload[0])
+}
+
+func (p *workerProcess) waitExit(d time.Duration) bool {
+	select {
+	case <-p.done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func (p *workerProcess) kill() {
+	p.stdin.Close()
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+	<-p.done
+}
+
+// stop closes stdin so the worker exits on its own, killing it if it doesn't
+func (p *workerProcess) stop() {
+	p.stdin.Close()
+	if !p.waitExit(2 * time.Second) {
+		p.kill()
+	}
+}
+
+func writeFrame(w io.Writer, payload []byte) error {
+	if len(payload) > maxFrameSize {
+		return fmt.Errorf("frame of %d bytes exceeds limit", len(payload))
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(payload)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(header[:])
+	if n > maxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes exceeds limit", n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	mu    sync.Mutex
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.limit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
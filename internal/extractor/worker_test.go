@@ -0,0 +1,254 @@
+package extractor
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"resumeparser/internal/models"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWorkerHelperProcess is not a real test. It stands in for the Java
+// worker when re-executed by newTestWorker, speaking the same protocol.
+// Extract replies echo the process id and document so tests can observe
+// reuse and restarts; special documents trigger failures, and PDF files
+// come back as three pages.
+func TestWorkerHelperProcess(t *testing.T) {
+	if os.Getenv("PDFBOX_WORKER_HELPER") != "1" {
+		return
+	}
+	in := bufio.NewReader(os.Stdin)
+	out := bufio.NewWriter(os.Stdout)
+	wedged := false
+	for {
+		request, err := readFrame(in)
+		if err != nil {
+			os.Exit(0)
+		}
+		if wedged {
+			time.Sleep(time.Hour)
+		}
+
+		status, reply := byte(statusOK), ""
+		switch request[0] {
+		case opPing:
+			reply = "pong"
+		case opExtract:
+			n := int(binary.BigEndian.Uint16(request[1:3]))
+			password := string(request[3 : 3+n])
+			doc := string(request[3+n:])
+			switch doc {
+			case "crash":
+				os.Exit(3)
+			case "fail":
+				status, reply = statusError, "java.io.IOException: Error: End-of-File"
+			case "slow":
+				time.Sleep(300 * time.Millisecond)
+			case "wedge":
+				wedged = true
+			case "locked":
+				if password != "secret" {
+					status, reply = statusError, "org.apache.pdfbox.pdmodel.encryption.InvalidPasswordException: Cannot decrypt PDF, the password is incorrect"
+				}
+			}
+			if status == statusOK && strings.HasPrefix(doc, "%PDF") {
+				// A real document: one page per form feed, as PDFBox ends them
+				reply = "Page 1\n\fPage 2\n\fPage 3\n\f"
+			} else if status == statusOK {
+				reply = fmt.Sprintf("pid %d\n%s\f%s", os.Getpid(), doc, password)
+			}
+		default:
+			status, reply = statusError, "unknown operation"
+		}
+		writeFrame(out, append([]byte{status}, reply...))
+		out.Flush()
+	}
+}
+
+func newTestWorker(t *testing.T, opts WorkerOptions) *PDFBoxWorker {
+	t.Helper()
+	opts.Command = []string{os.Args[0], "-test.run=^TestWorkerHelperProcess$"}
+	w, err := NewPDFBoxWorker(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PDFBOX_WORKER_HELPER", "1")
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+// workerPID extracts a document and returns the pid line of the reply
+func workerPID(t *testing.T, w *PDFBoxWorker, ctx context.Context, doc string) (string, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "resume.pdf")
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	text, err := w.Extract(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	pid, rest, _ := strings.Cut(text, "\n")
+	if !strings.HasPrefix(rest, doc) {
+		t.Errorf("Extract() = %q, want document %q echoed", text, doc)
+	}
+	return pid, nil
+}
+
+func TestPDFBoxWorker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reuses process", func(t *testing.T) {
+		w := newTestWorker(t, WorkerOptions{})
+		first, err := workerPID(t, w, ctx, "one")
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := workerPID(t, w, ctx, "two")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first != second {
+			t.Errorf("worker restarted between calls: %q then %q", first, second)
+		}
+	})
+
+	t.Run("pages split on form feed", func(t *testing.T) {
+		w := newTestWorker(t, WorkerOptions{})
+		path := filepath.Join(t.TempDir(), "resume.pdf")
+		os.WriteFile(path, []byte("Jane Smith\fEXPERIENCE"), 0o644)
+		doc, err := w.ExtractLayout(ctx, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The form feed ending the last page adds no empty page
+		if len(doc.Pages) != 2 || doc.Pages[0].Lines[1].Text() != "Jane Smith" || doc.Pages[1].Lines[0].Text() != "EXPERIENCE" {
+			t.Errorf("ExtractLayout() = %+v", doc.Pages)
+		}
+	})
+
+	t.Run("document error keeps process", func(t *testing.T) {
+		w := newTestWorker(t, WorkerOptions{})
+		first, err := workerPID(t, w, ctx, "one")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := workerPID(t, w, ctx, "fail"); err == nil || !strings.Contains(err.Error(), "End-of-File") {
+			t.Fatalf("Extract() error = %v, want worker error", err)
+		}
+		second, err := workerPID(t, w, ctx, "two")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first != second {
+			t.Errorf("worker restarted after document error: %q then %q", first, second)
+		}
+	})
+
+	t.Run("restarts after crash", func(t *testing.T) {
+		w := newTestWorker(t, WorkerOptions{})
+		first, err := workerPID(t, w, ctx, "one")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := workerPID(t, w, ctx, "crash"); err == nil {
+			t.Fatal("Extract() succeeded on crashing worker")
+		}
+		second, err := workerPID(t, w, ctx, "two")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first == second {
+			t.Errorf("worker not restarted after crash: %q", second)
+		}
+	})
+
+	t.Run("health check replaces wedged process", func(t *testing.T) {
+		w := newTestWorker(t, WorkerOptions{
+			HealthCheckInterval: time.Nanosecond,
+			HealthCheckTimeout:  100 * time.Millisecond,
+		})
+		first, err := workerPID(t, w, ctx, "wedge")
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := workerPID(t, w, ctx, "two")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first == second {
+			t.Errorf("wedged worker was reused: %q", second)
+		}
+	})
+
+	t.Run("concurrency limit", func(t *testing.T) {
+		w := newTestWorker(t, WorkerOptions{MaxWorkers: 2})
+		var mu sync.Mutex
+		pids := make(map[string]bool)
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				pid, err := workerPID(t, w, ctx, "slow")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				pids[pid] = true
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		if len(pids) > 2 {
+			t.Errorf("used %d processes, want at most 2", len(pids))
+		}
+	})
+
+	t.Run("waiting respects context", func(t *testing.T) {
+		w := newTestWorker(t, WorkerOptions{MaxWorkers: 1})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			workerPID(t, w, ctx, "slow")
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		if _, err := workerPID(t, w, short, "two"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Extract() error = %v, want deadline exceeded", err)
+		}
+		<-done
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		w := newTestWorker(t, WorkerOptions{})
+		_, err := workerPID(t, w, ctx, "locked")
+		var encrypted *models.EncryptedError
+		if !errors.Is(err, models.ErrEncrypted) || !errors.As(err, &encrypted) || !encrypted.PasswordRequired {
+			t.Fatalf("Extract() error = %v, want password required", err)
+		}
+
+		w = newTestWorker(t, WorkerOptions{Password: "secret"})
+		if _, err := workerPID(t, w, ctx, "locked"); err != nil {
+			t.Errorf("Extract() with password error = %v", err)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		w := newTestWorker(t, WorkerOptions{})
+		w.Close()
+		if err := w.Ping(ctx); !errors.Is(err, ErrWorkerClosed) {
+			t.Errorf("Ping() error = %v, want ErrWorkerClosed", err)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package extractor
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// xmlNode is a minimal DOM for walking office document XML parts
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string // character data before the first child element
+	tail     string // character data between this element and its next sibling
+}
+
+// parseXMLTree reads a whole XML document into a node tree
+func parseXMLTree(r io.Reader) (*xmlNode, error) {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		top := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name, attrs: t.Attr}
+			top.children = append(top.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if n := len(top.children); n > 0 {
+				top.children[n-1].tail += string(t)
+			} else {
+				top.text += string(t)
+			}
+		}
+	}
+	return root, nil
+}
+
+// attr returns the value of the attribute with the given local name
+func (n *xmlNode) attr(local string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.attrs {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child returns the first direct child with the given local name
+func (n *xmlNode) child(local string) *xmlNode {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.children {
+		if c.name.Local == local {
+			return c
+		}
+	}
+	return nil
+}
+
+// find returns the first descendant with the given local name
+func (n *xmlNode) find(local string) *xmlNode {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.children {
+		if c.name.Local == local {
+			return c
+		}
+		if found := c.find(local); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// textContent returns all character data inside the node
+func (n *xmlNode) textContent() string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(n.text)
+	for _, c := range n.children {
+		b.WriteString(c.textContent())
+		b.WriteString(c.tail)
+	}
+	return b.String()
+}
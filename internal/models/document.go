@@ -0,0 +1,177 @@
+package models
+
+import (
+	"math"
+	"strings"
+)
+
+// Document is the layout-level result of extracting a resume file
+type Document struct {
+	Pages    []Page
+	Links    []Link
+	Metadata map[string]string // document properties, keyed by the Meta* constants
+}
+
+// Metadata keys recorded by extractors and the parser
+const (
+	MetaPages      = "pages"
+	MetaCharacters = "characters" // non-whitespace characters of extracted text
+	MetaTitle      = "title"
+	MetaAuthor     = "author"
+	MetaCreator    = "creator"  // application that created the document
+	MetaProducer   = "producer" // application that produced the PDF
+	MetaCreated    = "created"  // RFC 3339
+	MetaModified   = "modified" // RFC 3339
+	MetaLanguage   = "language"
+	MetaFormat     = "format" // detected content type
+	MetaExtractor  = "extractor"
+	MetaOCR        = "ocr"       // "true" when page text came from OCR
+	MetaOCRPages   = "ocr_pages" // comma-separated numbers of the recognized pages
+
+	MetaOCRSkippedPages = "ocr_skipped_pages" // scanned pages whose images couldn't be decoded for OCR
+
+	MetaDetectedLanguage = "detected_language" // language the parser read the resume in, e.g. "de"
+)
+
+// SetMeta records a metadata value, ignoring empty ones
+func (d *Document) SetMeta(key, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	if d.Metadata == nil {
+		d.Metadata = make(map[string]string)
+	}
+	d.Metadata[key] = value
+}
+
+// Link is a hyperlink target together with the text it is attached to.
+// The active area uses the same coordinates as TextRun and is zero when
+// the extractor has no geometry for the link.
+type Link struct {
+	URI    string
+	Text   string
+	Page   int
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+type Page struct {
+	Number int
+	Width  float64
+	Height float64
+	Lines  []TextLine // in reading order, one column region after another
+}
+
+// TextLine is a group of runs sharing a baseline, ordered left to right
+type TextLine struct {
+	Runs    []TextRun
+	Column  int // 0 for full-width text, 1 and up for columns left to right
+	Heading int // heading level from document styles, 0 for body text
+}
+
+// TextRun is a piece of text drawn with a single font. Positions are in
+// points from the top-left corner of the page and are zero when the
+// extractor has no geometry for the text.
+type TextRun struct {
+	Text     string
+	X        float64
+	Y        float64
+	Width    float64
+	Height   float64
+	FontName string
+	FontSize float64
+	Bold     bool
+	Italic   bool
+	Order    int // reading order within the page
+}
+
+// Text joins the runs of the line, adding a space where runs are visibly apart
+func (l TextLine) Text() string {
+	var b strings.Builder
+	for i, run := range l.Runs {
+		if i > 0 {
+			prev := l.Runs[i-1]
+			gap := run.X - (prev.X + prev.Width)
+			if gap > math.Max(run.FontSize, prev.FontSize)*0.15 &&
+				!strings.HasSuffix(prev.Text, " ") && !strings.HasPrefix(run.Text, " ") {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(run.Text)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// X returns the left edge of the line
+func (l TextLine) X() float64 {
+	if len(l.Runs) == 0 {
+		return 0
+	}
+	return l.Runs[0].X
+}
+
+// Y returns the top of the line
+func (l TextLine) Y() float64 {
+	if len(l.Runs) == 0 {
+		return 0
+	}
+	return l.Runs[0].Y
+}
+
+// FontSize returns the largest font size used on the line
+func (l TextLine) FontSize() float64 {
+	var size float64
+	for _, run := range l.Runs {
+		size = math.Max(size, run.FontSize)
+	}
+	return size
+}
+
+// Bold reports whether every visible run on the line is bold
+func (l TextLine) Bold() bool {
+	return l.allRuns(func(r TextRun) bool { return r.Bold })
+}
+
+// Italic reports whether every visible run on the line is italic
+func (l TextLine) Italic() bool {
+	return l.allRuns(func(r TextRun) bool { return r.Italic })
+}
+
+func (l TextLine) allRuns(check func(TextRun) bool) bool {
+	seen := false
+	for _, run := range l.Runs {
+		if strings.TrimSpace(run.Text) == "" {
+			continue
+		}
+		if !check(run) {
+			return false
+		}
+		seen = true
+	}
+	return seen
+}
+
+// Text returns the page text, one line per text line
+func (p Page) Text() string {
+	var b strings.Builder
+	for _, line := range p.Lines {
+		b.WriteString(line.Text())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Text returns the plain text of the document with pages separated by form feeds
+func (d *Document) Text() string {
+	var b strings.Builder
+	for i, page := range d.Pages {
+		if i > 0 {
+			b.WriteString("\f")
+		}
+		b.WriteString(page.Text())
+	}
+	return b.String()
+}
@@ -1 +1,31 @@
 package models
+
+import "errors"
+
+// ErrEncrypted matches errors for encrypted documents that could not be
+// opened, as opposed to damaged ones: errors.Is(err, models.ErrEncrypted)
+var ErrEncrypted = errors.New("document is encrypted")
+
+// EncryptedError reports an encrypted document that could not be opened
+type EncryptedError struct {
+	PasswordRequired bool // a password is needed and none was given
+	Err              error
+}
+
+func (e *EncryptedError) Error() string {
+	switch {
+	case e.PasswordRequired:
+		return "document is encrypted: password required"
+	case e.Err != nil:
+		return "document is encrypted: " + e.Err.Error()
+	}
+	return ErrEncrypted.Error()
+}
+
+func (e *EncryptedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *EncryptedError) Is(target error) bool {
+	return target == ErrEncrypted
+}
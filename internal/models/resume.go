@@ -2,13 +2,26 @@ package models
 
 type Resume struct {
 	Raw      map[string]string
-	Sections map[string]Sections
+	Sections []Sections // in the order they appear in the document
 	Metadata map[string]string
 }
 
+// Section returns the section stored under name, e.g. "experience"
+func (r *Resume) Section(name string) (Sections, bool) {
+	for _, section := range r.Sections {
+		if section.Name == name {
+			return section, true
+		}
+	}
+	return Sections{}, false
+}
+
 type Sections struct {
-	Type    SectionType
-	Content interface{}
+	Name       string // section name, e.g. "experience" or "publications"
+	Heading    string // heading as written in the resume, empty when the section had none
+	Type       SectionType
+	Content    interface{}
+	Confidence float64 `json:",omitempty"` // how surely the section's header was recognized, from 0 to 1
 }
 
 type SectionType string
@@ -41,6 +54,7 @@ type TimelineEntry struct {
 	StartDate    string
 	EndDate      string
 	Details      []string
+	Links        []string          // hyperlinks anchored in the entry
 	Metadata     map[string]string // for weird resume formats
 }
 
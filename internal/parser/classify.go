@@ -0,0 +1,340 @@
+package parser
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minSectionConfidence is the score a line needs to be taken as a section
+// header
+const minSectionConfidence = 0.65
+
+// Weights of the signals combined by classifySection. The dictionary match
+// carries most of the score; layout and typography confirm or rule out a
+// line that merely mentions a section's name.
+const (
+	dictionaryWeight = 0.75
+	casingWeight     = 0.1  // all caps or title case
+	shortWeight      = 0.05 // four words or fewer
+	colonWeight      = 0.1  // ends with a colon
+	gapWeight        = 0.1  // blank line or extra space before the line
+	gapAfterWeight   = 0.05 // blank line or extra space after the line
+	typographyWeight = 0.15 // larger, bold or heading-styled type
+	sentencePenalty  = 0.3  // the header or its inline content ends like a sentence
+	digitPenalty     = 0.2  // holds numbers, as dates and addresses do
+	namePenalty      = 0.3  // ends in a company suffix such as "Inc"
+	lengthPenalty    = 0.3  // more than six words
+)
+
+// headingStopwords are left out when comparing a line with section names,
+// so "Skills & Tools" and "Honors and Awards" compare by their nouns and
+// "Experience (continued)" by its section
+var headingStopwords = map[string]bool{"and": true, "of": true, "the": true, "my": true, "continued": true, "cont": true}
+
+// maxInlineHeaderWords is the longest label that can lead a line of
+// content, as "Skills" does in "Skills: Go, Python"
+const maxInlineHeaderWords = 3
+
+// classifySection scores how likely lines[i] is to be a section header and
+// returns the section it names, with a confidence between 0 and 1. The
+// empty name means the line is not a header. A short header ending in a
+// colon may be followed by the section's first line of content, which is
+// returned as inline.
+func (p *Parser) classifySection(lines []Line, i int, lang *languagePack) (name string, confidence float64, inline string) {
+	l := lines[i]
+	text := strings.TrimSpace(l.Text)
+	if text == "" || isBulletPoint(text) || isContactDetail(text) {
+		return "", 0, ""
+	}
+
+	title, rest, colon := strings.Cut(text, ":")
+	inline = strings.TrimSpace(rest)
+	if inline != "" && len(strings.Fields(title)) > maxInlineHeaderWords {
+		return "", 0, ""
+	}
+	words := headingWords(title)
+	if len(words) == 0 {
+		return "", 0, ""
+	}
+
+	name, match := p.matchSection(words, lang)
+	if name == "" {
+		return "", 0, ""
+	}
+
+	score := match * dictionaryWeight
+	if isUpperCase(title) || isTitleCase(title) {
+		score += casingWeight
+	}
+	if len(strings.Fields(title)) <= 4 {
+		score += shortWeight
+	}
+	if colon {
+		score += colonWeight
+	}
+	if l.Gap {
+		score += gapWeight
+	}
+	if i+1 < len(lines) && lines[i+1].Gap {
+		score += gapAfterWeight
+	}
+	if l.Header {
+		score += typographyWeight
+	}
+	if strings.ContainsAny(title[len(title)-1:], ".!?;,") || strings.HasSuffix(inline, ".") {
+		score -= sentencePenalty
+	}
+	if strings.ContainsAny(title, "0123456789") {
+		score -= digitPenalty
+	}
+	if len(strings.Fields(title)) > 6 {
+		score -= lengthPenalty
+	}
+	if fields := strings.Fields(strings.ToLower(title)); contains(organizationSuffixes, fields[len(fields)-1]) {
+		score -= namePenalty
+	}
+
+	score = math.Round(min(max(score, 0), 1)*100) / 100
+	if score < minSectionConfidence {
+		return "", score, ""
+	}
+	return name, score, inline
+}
+
+// matchSection compares the words of a line with every section alias,
+// including those of the resume's language, and returns the best section
+// with a score between 0 and 1. All of an
+// alias's words must appear in the line, allowing for typos; the score
+// drops with the words of the line the alias doesn't account for, so
+// "Project Experience" matches projects rather than experience. Of equally
+// good matches, such as for "Skills & Achievements", the section defined
+// first in the config wins.
+func (p *Parser) matchSection(words []string, lang *languagePack) (string, float64) {
+	var best string
+	var bestScore float64
+	for _, name := range p.sectionOrder {
+		patterns := p.sectionDetectors[name]
+		patterns = append(patterns[:len(patterns):len(patterns)], lang.Sections[name]...)
+		for _, pattern := range patterns {
+			aliasWords := headingWords(pattern)
+			if len(aliasWords) == 0 {
+				continue
+			}
+			var total float64
+			for _, aliasWord := range aliasWords {
+				var similarity float64
+				for _, word := range words {
+					similarity = max(similarity, wordSimilarity(aliasWord, word))
+				}
+				if similarity == 0 {
+					total = 0
+					break
+				}
+				total += similarity
+			}
+			if total == 0 {
+				continue
+			}
+			aliasCoverage := total / float64(len(aliasWords))
+			lineCoverage := min(total/float64(len(words)), 1)
+			if score := aliasCoverage * (0.4 + 0.6*lineCoverage); score > bestScore {
+				best, bestScore = name, score
+			}
+		}
+	}
+	return best, bestScore
+}
+
+// headingWords lowercases a line and splits it into words, leaving out
+// punctuation and stopwords
+func headingWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	words := fields[:0]
+	for _, word := range fields {
+		if !headingStopwords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// wordSimilarity is 1 for equal words and less for words within a small
+// edit distance of each other, allowing one typo in five letters and two
+// in eight. Other words score 0.
+func wordSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	n := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if n < 5 {
+		return 0
+	}
+	allowed := 1
+	if n >= 8 {
+		allowed = 2
+	}
+	d := editDistance(a, b)
+	if d > allowed {
+		return 0
+	}
+	return 1 - float64(d)/float64(n)
+}
+
+// editDistance returns the Levenshtein distance between two words
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// isUpperCase reports whether every letter in text is upper case
+func isUpperCase(text string) bool {
+	hasLetter := false
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+			if !unicode.IsUpper(r) {
+				return false
+			}
+		}
+	}
+	return hasLetter
+}
+
+// isTitleCase reports whether every word of text other than stopwords
+// starts with a capital
+func isTitleCase(text string) bool {
+	hasWord := false
+	for _, word := range strings.Fields(text) {
+		r, _ := utf8.DecodeRuneInString(word)
+		if !unicode.IsLetter(r) || headingStopwords[strings.ToLower(word)] {
+			continue
+		}
+		hasWord = true
+		if !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return hasWord
+}
+
+// maxUnknownHeaderWords is the longest line taken as the header of a
+// section the config doesn't define
+const maxUnknownHeaderWords = 4
+
+// headerStyle is the look of a header line: its heading level, type size,
+// weight and casing. The level tells a Markdown "## Experience" from the
+// "### Acme Corp" entries under it.
+type headerStyle struct {
+	level int
+	size  float64
+	bold  bool
+	caps  bool
+}
+
+func styleOf(l Line) headerStyle {
+	return headerStyle{level: l.Level, size: math.Round(l.FontSize*2) / 2, bold: l.Bold, caps: isUpperCase(l.Text)}
+}
+
+// unknownSection reports whether lines[i] heads a section the config
+// doesn't define, such as "Publications" or "Interests": a short line in
+// capitals or title case that ends in a colon, stands out the way the
+// resume's recognized headers do in the same style, is in capitals after a
+// gap, or follows a gap in the style of the recognized headers that follow
+// one, as plain text headers do. styles holds the styles of the recognized
+// headers and gapped those of the ones after a gap. Like recognized
+// headers, a short label ending in a colon may lead the section's first
+// line, returned as inline. It returns the section's name, normalized from
+// the header, and a confidence.
+func unknownSection(lines []Line, i int, styles, gapped map[headerStyle]bool) (name string, confidence float64, inline string) {
+	l := lines[i]
+	text := strings.TrimSpace(l.Text)
+	if text == "" || isBulletPoint(text) || isContactDetail(text) || isPageNumber(text) {
+		return "", 0, ""
+	}
+	title, rest, colon := strings.Cut(text, ":")
+	inline = strings.TrimSpace(rest)
+	words := strings.Fields(title)
+	if len(words) == 0 || len(words) > maxUnknownHeaderWords {
+		return "", 0, ""
+	}
+	if inline != "" && (len(words) > maxInlineHeaderWords || strings.HasSuffix(inline, ".")) {
+		return "", 0, ""
+	}
+	if strings.ContainsAny(title, "0123456789.,;!?|()") || contains(organizationSuffixes, strings.ToLower(words[len(words)-1])) {
+		return "", 0, ""
+	}
+	if !isUpperCase(title) && !isTitleCase(title) {
+		return "", 0, ""
+	}
+
+	gapAfter := i+1 < len(lines) && lines[i+1].Gap
+	var score float64
+	switch {
+	case l.Header && (styles[styleOf(l)] || l.Gap && isUpperCase(title)):
+		score = dictionaryWeight / 2
+		if colon {
+			score += colonWeight
+		}
+	case colon:
+		score = dictionaryWeight / 2
+	case l.Gap && gapped[styleOf(l)]:
+		score = dictionaryWeight / 2
+	default:
+		return "", 0, ""
+	}
+	if l.Gap {
+		score += gapWeight
+	}
+	if gapAfter {
+		score += gapAfterWeight
+	}
+	return sectionKey(title), math.Round(score*100) / 100, inline
+}
+
+// entryLines is how many lines below a heading are searched for the dates
+// of a timeline entry
+const entryLines = 3
+
+// headsEntry reports whether lines[i] heads a timeline entry, such as an
+// employer's name: a date range follows within a few lines, before the
+// next header
+func headsEntry(lines []Line, i int, lang *languagePack) bool {
+	for _, l := range lines[i+1 : min(len(lines), i+1+entryLines)] {
+		if l.Header {
+			break
+		}
+		if dates, ok := lang.extractDates(l.Text); ok && dates.start != dates.end {
+			return true
+		}
+	}
+	return false
+}
+
+// sectionKey normalizes a header into a section name: lower case, with
+// punctuation removed, so "Volunteer Work:" becomes "volunteer work"
+func sectionKey(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(words, " ")
+}
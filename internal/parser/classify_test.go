@@ -0,0 +1,149 @@
+package parser
+
+import "testing"
+
+func TestUnknownSection(t *testing.T) {
+	titleCase := map[headerStyle]bool{{}: true}
+	tests := []struct {
+		name       string
+		lines      []Line
+		styles     map[headerStyle]bool
+		want       string
+		confidence float64
+		inline     string
+	}{
+		{
+			name:       "colon",
+			lines:      []Line{{Text: "Go"}, {Text: "Publications:"}, {Text: "A paper"}},
+			want:       "publications",
+			confidence: 0.38,
+		},
+		{
+			name:       "colon with inline content",
+			lines:      []Line{{Text: "Go"}, {Text: "Languages: English, German", Gap: true}},
+			want:       "languages",
+			confidence: 0.48,
+			inline:     "English, German",
+		},
+		{
+			name:  "inline sentence",
+			lines: []Line{{Text: "Go"}, {Text: "Note: Available from May."}},
+		},
+		{
+			name:  "long inline label",
+			lines: []Line{{Text: "Go"}, {Text: "Some Other Long Label: Go"}},
+		},
+		{
+			name:       "title case between gaps",
+			lines:      []Line{{Text: "Go"}, {Text: "Interests", Gap: true}, {Text: "Hiking", Gap: true}},
+			styles:     titleCase,
+			want:       "interests",
+			confidence: 0.53,
+		},
+		{
+			name:       "title case after gap",
+			lines:      []Line{{Text: "Go"}, {Text: "Interests", Gap: true}, {Text: "Hiking"}},
+			styles:     titleCase,
+			want:       "interests",
+			confidence: 0.48,
+		},
+		{
+			name:   "title case without gap",
+			lines:  []Line{{Text: "Go"}, {Text: "Interests"}, {Text: "Hiking"}},
+			styles: titleCase,
+		},
+		{
+			name:  "title case in another style",
+			lines: []Line{{Text: "Go"}, {Text: "Interests", Gap: true}, {Text: "Hiking", Gap: true}},
+		},
+		{
+			name:       "capitals after gap",
+			lines:      []Line{{Text: "Go"}, {Text: "INTERESTS", Header: true, Gap: true}, {Text: "Hiking"}},
+			want:       "interests",
+			confidence: 0.48,
+		},
+		{
+			name:  "organization",
+			lines: []Line{{Text: "Go"}, {Text: "Acme Inc", Gap: true}, {Text: "Engineer", Gap: true}},
+		},
+		{
+			name:  "contact detail",
+			lines: []Line{{Text: "Go"}, {Text: "Email: jane@example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, confidence, inline := unknownSection(tt.lines, 1, tt.styles, tt.styles)
+			if name != tt.want || confidence != tt.confidence || inline != tt.inline {
+				t.Errorf("unknownSection() = %q, %v, %q, want %q, %v, %q", name, confidence, inline, tt.want, tt.confidence, tt.inline)
+			}
+		})
+	}
+}
+
+func TestClassifySection(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       Line
+		want       string
+		confidence float64
+		inline     string
+	}{
+		{name: "capitals", line: Line{Text: "EXPERIENCE", Header: true, Gap: true}, want: "experience", confidence: 1},
+		{name: "title case", line: Line{Text: "Experience"}, want: "experience", confidence: 0.9},
+		{name: "typo", line: Line{Text: "Experiance", Gap: true}, want: "experience", confidence: 0.88},
+		{name: "more specific alias", line: Line{Text: "Project Experience", Header: true}, want: "projects", confidence: 1},
+		{name: "inline", line: Line{Text: "Skills: Go, Python"}, want: "skills", confidence: 1, inline: "Go, Python"},
+		{name: "inline sentence", line: Line{Text: "Skills: I like to code a lot."}, want: "skills", confidence: 0.7, inline: "I like to code a lot."},
+		{name: "below threshold", line: Line{Text: "Experience 2020"}, confidence: 0.47},
+		{name: "organization", line: Line{Text: "Education Inc"}, confidence: 0.37},
+		{name: "sentence", line: Line{Text: "I have experience with Go."}, confidence: 0.09},
+		{name: "no match", line: Line{Text: "Acme Corp"}},
+		{name: "bullet", line: Line{Text: "• Experience"}},
+		{name: "tie goes to the first section", line: Line{Text: "SKILLS & ACHIEVEMENTS", Header: true}, want: "skills", confidence: 0.83},
+		{name: "tie in config order", line: Line{Text: "EDUCATION AND EXPERIENCE", Header: true}, want: "education", confidence: 0.83},
+	}
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := []Line{{Text: "Jane Doe"}, tt.line, {Text: "Acme Corp"}}
+			// Map iteration order varies between runs; the result must not
+			for range 20 {
+				name, confidence, inline := p.classifySection(lines, 1, languagePacks["en"])
+				if name != tt.want || confidence != tt.confidence || inline != tt.inline {
+					t.Fatalf("classifySection() = %q, %v, %q, want %q, %v, %q", name, confidence, inline, tt.want, tt.confidence, tt.inline)
+				}
+			}
+			name, confidence, _ := p.classifySection(lines, 1, languagePacks["en"])
+			if name != "" && confidence < minSectionConfidence {
+				t.Errorf("confidence %v is below the threshold", confidence)
+			}
+		})
+	}
+}
+
+func TestIsTitleCase(t *testing.T) {
+	tests := []struct {
+		text      string
+		titleCase bool
+		upperCase bool
+	}{
+		{text: "Work Experience", titleCase: true},
+		{text: "Honors and Awards", titleCase: true},
+		{text: "Skills & Tools", titleCase: true},
+		{text: "EXPERIENCE", titleCase: true, upperCase: true},
+		{text: "Work experience"},
+		{text: "experience"},
+		{text: "2020"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := isTitleCase(tt.text); got != tt.titleCase {
+				t.Errorf("isTitleCase(%q) = %v, want %v", tt.text, got, tt.titleCase)
+			}
+			if got := isUpperCase(tt.text); got != tt.upperCase {
+				t.Errorf("isUpperCase(%q) = %v, want %v", tt.text, got, tt.upperCase)
+			}
+		})
+	}
+}
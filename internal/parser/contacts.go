@@ -1,44 +1,55 @@
 package parser
 
 import (
+	"net/url"
 	"regexp"
 	"resumeparser/internal/models"
 	"strings"
+	"unicode"
+)
+
+// Regular expressions for different contact information
+var (
+	emailPattern    = regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
+	phonePattern    = regexp.MustCompile(`(?:(?:\+?\d{1,3}[-.]?\s*)?(?:\(?\d{3}\)?[-.]?\s*)?\d{3}[-.]?\s*\d{4})`)
+	linkedinPattern = regexp.MustCompile(`(?i)linkedin\.com/(?:in|profile)/[a-zA-Z0-9_-]+`)
+	githubPattern   = regexp.MustCompile(`(?i)github\.com/[a-zA-Z0-9_-]+`)
 )
 
 // parseContact extracts contact information from the given lines
-func (p *Parser) parseContact(lines []string) (*models.ContactContent, error) {
+func (p *Parser) parseContact(lines []Line) (*models.ContactContent, error) {
 	content := &models.ContactContent{
 		Email:  make([]string, 0),
 		Number: make([]string, 0),
 		Social: make(map[string]string),
 	}
 
-	// Regular expressions for different contact information
-	emailRegex := regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
-	phoneRegex := regexp.MustCompile(`(?:(?:\+?\d{1,3}[-.]?\s*)?(?:\(?\d{3}\)?[-.]?\s*)?\d{3}[-.]?\s*\d{4})`)
-	linkedinRegex := regexp.MustCompile(`(?i)linkedin\.com/(?:in|profile)/[a-zA-Z0-9_-]+`)
-	githubRegex := regexp.MustCompile(`(?i)github\.com/[a-zA-Z0-9_-]+`)
+	for _, l := range lines {
+		line := l.Text
 
-	for _, line := range lines {
 		// Extract email addresses
-		emails := emailRegex.FindAllString(line, -1)
+		emails := emailPattern.FindAllString(line, -1)
 		content.Email = append(content.Email, emails...)
 
 		// Extract phone numbers
-		phones := phoneRegex.FindAllString(line, -1)
+		phones := phonePattern.FindAllString(line, -1)
 		content.Number = append(content.Number, phones...)
 
 		// Extract LinkedIn profile
-		if linkedin := linkedinRegex.FindString(line); linkedin != "" {
+		if linkedin := linkedinPattern.FindString(line); linkedin != "" {
 			content.Social["linkedin"] = linkedin
 		}
 
 		// Extract GitHub profile
-		if github := githubRegex.FindString(line); github != "" {
+		if github := githubPattern.FindString(line); github != "" {
 			content.Social["github"] = github
 		}
 
+		// Hyperlinks carry the real target behind anchors like "LinkedIn"
+		for _, uri := range l.Links {
+			addContactLink(content, uri)
+		}
+
 		// Try to identify name and location
 		if content.Name == "" && !containsAny(line, "@", "http", "linkedin", "github") {
 			parts := strings.Split(line, "•")
@@ -52,8 +63,206 @@ func (p *Parser) parseContact(lines []string) (*models.ContactContent, error) {
 					}
 				}
 			}
+		} else if content.Location == "" && strings.Contains(line, ",") && !isContactDetail(line) && isPlace(strings.Split(line, ",")) {
+			// A line of its own such as "Berlin, Germany"
+			content.Location = strings.TrimSpace(line)
 		}
 	}
 
 	return content, nil
 }
+
+// splitHeaderBlock divides the lines above the first section header into
+// the contact block and a summary or objective paragraph. Contact lines are
+// short, like the name, a headline or the location, or hold an email, phone
+// number or link. A sentence or a line longer than a heading starts the
+// summary, which runs until a gap. Decorative lines are dropped.
+func splitHeaderBlock(lines []Line) (contact, summary []Line) {
+	inSummary := false
+	for _, l := range lines {
+		text := strings.TrimSpace(l.Text)
+		switch {
+		case isDecoration(text):
+		case isContactDetail(text):
+			contact = append(contact, l)
+			inSummary = false
+		case inSummary && !l.Gap, isSummaryLine(text):
+			summary = append(summary, l)
+			inSummary = true
+		default:
+			contact = append(contact, l)
+			inSummary = false
+		}
+	}
+	return contact, summary
+}
+
+// isDecoration reports whether a line is a rule or ornament without any
+// letters or digits
+func isDecoration(text string) bool {
+	return !strings.ContainsFunc(text, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) })
+}
+
+// isSummaryLine reports whether a line of the header block reads as prose
+// rather than a name or headline: longer than a heading, or a sentence
+func isSummaryLine(text string) bool {
+	words := len(strings.Fields(text))
+	return words > maxHeadingWords || words > 3 && strings.ContainsAny(text[len(text)-1:], ".!?")
+}
+
+// findContactDetails looks through the given lines, those of every section
+// but the references and the running headers and footers, for an email
+// address, phone number or profile the contact block doesn't give, as when
+// they are only linked, in a footer or in a closing "Contact me" line. A contact section is added when the resume has
+// none.
+func findContactDetails(resume *models.Resume, lines []Line) {
+	var contact *models.ContactContent
+	if section, ok := resume.Section(contactSection); ok {
+		contact, _ = section.Content.(*models.ContactContent)
+	}
+	added := contact == nil
+	if added {
+		contact = &models.ContactContent{
+			Email:  make([]string, 0),
+			Number: make([]string, 0),
+			Social: make(map[string]string),
+		}
+	}
+
+	found := false
+	for _, l := range lines {
+		for _, uri := range l.Links {
+			lower := strings.ToLower(uri)
+			switch {
+			case strings.HasPrefix(lower, "mailto:") && len(contact.Email) == 0,
+				strings.HasPrefix(lower, "tel:") && len(contact.Number) == 0:
+				addContactLink(contact, uri)
+				found = true
+			default:
+				if platform, profile := socialPlatform(uri); profile && contact.Social[platform] == "" {
+					contact.Social[platform] = uri
+					found = true
+				}
+			}
+		}
+		if !isContactDetail(l.Text) {
+			continue
+		}
+		if email := emailPattern.FindString(l.Text); email != "" && len(contact.Email) == 0 {
+			contact.Email = append(contact.Email, email)
+			found = true
+		}
+		if number := phonePattern.FindString(l.Text); number != "" && len(contact.Number) == 0 {
+			contact.Number = append(contact.Number, strings.TrimSpace(number))
+			found = true
+		}
+		for _, word := range strings.Fields(l.Text) {
+			word = strings.Trim(word, "()<>[],;|")
+			if platform, profile := socialPlatform(word); profile && contact.Social[platform] == "" {
+				contact.Social[platform] = word
+				found = true
+			}
+		}
+	}
+
+	if added && found {
+		section := models.Sections{Name: contactSection, Type: models.ContactSection, Content: contact}
+		resume.Sections = append([]models.Sections{section}, resume.Sections...)
+	}
+}
+
+// addContactLink records a hyperlink from the contact block as an email,
+// phone number, social profile or personal website
+func addContactLink(content *models.ContactContent, uri string) {
+	lower := strings.ToLower(uri)
+	switch {
+	case strings.HasPrefix(lower, "mailto:"):
+		email, _, _ := strings.Cut(uri[len("mailto:"):], "?")
+		if email, err := url.PathUnescape(email); err == nil && email != "" && !contains(content.Email, email) {
+			content.Email = append(content.Email, email)
+		}
+	case strings.HasPrefix(lower, "tel:"):
+		if number := strings.TrimSpace(uri[len("tel:"):]); number != "" && !contains(content.Number, number) {
+			content.Number = append(content.Number, number)
+		}
+	default:
+		if platform, _ := socialPlatform(uri); platform != "" {
+			content.Social[platform] = uri
+		} else if _, ok := content.Social["website"]; !ok && strings.HasPrefix(lower, "http") {
+			content.Social["website"] = uri
+		}
+	}
+}
+
+// addProfileLinks fills in social profiles linked from anywhere in the
+// document, e.g. sidebar icons, that the contact block doesn't mention
+func addProfileLinks(resume *models.Resume, links []models.Link) {
+	section, ok := resume.Section(contactSection)
+	if !ok {
+		return
+	}
+	contact, ok := section.Content.(*models.ContactContent)
+	if !ok {
+		return
+	}
+	for _, link := range links {
+		if platform, profile := socialPlatform(link.URI); profile {
+			if _, seen := contact.Social[platform]; !seen {
+				contact.Social[platform] = link.URI
+			}
+		}
+	}
+}
+
+var socialDomains = map[string]string{
+	"linkedin.com":      "linkedin",
+	"github.com":        "github",
+	"gitlab.com":        "gitlab",
+	"bitbucket.org":     "bitbucket",
+	"twitter.com":       "twitter",
+	"x.com":             "twitter",
+	"stackoverflow.com": "stackoverflow",
+	"medium.com":        "medium",
+	"behance.net":       "behance",
+	"dribbble.com":      "dribbble",
+	"kaggle.com":        "kaggle",
+	"leetcode.com":      "leetcode",
+}
+
+// socialPlatform returns the platform a URL belongs to, and whether it
+// points at a person's profile rather than e.g. a repository
+func socialPlatform(uri string) (string, bool) {
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	for domain, platform := range socialDomains {
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			continue
+		}
+		segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+		switch platform {
+		case "linkedin":
+			return platform, len(segments) >= 2 && (segments[0] == "in" || segments[0] == "pub")
+		case "github", "gitlab", "bitbucket":
+			return platform, len(segments) == 1
+		default:
+			return platform, len(segments) >= 1
+		}
+	}
+	return "", false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"reflect"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestFindContactDetails(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		email  []string
+		number []string
+	}{
+		{
+			name:  "contact block",
+			text:  "Jane Doe\njane@example.com\n\nEXPERIENCE\nAcme Corp\nJan 2020 - Present\n",
+			email: []string{"jane@example.com"},
+		},
+		{
+			name: "referee",
+			text: "Jane Doe\nBerlin\n\nEXPERIENCE\nAcme Corp\nJan 2020 - Present\n\nREFERENCES\nJohn Smith, Acme Corp\njohn@acme.com\n+1 555 123 4567\n",
+		},
+		{
+			name:  "closing paragraph",
+			text:  "Jane Doe\nBerlin\n\nEXPERIENCE\nAcme Corp\nJan 2020 - Present\n\nINTERESTS\nHiking\nContact me at jane@example.com\n",
+			email: []string{"jane@example.com"},
+		},
+		{
+			name:  "referee after the candidate's email",
+			text:  "Jane Doe\nBerlin\n\nREFERENCES\nJohn Smith, Acme Corp\njohn@acme.com\n\nINTERESTS\nContact me at jane@example.com\n",
+			email: []string{"jane@example.com"},
+		},
+		{
+			name:   "footer",
+			text:   "Jane Doe\n\nEXPERIENCE\nAcme Corp\nJan 2020 - Present\njane@example.com | +1 555 987 6543\n\fEDUCATION\nTU Berlin\n2015 - 2019\njane@example.com | +1 555 987 6543\n",
+			email:  []string{"jane@example.com"},
+			number: []string{"+1 555 987 6543"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resume, err := NewParser().Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			contact := &models.ContactContent{}
+			if section, ok := resume.Section(contactSection); ok {
+				contact = section.Content.(*models.ContactContent)
+			}
+			if len(contact.Email) > 0 || len(tt.email) > 0 {
+				if !reflect.DeepEqual(contact.Email, tt.email) {
+					t.Errorf("email = %q, want %q", contact.Email, tt.email)
+				}
+			}
+			if len(contact.Number) > 0 || len(tt.number) > 0 {
+				if !reflect.DeepEqual(contact.Number, tt.number) {
+					t.Errorf("number = %q, want %q", contact.Number, tt.number)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitHeaderBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		contact []string
+		summary []string
+	}{
+		{
+			name:    "contact only",
+			text:    "Jane Doe\nSenior Engineer\nBerlin, Germany\njane@example.com",
+			contact: []string{"Jane Doe", "Senior Engineer", "Berlin, Germany", "jane@example.com"},
+		},
+		{
+			name:    "summary",
+			text:    "Jane Doe\njane@example.com\nBackend engineer with eight years of experience building payments.\nI like Go.",
+			contact: []string{"Jane Doe", "jane@example.com"},
+			summary: []string{"Backend engineer with eight years of experience building payments.", "I like Go."},
+		},
+		{
+			name:    "summary ends at gap",
+			text:    "Jane Doe\nBackend engineer with eight years of experience building payments.\n\nBerlin",
+			contact: []string{"Jane Doe", "Berlin"},
+			summary: []string{"Backend engineer with eight years of experience building payments."},
+		},
+		{
+			name:    "contact detail ends summary",
+			text:    "Backend engineer with eight years of experience building payments.\njane@example.com",
+			contact: []string{"jane@example.com"},
+			summary: []string{"Backend engineer with eight years of experience building payments."},
+		},
+		{
+			name:    "decoration",
+			text:    "Jane Doe\n* * *\n~~~~",
+			contact: []string{"Jane Doe"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []Line
+			gap := false
+			for _, text := range strings.Split(tt.text, "\n") {
+				if text == "" {
+					gap = true
+					continue
+				}
+				lines = append(lines, Line{Text: text, Gap: gap})
+				gap = false
+			}
+			contact, summary := splitHeaderBlock(lines)
+			if got := lineTexts(contact); !reflect.DeepEqual(got, tt.contact) {
+				t.Errorf("contact = %q, want %q", got, tt.contact)
+			}
+			if got := lineTexts(summary); len(got) > 0 || len(tt.summary) > 0 {
+				if !reflect.DeepEqual(got, tt.summary) {
+					t.Errorf("summary = %q, want %q", got, tt.summary)
+				}
+			}
+		})
+	}
+}
+
+func TestIsSummaryLine(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "Jane Doe"},
+		{text: "Senior Backend Engineer"},
+		{text: "Go. Kafka. Postgres."},
+		{text: "I build payment systems.", want: true},
+		{text: "Backend engineer with eight years of experience building payments", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := isSummaryLine(tt.text); got != tt.want {
+				t.Errorf("isSummaryLine(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
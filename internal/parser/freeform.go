@@ -5,7 +5,11 @@ import (
 	"strings"
 )
 
-func (p *Parser) parseFreeform(lines []string) (*models.FreeformContent, error) {
+// maxHeadingWords is the longest line read as a heading within a freeform
+// section
+const maxHeadingWords = 8
+
+func (p *Parser) parseFreeform(lines []Line) (*models.FreeformContent, error) {
 	content := &models.FreeformContent{
 		Entries: make([]models.FreeformEntry, 0),
 	}
@@ -13,80 +17,88 @@ func (p *Parser) parseFreeform(lines []string) (*models.FreeformContent, error)
 	var currentEntry *models.FreeformEntry
 	var buffer []string
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	// flushBuffer adds the paragraph read so far to the current entry
+	flushBuffer := func() {
+		if len(buffer) == 0 {
+			return
+		}
+		if currentEntry == nil {
+			// Create entry without explicit heading
+			currentEntry = &models.FreeformEntry{Content: make([]string, 0)}
+		}
+		currentEntry.Content = append(currentEntry.Content, strings.Join(buffer, " "))
+		buffer = nil
+	}
+	closeEntry := func() {
+		flushBuffer()
+		if currentEntry != nil {
+			content.Entries = append(content.Entries, *currentEntry)
+			currentEntry = nil
+		}
+	}
+
+	margin, allBold := freeformLayout(lines)
+	for i, l := range lines {
+		line := strings.TrimSpace(l.Text)
 		if line == "" {
-			// Empty line could indicate a section break
-			if len(buffer) > 0 {
-				if currentEntry != nil {
-					currentEntry.Content = append(currentEntry.Content, strings.Join(buffer, " "))
-					content.Entries = append(content.Entries, *currentEntry)
-				} else {
-					// Create entry without explicit heading
-					content.Entries = append(content.Entries, models.FreeformEntry{
-						Content: []string{strings.Join(buffer, " ")},
-					})
-				}
-				buffer = nil
-				currentEntry = nil
-			}
 			continue
 		}
+		// A gap could indicate a section break
+		if l.Gap {
+			closeEntry()
+		}
 
-		indentation := countIndentation(line)
-		if indentation == 0 && !isBulletPoint(line) && !strings.HasPrefix(line, "-") {
-			// This might be a new heading
-			if currentEntry != nil {
-				if len(buffer) > 0 {
-					currentEntry.Content = append(currentEntry.Content, strings.Join(buffer, " "))
-				}
-				content.Entries = append(content.Entries, *currentEntry)
-				buffer = nil
+		switch {
+		case isBulletPoint(line):
+			flushBuffer()
+			if currentEntry == nil {
+				currentEntry = &models.FreeformEntry{Content: make([]string, 0)}
 			}
+			currentEntry.Content = append(currentEntry.Content, removeBulletPoint(line))
+		case isFreeformHeading(l, lines[i+1:], margin, allBold):
+			closeEntry()
 			currentEntry = &models.FreeformEntry{
 				Heading: line,
 				Content: make([]string, 0),
 			}
-		} else {
-			// This is content
-			if isBulletPoint(line) {
-				// Handle bullet points
-				if len(buffer) > 0 {
-					if currentEntry != nil {
-						currentEntry.Content = append(currentEntry.Content, strings.Join(buffer, " "))
-					}
-					buffer = nil
-				}
-				line = removeBulletPoint(line)
-				if currentEntry != nil {
-					currentEntry.Content = append(currentEntry.Content, line)
-				} else {
-					content.Entries = append(content.Entries, models.FreeformEntry{
-						Content: []string{line},
-					})
-				}
-			} else {
-				// Accumulate text in buffer
-				buffer = append(buffer, line)
-			}
+		default:
+			// Accumulate text in buffer
+			buffer = append(buffer, line)
 		}
 	}
+	closeEntry()
 
-	// Handle any remaining content
-	if len(buffer) > 0 {
-		if currentEntry != nil {
-			currentEntry.Content = append(currentEntry.Content, strings.Join(buffer, " "))
-			content.Entries = append(content.Entries, *currentEntry)
-		} else {
-			content.Entries = append(content.Entries, models.FreeformEntry{
-				Content: []string{strings.Join(buffer, " ")},
-			})
+	return content, nil
+}
+
+// freeformLayout returns the smallest indentation in a section and whether
+// every line in it is bold
+func freeformLayout(lines []Line) (float64, bool) {
+	margin, allBold := -1.0, true
+	for _, l := range lines {
+		if margin < 0 || l.Indent < margin {
+			margin = l.Indent
 		}
-	} else if currentEntry != nil {
-		content.Entries = append(content.Entries, *currentEntry)
+		allBold = allBold && l.Bold
 	}
+	return max(margin, 0), allBold
+}
 
-	return content, nil
+// isFreeformHeading reports whether a line heads the text after it: a short
+// line at the section's margin that is bold when the rest isn't, or that
+// the next line is a bullet or indented under
+func isFreeformHeading(l Line, rest []Line, margin float64, allBold bool) bool {
+	line := strings.TrimSpace(l.Text)
+	if l.Indent > margin+1 || len(strings.Fields(line)) > maxHeadingWords || strings.ContainsAny(line[len(line)-1:], ".!?;,") {
+		return false
+	}
+	if l.Bold && !allBold {
+		return true
+	}
+	if len(rest) == 0 || rest[0].Gap {
+		return false
+	}
+	return isBulletPoint(rest[0].Text) || rest[0].Indent > l.Indent+1
 }
 
 func countIndentation(line string) int {
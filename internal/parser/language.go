@@ -0,0 +1,173 @@
+package parser
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//go:embed assets/languages.json
+var languagePacksJSON []byte
+
+// languagePack holds the words a language uses on resumes: section
+// headings, month names, words for an ongoing end date and degree names.
+// Stopwords are what the language is recognized by.
+type languagePack struct {
+	Code      string              `json:"-"`
+	Name      string              `json:"name"`
+	Stopwords []string            `json:"stopwords"`
+	Months    [][]string          `json:"months"`
+	Present   []string            `json:"present"`
+	Degrees   []string            `json:"degrees"`
+	Sections  map[string][]string `json:"sections"` // aliases added to the configured sections of the same name
+
+	stopwords   map[string]bool
+	datePattern *regexp.Regexp
+	dateRange   *regexp.Regexp
+	present     *regexp.Regexp
+	degree      *regexp.Regexp
+}
+
+// defaultLanguage is assumed unless the text clearly reads as another
+// language. Its months, present words and degrees are understood in every
+// language, as resumes mix them in.
+const defaultLanguage = "en"
+
+// minLanguageEvidence is how many stopwords and headings a language needs
+// before it is preferred over the default
+const minLanguageEvidence = 3
+
+var languagePacks = loadLanguagePacks()
+
+// loadLanguagePacks reads the embedded packs and compiles their patterns
+func loadLanguagePacks() map[string]*languagePack {
+	packs := make(map[string]*languagePack)
+	if err := json.Unmarshal(languagePacksJSON, &packs); err != nil {
+		panic(fmt.Sprintf("invalid built-in language packs: %v", err))
+	}
+	base := packs[defaultLanguage]
+	for code, pack := range packs {
+		pack.Code = code
+		pack.stopwords = make(map[string]bool)
+		for _, word := range pack.Stopwords {
+			pack.stopwords[word] = true
+		}
+
+		months, present, degrees := pack.Months, pack.Present, pack.Degrees
+		if code != defaultLanguage {
+			months = append(append([][]string{}, months...), base.Months...)
+			present = append(append([]string{}, present...), base.Present...)
+			degrees = append(append([]string{}, degrees...), base.Degrees...)
+		}
+		var monthNames []string
+		for _, names := range months {
+			monthNames = append(monthNames, names...)
+		}
+
+		// Dates are a month and year, as in "März 2020" or "enero de 2020",
+		// MM/YYYY, MM.YYYY or a bare year
+		date := `(?:\b` + alternation(monthNames) + `\.?\s+(?:de\s+)?\d{4}|\b\d{2}[/.-]\d{4}\b|\b(?:19|20)\d{2}\b)`
+		ongoing := alternation(present)
+		separator := `\s*(?:-|–|—|to|bis|à|au|a|al|até|hasta)\s*`
+		pack.datePattern = regexp.MustCompile(`(?i)` + date)
+		pack.dateRange = regexp.MustCompile(`(?i)` + date + `(?:` + separator + `(?:` + date + `|` + ongoing + `))?`)
+		// "Present" only ends a range, so "now used by 3 teams" is no date
+		pack.present = regexp.MustCompile(`(?i)` + date + separator + ongoing + `(?:$|[^\pL])`)
+		pack.degree = regexp.MustCompile(`(?i)(?:^|[^\pL.])` + alternation(degrees) + `(?:$|[^\pL])`)
+	}
+	return packs
+}
+
+// alternation matches any of words, longest first so "janv" wins over "jan"
+func alternation(words []string) string {
+	sorted := append([]string{}, words...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for i, word := range sorted {
+		sorted[i] = regexp.QuoteMeta(word)
+	}
+	return `(?:` + strings.Join(sorted, "|") + `)`
+}
+
+// detectLanguage guesses the language of a resume from its stopwords and
+// section headings, falling back to English
+func detectLanguage(lines []string) *languagePack {
+	scores := make(map[string]int)
+	for _, line := range lines {
+		lower := strings.ToLower(strings.TrimRight(strings.TrimSpace(line), ":"))
+		if lower == "" {
+			continue
+		}
+		for code, pack := range languagePacks {
+			for _, aliases := range pack.Sections {
+				if contains(aliases, lower) {
+					scores[code] += minLanguageEvidence
+				}
+			}
+		}
+		words := strings.FieldsFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) })
+		for _, word := range words {
+			for code, pack := range languagePacks {
+				if pack.stopwords[word] {
+					scores[code]++
+				}
+			}
+		}
+	}
+
+	codes := make([]string, 0, len(languagePacks))
+	for code := range languagePacks {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	best, runnerUp := defaultLanguage, 0
+	for _, code := range codes {
+		if code == best {
+			continue
+		}
+		if scores[code] > scores[best] {
+			best, runnerUp = code, scores[best]
+		} else {
+			runnerUp = max(runnerUp, scores[code])
+		}
+	}
+	if best != defaultLanguage && (scores[best] < minLanguageEvidence || scores[best] == runnerUp) {
+		best = defaultLanguage
+	}
+	return languagePacks[best]
+}
+
+// extractDates attempts to extract start and end dates from a line of text.
+// Dates are taken in the order they appear; a word for an ongoing role
+// such as "Present" or "heute" after the start date makes the end
+// "Present".
+func (lang *languagePack) extractDates(line string) (dateInfo, bool) {
+	dates := lang.datePattern.FindAllString(line, -1)
+	if len(dates) == 0 {
+		return dateInfo{}, false
+	}
+	if lang.present.MatchString(line) {
+		return dateInfo{start: dates[0], end: "Present"}, true
+	}
+	if len(dates) >= 2 {
+		return dateInfo{start: dates[0], end: dates[1]}, true
+	}
+	return dateInfo{start: dates[0], end: dates[0]}, true
+}
+
+// stripDates removes the dates extractDates finds from a line, along with
+// the separators left around them
+func (lang *languagePack) stripDates(line string) string {
+	line = lang.dateRange.ReplaceAllString(line, "")
+	return strings.Trim(strings.Join(strings.Fields(line), " "), " ,|-–—()")
+}
+
+// isDegree reports whether a line names a degree, such as "B.Sc." or
+// "Licenciatura"
+func (lang *languagePack) isDegree(line string) bool {
+	return lang.degree.MatchString(line)
+}
@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "english", text: "EXPERIENCE\nSoftware engineer at Acme and the platform team", want: "en"},
+		{name: "german", text: "BERUFSERFAHRUNG\nEntwicklung von Diensten für die Plattform und das Team", want: "de"},
+		{name: "french", text: "EXPÉRIENCE PROFESSIONNELLE\nDéveloppement des services pour les équipes et la plateforme", want: "fr"},
+		{name: "spanish", text: "EXPERIENCIA LABORAL\nDesarrollo de servicios para los equipos y la plataforma", want: "es"},
+		{name: "portuguese", text: "EXPERIÊNCIA PROFISSIONAL\nDesenvolvimento de serviços para as equipes e uma plataforma", want: "pt"},
+		{name: "too little evidence", text: "Jane Doe\nund", want: "en"},
+		{name: "empty", text: "", want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectLanguage(strings.Split(tt.text, "\n")).Code; got != tt.want {
+				t.Errorf("detectLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractDates(t *testing.T) {
+	tests := []struct {
+		lang      string
+		line      string
+		wantStart string
+		wantEnd   string
+		wantOK    bool
+	}{
+		{lang: "en", line: "Acme Corp | Jan 2020 - Present", wantStart: "Jan 2020", wantEnd: "Present", wantOK: true},
+		{lang: "en", line: "Sep 2013 – May 2017", wantStart: "Sep 2013", wantEnd: "May 2017", wantOK: true},
+		{lang: "en", line: "2019 to current", wantStart: "2019", wantEnd: "Present", wantOK: true},
+		{lang: "en", line: "06/2018", wantStart: "06/2018", wantEnd: "06/2018", wantOK: true},
+		{lang: "en", line: "Led the 2019 migration now used by 3 teams", wantStart: "2019", wantEnd: "2019", wantOK: true},
+		{lang: "en", line: "Built tools used today by every team", wantOK: false},
+		{lang: "de", line: "März 2020 - heute", wantStart: "März 2020", wantEnd: "Present", wantOK: true},
+		{lang: "fr", line: "janv. 2019 à aujourd'hui", wantStart: "janv. 2019", wantEnd: "Present", wantOK: true},
+		{lang: "es", line: "enero de 2018 - actualidad", wantStart: "enero de 2018", wantEnd: "Present", wantOK: true},
+		{lang: "pt", line: "Março 2021 até o momento", wantStart: "Março 2021", wantEnd: "Present", wantOK: true},
+		{lang: "de", line: "Jan 2020 - Present", wantStart: "Jan 2020", wantEnd: "Present", wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.line, func(t *testing.T) {
+			got, ok := languagePacks[tt.lang].extractDates(tt.line)
+			if ok != tt.wantOK || got.start != tt.wantStart || got.end != tt.wantEnd {
+				t.Errorf("extractDates() = %+v, %v, want %q-%q, %v", got, ok, tt.wantStart, tt.wantEnd, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestStripDates(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "Acme Corp, Berlin | Jan 2020 - Present", want: "Acme Corp, Berlin"},
+		{line: "Software Engineer (2018 – 2020)", want: "Software Engineer"},
+		{line: "State University", want: "State University"},
+	}
+	for _, tt := range tests {
+		if got := languagePacks["en"].stripDates(tt.line); got != tt.want {
+			t.Errorf("stripDates(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestIsDegree(t *testing.T) {
+	tests := []struct {
+		lang string
+		line string
+		want bool
+	}{
+		{lang: "en", line: "B.S. Computer Science", want: true},
+		{lang: "en", line: "Master of Science in Informatics", want: true},
+		{lang: "en", line: "Acme Corp", want: false},
+		{lang: "de", line: "M.Sc. Informatik", want: true},
+		{lang: "es", line: "Licenciatura en Informática", want: true},
+		{lang: "en", line: "Licenciatura en Informática", want: false},
+	}
+	for _, tt := range tests {
+		if got := languagePacks[tt.lang].isDegree(tt.line); got != tt.want {
+			t.Errorf("%s isDegree(%q) = %v, want %v", tt.lang, tt.line, got, tt.want)
+		}
+	}
+}
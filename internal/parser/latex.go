@@ -0,0 +1,683 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"resumeparser/internal/models"
+	"strings"
+)
+
+// ParseLaTeX builds a resume from the LaTeX source of a moderncv, awesome-cv
+// or Jake's resume template. Sections, entries and items come from the
+// template's commands, so no layout heuristics are involved; only a header
+// written by hand above the first section is read as text.
+func (p *Parser) ParseLaTeX(src string) (*models.Resume, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, fmt.Errorf("empty input")
+	}
+
+	r := &latexReader{
+		p:        p,
+		contact:  &models.ContactContent{Email: make([]string, 0), Number: make([]string, 0), Social: make(map[string]string)},
+		sections: make(map[string]*latexSection),
+		meta:     make(map[string]string),
+	}
+	preamble, body, ok := strings.Cut(src, `\begin{document}`)
+	if !ok {
+		preamble, body = "", src
+	}
+	body, _, _ = strings.Cut(body, `\end{document}`)
+	bodyTokens := tokenizeTeX(body)
+	r.lang = detectLanguage(strings.Split(texRaw(bodyTokens), "\n"))
+
+	r.preamble = true
+	r.walk(tokenizeTeX(preamble))
+	r.buf.Reset()
+	r.links = nil
+	r.preamble = false
+	r.walk(bodyTokens)
+	r.flush()
+
+	return r.resume(), nil
+}
+
+// latexReader walks the tokens of a LaTeX resume and fills in sections
+type latexReader struct {
+	p        *Parser
+	class    string // document class, which decides the argument order of \cventry
+	preamble bool
+	contact  *models.ContactContent
+	sections map[string]*latexSection
+	order    []string
+	section  *latexSection // nil before the first section
+	meta     map[string]string
+	lang     *languagePack // language of the body text
+
+	buf   strings.Builder // running text not yet assigned to a line
+	links []string        // link targets found since the last flush
+}
+
+// latexSection collects the content of one resume section
+type latexSection struct {
+	name     string
+	heading  string // title of the first \section of this name
+	typ      models.SectionType
+	timeline models.TimelineContent
+	list     models.ListContent
+	freeform models.FreeformContent
+	category string // current \subsection of a list section
+}
+
+// texSkipArgs lists commands whose arguments are layout settings or
+// definitions rather than text, with the number of arguments to drop
+var texSkipArgs = map[string]int{
+	"usepackage": 1, "RequirePackage": 1, "input": 1, "include": 1, "label": 1,
+	"newcommand": 2, "renewcommand": 2, "providecommand": 2, "newcommand*": 2, "renewcommand*": 2,
+	"newenvironment": 3, "renewenvironment": 3, "newlength": 1, "newcounter": 1,
+	"setlength": 2, "addtolength": 2, "setcounter": 2, "addtocounter": 2, "stepcounter": 1,
+	"vspace": 1, "vspace*": 1, "hspace": 1, "hspace*": 1, "enlargethispage": 1,
+	"color": 1, "textcolor": 1, "colorbox": 1, "definecolor": 3, "colorlet": 2, "pagecolor": 1,
+	"geometry": 1, "pagestyle": 1, "thispagestyle": 1, "fancyhf": 1, "fancyhead": 1, "fancyfoot": 1,
+	"titleformat": 5, "titlespacing": 5, "titlespacing*": 5, "setlist": 1, "urlstyle": 1,
+	"raisebox": 1, "parbox": 1, "multicolumn": 2, "rule": 2, "fontsize": 2, "resizebox": 2, "scalebox": 1,
+	"includegraphics": 1, "photo": 1, "moderncvstyle": 1, "moderncvcolor": 1, "moderncvtheme": 2,
+	"setmainfont": 1, "setsansfont": 1, "fontspec": 1, "quote": 1, "position": 1, "extrainfo": 1,
+	"makecvfooter": 3, "date": 1,
+}
+
+// latexProfiles gives the profile URL prefix for social handles such as
+// moderncv's \social[github]{jsmith} or awesome-cv's \github{jsmith}
+var latexProfiles = map[string]string{
+	"linkedin":      "https://www.linkedin.com/in/",
+	"github":        "https://github.com/",
+	"gitlab":        "https://gitlab.com/",
+	"twitter":       "https://twitter.com/",
+	"stackoverflow": "https://stackoverflow.com/users/",
+	"kaggle":        "https://www.kaggle.com/",
+	"medium":        "https://medium.com/@",
+	"leetcode":      "https://leetcode.com/",
+}
+
+var (
+	texParagraph = regexp.MustCompile(`\n[ \t]*\n`)
+	texDate      = regexp.MustCompile(`(?i)\b(?:19|20)\d{2}\b|\bpresent\b|\bcurrent\b`)
+	texYearRange = regexp.MustCompile(`(?i)^(\d{4})\s*-\s*(\d{4}|present|current|now)$`)
+	texEmail     = regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
+	texWebsite   = regexp.MustCompile(`^(?:https?://|www\.)\S+$|^[\w-]+(?:\.[\w-]+)+/\S*$`)
+)
+
+// walk interprets tokens, adding their text and entries to the current section
+func (r *latexReader) walk(toks []texToken) {
+	c := &texCursor{toks: toks}
+	for tok, ok := c.next(); ok; tok, ok = c.next() {
+		switch tok.kind {
+		case texText:
+			for i, part := range texParagraph.Split(tok.text, -1) {
+				if i > 0 {
+					r.flush()
+				}
+				r.buf.WriteString(part)
+			}
+		case texGroup:
+			r.walk(tok.group)
+		case texCommand:
+			r.command(tok.text, c)
+		}
+	}
+}
+
+// command handles a control sequence and reads its arguments from c
+func (r *latexReader) command(name string, c *texCursor) {
+	switch name {
+	case "documentclass":
+		c.optional()
+		r.class = texRaw(c.arg())
+	case "section", "section*", "cvsection":
+		c.optional()
+		r.startSection(r.plain(c.arg()))
+		if name == "cvsection" && r.class == "" {
+			r.class = "awesome-cv"
+		}
+	case "subsection", "subsection*", "cvsubsection":
+		c.optional()
+		r.subsection(r.plain(c.arg()))
+	case "item":
+		r.flush()
+		if label, ok := c.optional(); ok {
+			if text := r.plain(label); text != "" {
+				r.buf.WriteString(text + ": ")
+			}
+		}
+	case `\`, "newline", "linebreak":
+		c.optional()
+		r.buf.WriteByte('\n')
+	case "par":
+		r.flush()
+	case "begin":
+		r.begin(texRaw(c.arg()), c)
+	case "end":
+		c.arg()
+		r.flush()
+
+	// Contact details and document properties
+	case "name":
+		name := r.plain(c.arg())
+		if last, ok := c.group(); ok {
+			name += " " + r.plain(last)
+		}
+		r.contact.Name = strings.TrimSpace(name)
+	case "firstname":
+		r.contact.Name = strings.TrimSpace(r.plain(c.arg()) + " " + r.contact.Name)
+	case "familyname", "lastname":
+		r.contact.Name = strings.TrimSpace(r.contact.Name + " " + r.plain(c.arg()))
+	case "email":
+		addContactLink(r.contact, "mailto:"+texRaw(c.arg()))
+	case "phone", "mobile":
+		c.optional()
+		addContactLink(r.contact, "tel:"+r.plain(c.arg()))
+	case "homepage":
+		r.website(texRaw(c.arg()))
+	case "social":
+		kind, _ := c.optional()
+		r.social(texRaw(kind), texRaw(c.arg()))
+	case "linkedin", "github", "gitlab", "twitter", "stackoverflow", "kaggle", "medium", "leetcode":
+		r.social(name, texRaw(c.arg()))
+	case "address":
+		parts := []string{r.plain(c.arg())}
+		for i := 0; i < 2; i++ {
+			if arg, ok := c.group(); ok {
+				parts = append(parts, r.plain(arg))
+			}
+		}
+		r.contact.Location = joinNonEmpty(", ", parts...)
+	case "title":
+		c.optional()
+		r.meta[models.MetaTitle] = r.plain(c.arg())
+	case "author":
+		r.meta[models.MetaAuthor] = r.plain(c.arg())
+	case "hypersetup":
+		for _, option := range strings.Split(texRaw(c.arg()), ",") {
+			key, value, _ := strings.Cut(option, "=")
+			switch strings.TrimSpace(key) {
+			case "pdftitle":
+				r.meta[models.MetaTitle] = strings.TrimSpace(value)
+			case "pdfauthor":
+				r.meta[models.MetaAuthor] = strings.TrimSpace(value)
+			}
+		}
+
+	// Entries of moderncv, awesome-cv and Jake's resume
+	case "cventry":
+		r.flush()
+		c.optional()
+		entry := models.TimelineEntry{}
+		if r.class == "awesome-cv" {
+			// \cventry{position}{organization}{location}{dates}{description}
+			entry.Title = r.plain(c.arg())
+			entry.Organization = r.plain(c.arg())
+			entry.Location = r.plain(c.arg())
+			entry.StartDate, entry.EndDate = splitDates(r.plain(c.arg()))
+		} else {
+			// \cventry{dates}{title}{organization}{location}{grade}{description}
+			entry.StartDate, entry.EndDate = splitDates(r.plain(c.arg()))
+			entry.Title = r.plain(c.arg())
+			entry.Organization = r.plain(c.arg())
+			entry.Location = r.plain(c.arg())
+			if grade := r.plain(c.arg()); grade != "" {
+				entry.Metadata = map[string]string{"grade": grade}
+			}
+		}
+		r.addEntry(entry)
+		r.walk(c.arg())
+		r.flush()
+	case "cvhonor":
+		// \cvhonor{award}{event}{location}{date}
+		r.flush()
+		entry := models.TimelineEntry{Title: r.plain(c.arg()), Organization: r.plain(c.arg()), Location: r.plain(c.arg())}
+		entry.StartDate, entry.EndDate = splitDates(r.plain(c.arg()))
+		r.addEntry(entry)
+	case "resumeSubheading":
+		// \resumeSubheading{organization}{location}{title}{dates} in
+		// education, and {title}{dates}{organization}{location} in the
+		// template's experience section
+		r.flush()
+		args := [4]string{r.plain(c.arg()), r.plain(c.arg()), r.plain(c.arg()), r.plain(c.arg())}
+		entry := models.TimelineEntry{Organization: args[0], Location: args[1], Title: args[2]}
+		dates := args[3]
+		if texDate.MatchString(args[1]) && !texDate.MatchString(args[3]) {
+			entry = models.TimelineEntry{Title: args[0], Organization: args[2], Location: args[3]}
+			dates = args[1]
+		}
+		entry.StartDate, entry.EndDate = splitDates(dates)
+		r.addEntry(entry)
+	case "resumeSubSubheading":
+		// Another role at the organization of the previous entry
+		r.flush()
+		entry := models.TimelineEntry{Title: r.plain(c.arg())}
+		entry.StartDate, entry.EndDate = splitDates(r.plain(c.arg()))
+		if r.section != nil && len(r.section.timeline.Entries) > 0 {
+			entry.Organization = r.section.timeline.Entries[len(r.section.timeline.Entries)-1].Organization
+		}
+		r.addEntry(entry)
+	case "resumeProjectHeading":
+		// \resumeProjectHeading{\textbf{name} $|$ \emph{technologies}}{dates}
+		r.flush()
+		project, technologies, _ := strings.Cut(r.plain(c.arg()), "|")
+		entry := models.TimelineEntry{Organization: strings.TrimSpace(project)}
+		if technologies = strings.TrimSpace(technologies); technologies != "" {
+			entry.Metadata = map[string]string{"technologies": technologies}
+		}
+		entry.StartDate, entry.EndDate = splitDates(r.plain(c.arg()))
+		r.addEntry(entry)
+	case "resumeItem", "cvlistitem":
+		r.flush()
+		c.optional()
+		r.walk(c.arg())
+		r.flush()
+	case "resumeSubItem":
+		r.flush()
+		r.addLine([]string{joinNonEmpty(": ", r.plain(c.arg()), r.plain(c.arg()))})
+	case "cvitem", "cvitemwithcomment", "cvskill":
+		r.flush()
+		c.optional()
+		label, text := r.plain(c.arg()), r.plain(c.arg())
+		if name == "cvitemwithcomment" {
+			text = joinNonEmpty(", ", text, r.plain(c.arg()))
+		}
+		r.addItem(label, text)
+	case "cvdoubleitem":
+		r.flush()
+		r.addItem(r.plain(c.arg()), r.plain(c.arg()))
+		r.addItem(r.plain(c.arg()), r.plain(c.arg()))
+	case "cvlistdoubleitem":
+		r.flush()
+		r.addLine([]string{r.plain(c.arg())})
+		r.addLine([]string{r.plain(c.arg())})
+
+	default:
+		r.buf.WriteString(r.inline(name, c))
+	}
+}
+
+// begin handles the start of an environment
+func (r *latexReader) begin(env string, c *texCursor) {
+	switch env {
+	case "tabular", "tabular*", "tabularx", "minipage", "multicols":
+		c.optional()
+		c.arg()
+		if env == "tabular*" || env == "tabularx" {
+			c.arg()
+		}
+	case "comment":
+		for tok, ok := c.next(); ok; tok, ok = c.next() {
+			if tok.kind == texCommand && tok.text == "end" && texRaw(c.arg()) == "comment" {
+				return
+			}
+		}
+	case "itemize", "enumerate", "description", "cvitems", "cventries", "cvhonors", "cvskills", "cvparagraph":
+		r.flush()
+		c.optional()
+	}
+}
+
+// inline returns the text printed by a command that doesn't structure the
+// resume, consuming the arguments that aren't printed
+func (r *latexReader) inline(name string, c *texCursor) string {
+	if n, ok := texSkipArgs[name]; ok {
+		c.skipArgs(n)
+		return ""
+	}
+	switch name {
+	case "href":
+		uri := texRaw(c.arg())
+		r.addLink(uri)
+		return r.plain(c.arg())
+	case "url":
+		uri := texRaw(c.arg())
+		r.addLink(uri)
+		return uri
+	case "def":
+		// \def\name#1{body}
+		for tok, ok := c.next(); ok && tok.kind != texGroup; tok, ok = c.next() {
+		}
+	}
+	// Formatting commands print nothing themselves; the groups after
+	// them are read as text
+	return ""
+}
+
+// plain returns the text of an argument on a single line
+func (r *latexReader) plain(toks []texToken) string {
+	var b strings.Builder
+	c := &texCursor{toks: toks}
+	for tok, ok := c.next(); ok; tok, ok = c.next() {
+		switch tok.kind {
+		case texText:
+			b.WriteString(tok.text)
+		case texGroup:
+			b.WriteString(r.plain(tok.group))
+		case texCommand:
+			switch tok.text {
+			case `\`, "newline", "linebreak", "item":
+				c.optional()
+				b.WriteByte(' ')
+			case "begin", "end":
+				c.arg()
+			default:
+				b.WriteString(r.inline(tok.text, c))
+			}
+		}
+	}
+	return cleanTeX(b.String())
+}
+
+// cleanTeX applies TeX's dash and quote ligatures and collapses white space
+func cleanTeX(s string) string {
+	s = strings.NewReplacer("---", "—", "--", "–", "``", "“", "''", "”").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func (r *latexReader) addLink(uri string) {
+	if uri != "" && !contains(r.links, uri) {
+		r.links = append(r.links, uri)
+	}
+}
+
+// flush ends the running text, adding each of its lines to the current
+// section. Table cells stay separate so "Languages & Go, Python" can
+// become a skill category.
+func (r *latexReader) flush() {
+	text := r.buf.String()
+	r.buf.Reset()
+	for _, line := range strings.Split(text, "\n") {
+		var cells []string
+		for _, cell := range strings.Split(line, "\t") {
+			if cell = cleanTeX(cell); cell != "" {
+				cells = append(cells, cell)
+			}
+		}
+		if len(cells) > 0 {
+			r.addLine(cells)
+		}
+	}
+	if r.preamble {
+		r.links = nil
+		return
+	}
+	r.attachLinks()
+}
+
+// attachLinks gives the links found so far to the latest entry, or to the
+// contact details when they were in the header
+func (r *latexReader) attachLinks() {
+	links := r.links
+	r.links = nil
+	switch {
+	case r.section == nil || r.section.typ == models.ContactSection:
+		for _, uri := range links {
+			addContactLink(r.contact, uri)
+		}
+	case r.section.typ == models.TimelineSection && len(r.section.timeline.Entries) > 0:
+		entry := &r.section.timeline.Entries[len(r.section.timeline.Entries)-1]
+		for _, uri := range links {
+			if !contains(entry.Links, uri) {
+				entry.Links = append(entry.Links, uri)
+			}
+		}
+	}
+}
+
+// startSection makes the section a heading names current, reusing it when
+// the heading repeats
+func (r *latexReader) startSection(title string) {
+	r.flush()
+	name, _, _ := r.p.classifySection([]Line{{Text: title, Header: true}}, 0, r.lang)
+	if name == "" {
+		name = sectionKey(title)
+	}
+	if name == "" {
+		return
+	}
+	if s, ok := r.sections[name]; ok {
+		r.section = s
+		s.category = ""
+		return
+	}
+	r.section = &latexSection{name: name, heading: strings.TrimSpace(title), typ: r.p.getSectionType(name)}
+	r.sections[name] = r.section
+	r.order = append(r.order, name)
+}
+
+// subsection starts a skill category, or a heading in freeform sections
+func (r *latexReader) subsection(title string) {
+	r.flush()
+	switch {
+	case r.section == nil:
+	case r.section.typ == models.ListSection:
+		r.section.category = title
+	case r.section.typ == models.FreeformSection:
+		r.section.freeform.Entries = append(r.section.freeform.Entries, models.FreeformEntry{Heading: title})
+	}
+}
+
+// addEntry adds a timeline entry, or its text where the section isn't a
+// timeline
+func (r *latexReader) addEntry(entry models.TimelineEntry) {
+	dates := entry.StartDate
+	if entry.EndDate != "" && entry.EndDate != entry.StartDate {
+		dates += " – " + entry.EndDate
+	}
+	switch {
+	case r.section == nil || r.section.typ == models.ContactSection:
+		r.addLine([]string{joinNonEmpty(" | ", entry.Title, entry.Organization, entry.Location, dates)})
+	case r.section.typ == models.TimelineSection:
+		entry.Details = make([]string, 0)
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]string)
+		}
+		entry.Links = r.links
+		r.links = nil
+		r.section.timeline.Entries = append(r.section.timeline.Entries, entry)
+	case r.section.typ == models.ListSection:
+		r.addItems(r.section.category, []string{joinNonEmpty(", ", entry.Title, entry.Organization, entry.Location, dates)})
+	default:
+		r.section.freeform.Entries = append(r.section.freeform.Entries, models.FreeformEntry{
+			Heading: joinNonEmpty(", ", entry.Title, entry.Organization),
+			Content: nonEmpty(joinNonEmpty(", ", entry.Location, dates)),
+		})
+	}
+}
+
+// addItem adds a labelled item such as \cvitem{Languages}{Go, Python}
+func (r *latexReader) addItem(label, text string) {
+	switch {
+	case r.section != nil && r.section.typ == models.ListSection:
+		r.addItems(label, parseItems(text))
+	case r.section != nil && r.section.typ == models.FreeformSection:
+		r.section.freeform.Entries = append(r.section.freeform.Entries, models.FreeformEntry{
+			Heading: label,
+			Content: nonEmpty(text),
+		})
+	default:
+		r.addLine([]string{joinNonEmpty(": ", label, text)})
+	}
+}
+
+// addLine adds a line of text, split into table cells, to the current section
+func (r *latexReader) addLine(cells []string) {
+	text := strings.Join(cells, " | ")
+	switch {
+	case r.preamble:
+	case r.section == nil || r.section.typ == models.ContactSection:
+		r.headerLine(cells)
+	case r.section.typ == models.TimelineSection:
+		entries := r.section.timeline.Entries
+		if len(entries) == 0 {
+			r.addEntry(models.TimelineEntry{Organization: text})
+			return
+		}
+		entries[len(entries)-1].Details = append(entries[len(entries)-1].Details, text)
+	case r.section.typ == models.ListSection:
+		// Rows such as "Languages: Go, Python" or a two-column table
+		// name a category
+		if len(cells) == 2 {
+			r.addItems(cells[0], parseItems(cells[1]))
+		} else if label, items, ok := strings.Cut(text, ":"); ok && strings.TrimSpace(items) != "" && len(strings.Fields(label)) <= 4 {
+			r.addItems(strings.TrimSpace(label), parseItems(items))
+		} else {
+			r.addItems(r.section.category, []string{text})
+		}
+	default:
+		entries := &r.section.freeform.Entries
+		if len(*entries) == 0 {
+			*entries = append(*entries, models.FreeformEntry{})
+		}
+		last := &(*entries)[len(*entries)-1]
+		last.Content = append(last.Content, text)
+	}
+}
+
+// addItems appends items to the named category of a list section
+func (r *latexReader) addItems(name string, items []string) {
+	categories := &r.section.list.Categories
+	for i := range *categories {
+		if (*categories)[i].Name == name {
+			(*categories)[i].Items = append((*categories)[i].Items, items...)
+			return
+		}
+	}
+	*categories = append(*categories, models.ListCategory{Name: name, Items: append(make([]string, 0), items...)})
+}
+
+// headerLine reads contact details from a header laid out by hand, e.g.
+// "555-123-4567 | jane@example.com | linkedin.com/in/jane"
+func (r *latexReader) headerLine(cells []string) {
+	for _, cell := range cells {
+		for _, part := range strings.FieldsFunc(cell, func(r rune) bool {
+			return r == '|' || r == '·' || r == '•' || r == '⋄'
+		}) {
+			part = strings.TrimSpace(part)
+			switch {
+			case part == "":
+			case texEmail.MatchString(part):
+				addContactLink(r.contact, "mailto:"+texEmail.FindString(part))
+			case texWebsite.MatchString(part):
+				r.website(part)
+			case containsPhoneNumber(part):
+				addContactLink(r.contact, "tel:"+part)
+			case r.contact.Name == "":
+				r.contact.Name = part
+			case r.contact.Location == "" && strings.Contains(part, ","):
+				r.contact.Location = part
+			}
+		}
+	}
+}
+
+// website records a homepage or profile URL, which may lack its scheme
+func (r *latexReader) website(uri string) {
+	if uri == "" {
+		return
+	}
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
+	}
+	addContactLink(r.contact, uri)
+}
+
+// social records a profile given by its handle
+func (r *latexReader) social(kind, handle string) {
+	kind = strings.ToLower(kind)
+	handle = strings.TrimPrefix(handle, "@")
+	switch {
+	case handle == "":
+	case strings.Contains(handle, "/") && strings.Contains(handle, "."):
+		r.website(handle)
+	case latexProfiles[kind] != "":
+		r.contact.Social[kind] = latexProfiles[kind] + handle
+	case kind != "":
+		r.contact.Social[kind] = handle
+	}
+}
+
+// resume assembles the sections read from the source
+func (r *latexReader) resume() *models.Resume {
+	resume := &models.Resume{
+		Raw:      make(map[string]string),
+		Metadata: make(map[string]string),
+	}
+
+	c := r.contact
+	if c.Name != "" || len(c.Email) > 0 || len(c.Number) > 0 || c.Location != "" || len(c.Social) > 0 {
+		resume.Sections = append(resume.Sections, models.Sections{Name: contactSection, Type: models.ContactSection, Content: c})
+	}
+	for _, name := range r.order {
+		s := r.sections[name]
+		var content interface{}
+		switch s.typ {
+		case models.TimelineSection:
+			if len(s.timeline.Entries) > 0 {
+				content = &s.timeline
+			}
+		case models.ListSection:
+			if len(s.list.Categories) > 0 {
+				content = &s.list
+			}
+		case models.FreeformSection:
+			if len(s.freeform.Entries) > 0 {
+				content = &s.freeform
+			}
+		}
+		if content != nil {
+			// Sectioning commands declare their headers outright
+			resume.Sections = append(resume.Sections, models.Sections{Name: name, Heading: s.heading, Type: s.typ, Content: content, Confidence: 1})
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Identified sections: %s\n", strings.Join(r.order, ", "))
+
+	for key, value := range r.meta {
+		if value != "" {
+			resume.Metadata[key] = value
+		}
+	}
+	if resume.Metadata[models.MetaAuthor] == "" && c.Name != "" {
+		resume.Metadata[models.MetaAuthor] = c.Name
+	}
+	if r.class != "" {
+		resume.Metadata[models.MetaCreator] = r.class
+	}
+	resume.Metadata[models.MetaDetectedLanguage] = r.lang.Code
+	return resume
+}
+
+// splitDates splits a range such as "Aug. 2018 – May 2021" into its ends.
+// A single date is both the start and the end, as in extractDates.
+func splitDates(s string) (string, string) {
+	if m := texYearRange.FindStringSubmatch(s); m != nil {
+		return m[1], m[2]
+	}
+	for _, sep := range []string{"–", "—", " - ", " to "} {
+		if start, end, ok := strings.Cut(s, sep); ok && strings.TrimSpace(start) != "" && strings.TrimSpace(end) != "" {
+			return strings.TrimSpace(start), strings.TrimSpace(end)
+		}
+	}
+	return s, s
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"reflect"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+// texString writes tokens back out, marking commands and groups
+func texString(toks []texToken) string {
+	var b strings.Builder
+	for _, tok := range toks {
+		switch tok.kind {
+		case texText:
+			b.WriteString(tok.text)
+		case texCommand:
+			b.WriteString(`\` + tok.text + " ")
+		case texGroup:
+			b.WriteString("{" + texString(tok.group) + "}")
+		}
+	}
+	return b.String()
+}
+
+func TestTokenizeTeX(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: `Caf\'e \& Bar`, want: "Café & Bar"},
+		{src: `\"{o} \c c \ss{}`, want: "ö ç ß{}"},
+		{src: "a % note\n   b", want: "a b"},
+		{src: `\textbf{Go} and \emph{C}`, want: `\textbf {Go} and \emph {C}`},
+		{src: `x & y`, want: "x \t y"},
+		{src: `$x$`, want: "x"},
+		{src: `[opt]{arg}`, want: "[opt]{arg}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			if got := texString(tokenizeTeX(tt.src)); got != tt.want {
+				t.Errorf("tokenizeTeX(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitDates(t *testing.T) {
+	tests := []struct {
+		text       string
+		start, end string
+	}{
+		{text: "Aug. 2018 -- May 2021", start: "Aug. 2018", end: "May 2021"},
+		{text: "2018--2021", start: "2018", end: "2021"},
+		{text: "June 2020 -- Present", start: "June 2020", end: "Present"},
+		{text: "Jan 2020 to Dec 2021", start: "Jan 2020", end: "Dec 2021"},
+		{text: "2019", start: "2019", end: "2019"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			start, end := splitDates(cleanTeX(tt.text))
+			if start != tt.start || end != tt.end {
+				t.Errorf("splitDates(%q) = %q, %q, want %q, %q", tt.text, start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseLaTeX(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		contact *models.ContactContent
+		entry   models.TimelineEntry
+		skills  []models.ListCategory
+	}{
+		{
+			name: "moderncv",
+			src: `\documentclass{moderncv}
+\name{Jane}{Doe}
+\email{jane@example.com}
+\phone{+1 555 123 4567}
+\social[github]{janedoe}
+\begin{document}
+\makecvtitle
+\section{Experience}
+\cventry{2018--2021}{Engineer}{Acme Corp}{Berlin}{}{Built things}
+\section{Skills}
+\cvitem{Languages}{Go, Python}
+\end{document}`,
+			contact: &models.ContactContent{
+				Name:   "Jane Doe",
+				Email:  []string{"jane@example.com"},
+				Number: []string{"+1 555 123 4567"},
+				Social: map[string]string{"github": "https://github.com/janedoe"},
+			},
+			entry:  models.TimelineEntry{Organization: "Acme Corp", Location: "Berlin", Title: "Engineer", StartDate: "2018", EndDate: "2021", Details: []string{"Built things"}, Metadata: map[string]string{}},
+			skills: []models.ListCategory{{Name: "Languages", Items: []string{"Go", "Python"}}},
+		},
+		{
+			name: "jake",
+			src: `\begin{document}
+\begin{center}
+{\Huge Jane Doe} \\ \href{mailto:jane@example.com}{jane@example.com} $|$ 555-123-4567
+\end{center}
+\section{Experience}
+\resumeSubHeadingListStart
+\resumeSubheading{Engineer}{Jan 2020 -- Present}{Acme Corp}{Berlin}
+\resumeItemListStart
+\resumeItem{Built things}
+\resumeItemListEnd
+\resumeSubHeadingListEnd
+\section{Technical Skills}
+\textbf{Languages}{: Go, Python}
+\end{document}`,
+			contact: &models.ContactContent{
+				Name:   "Jane Doe",
+				Email:  []string{"jane@example.com"},
+				Number: []string{"555-123-4567"},
+				Social: map[string]string{},
+			},
+			entry:  models.TimelineEntry{Organization: "Acme Corp", Location: "Berlin", Title: "Engineer", StartDate: "Jan 2020", EndDate: "Present", Details: []string{"Built things"}, Metadata: map[string]string{}},
+			skills: []models.ListCategory{{Name: "Languages", Items: []string{"Go", "Python"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resume, err := NewParser().ParseLaTeX(tt.src)
+			if err != nil {
+				t.Fatalf("ParseLaTeX() error = %v", err)
+			}
+			if section, ok := resume.Section(contactSection); !ok || !reflect.DeepEqual(section.Content, tt.contact) {
+				t.Errorf("contact = %+v, want %+v", section.Content, tt.contact)
+			}
+			section, ok := resume.Section("experience")
+			if content, _ := section.Content.(*models.TimelineContent); !ok || content == nil || len(content.Entries) != 1 || !reflect.DeepEqual(content.Entries[0], tt.entry) {
+				t.Errorf("experience = %+v, want %+v", section.Content, tt.entry)
+			}
+			section, ok = resume.Section("skills")
+			if content, _ := section.Content.(*models.ListContent); !ok || content == nil || !reflect.DeepEqual(content.Categories, tt.skills) {
+				t.Errorf("skills = %+v, want %+v", section.Content, tt.skills)
+			}
+		})
+	}
+}
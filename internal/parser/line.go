@@ -0,0 +1,25 @@
+package parser
+
+// Line is a preprocessed line of resume text together with the layout
+// hints the extractor could provide for it.
+type Line struct {
+	Text     string
+	FontSize float64
+	Bold     bool
+	Italic   bool
+	Header   bool     // looks like a section header (all caps or emphasized type)
+	Level    int      // heading level from document styles, 0 for body text
+	Column   int      // layout column the line came from, 0 for full-width text
+	Indent   float64  // offset from the column's left margin in points, or leading spaces for plain text
+	Gap      bool     // set apart from the previous line by a blank line or extra vertical space
+	Links    []string // hyperlink targets anchored on the line
+}
+
+// lineTexts returns the text of each line
+func lineTexts(lines []Line) []string {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.Text
+	}
+	return texts
+}
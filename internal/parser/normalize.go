@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization selects the text repairs applied to lines before sections
+// are identified. PDF text in particular carries ligatures, invisible
+// characters and words hyphenated across lines, which keep headings and
+// skills from matching.
+type Normalization struct {
+	Unicode     bool // NFKC: compatibility forms such as full-width letters become plain ones, and accents are composed
+	Ligatures   bool // ﬁ, ﬂ and other ligatures become their letters
+	Invisible   bool // soft hyphens and zero-width characters are removed, unusual spaces become spaces
+	Hyphenation bool // words broken across lines are rejoined
+	Punctuation bool // smart quotes and dashes become ASCII
+}
+
+// DefaultNormalization enables every step
+func DefaultNormalization() Normalization {
+	return Normalization{Unicode: true, Ligatures: true, Invisible: true, Hyphenation: true, Punctuation: true}
+}
+
+// ParseNormalization reads a comma-separated list of steps: unicode,
+// ligatures, invisible, hyphenation and punctuation, or "all" or "none"
+func ParseNormalization(s string) (Normalization, error) {
+	var n Normalization
+	for _, step := range strings.Split(s, ",") {
+		switch strings.ToLower(strings.TrimSpace(step)) {
+		case "all":
+			n = DefaultNormalization()
+		case "none", "":
+		case "unicode":
+			n.Unicode = true
+		case "ligatures":
+			n.Ligatures = true
+		case "invisible":
+			n.Invisible = true
+		case "hyphenation":
+			n.Hyphenation = true
+		case "punctuation":
+			n.Punctuation = true
+		default:
+			return Normalization{}, fmt.Errorf("unknown normalization step %q", step)
+		}
+	}
+	return n, nil
+}
+
+// apply normalizes the text of each line. Hyphenation is repaired first,
+// while soft hyphens still mark where words were broken.
+func (n Normalization) apply(lines []Line) []Line {
+	if n.Hyphenation {
+		lines = rejoinHyphenation(lines)
+	}
+	for i := range lines {
+		lines[i].Text = n.text(lines[i].Text)
+	}
+	return lines
+}
+
+// text applies the character level steps to a line
+func (n Normalization) text(s string) string {
+	if isPlainASCII(s) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		switch {
+		case n.Invisible && isInvisible(r):
+			continue
+		case n.Invisible && r != ' ' && unicode.Is(unicode.Zs, r):
+			b.WriteByte(' ')
+			continue
+		case n.Ligatures && ligatures[r] != "":
+			b.WriteString(ligatures[r])
+			continue
+		case n.Punctuation && punctuation[r] != 0:
+			b.WriteRune(punctuation[r])
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+	if n.Unicode {
+		return norm.NFKC.String(b.String())
+	}
+	return b.String()
+}
+
+// rejoinHyphenation moves the rest of a word broken across lines back onto
+// the line holding its first half: "develop-" and "ment of" become
+// "development" and "of". The continuation must start in lower case on the
+// next line of the same column. A line left empty is removed.
+func rejoinHyphenation(lines []Line) []Line {
+	for i := 0; i+1 < len(lines); i++ {
+		text := strings.TrimRightFunc(lines[i].Text, unicode.IsSpace)
+		last, size := utf8.DecodeLastRuneInString(text)
+		if last != '-' && last != '\u2010' && last != '\u00ad' {
+			continue
+		}
+		stem := text[:len(text)-size]
+		if r, _ := utf8.DecodeLastRuneInString(stem); !unicode.IsLetter(r) {
+			continue
+		}
+
+		next := &lines[i+1]
+		if next.Column != lines[i].Column {
+			continue
+		}
+		rest := strings.TrimLeftFunc(next.Text, unicode.IsSpace)
+		if r, _ := utf8.DecodeRuneInString(rest); !unicode.IsLower(r) {
+			continue
+		}
+		word, remainder, _ := strings.Cut(rest, " ")
+		lines[i].Text = stem + word
+		next.Text = strings.TrimSpace(remainder)
+		if next.Text == "" {
+			// The line held only the end of the word; an empty line left
+			// behind would read as a paragraph break. The joined word may
+			// itself end in a hyphen, so line i is checked again.
+			lines[i].Links = append(lines[i].Links, next.Links...)
+			lines = slices.Delete(lines, i+1, i+2)
+			i--
+		}
+	}
+	return lines
+}
+
+// isPlainASCII reports whether s is printable ASCII, which no step changes
+func isPlainASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf || s[i] < ' ' && s[i] != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// isInvisible reports whether r has no visible form: soft hyphens,
+// zero-width characters, directional marks and stray control characters
+func isInvisible(r rune) bool {
+	switch {
+	case r == '\u00ad', r == '\u180e', r == '\u2060', r == '\ufeff':
+		return true
+	case r >= '\u200b' && r <= '\u200f', r >= '\u202a' && r <= '\u202e', r >= '\u2066' && r <= '\u2069':
+		return true
+	case unicode.IsControl(r):
+		return r != '\t' && r != '\n' && r != '\f'
+	}
+	return false
+}
+
+var ligatures = map[rune]string{
+	'ﬀ': "ff", 'ﬁ': "fi", 'ﬂ': "fl", 'ﬃ': "ffi", 'ﬄ': "ffl", 'ﬅ': "st", 'ﬆ': "st",
+	'Ĳ': "IJ", 'ĳ': "ij",
+}
+
+var punctuation = map[rune]rune{
+	'‘': '\'', '’': '\'', '‚': '\'', '‛': '\'', '′': '\'',
+	'“': '"', '”': '"', '„': '"', '‟': '"', '″': '"',
+	'‐': '-', '‑': '-', '‒': '-', '–': '-', '—': '-', '―': '-', '−': '-',
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizationText(t *testing.T) {
+	tests := []struct {
+		name string
+		n    Normalization
+		in   string
+		want string
+	}{
+		{name: "plain ascii", n: DefaultNormalization(), in: "Go, Python", want: "Go, Python"},
+		{name: "full-width", n: Normalization{Unicode: true}, in: "ＥＸＰＥＲＩＥＮＣＥ", want: "EXPERIENCE"},
+		{name: "combining accent", n: Normalization{Unicode: true}, in: "Jose\u0301 Mu\u0308ller", want: "Jos\u00e9 M\u00fcller"},
+		{name: "superscript", n: Normalization{Unicode: true}, in: "m²", want: "m2"},
+		{name: "ligatures", n: Normalization{Ligatures: true}, in: "ﬁnance ofﬁce", want: "finance office"},
+		{name: "invisible", n: Normalization{Invisible: true}, in: "soft\u00adware\u200b engi\u00a0neer", want: "software engi neer"},
+		{name: "punctuation", n: Normalization{Punctuation: true}, in: "“Lead” – Jane’s", want: "\"Lead\" - Jane's"},
+		{name: "disabled", n: Normalization{}, in: "ﬁnance “Lead”", want: "ﬁnance “Lead”"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.n.text(tt.in); got != tt.want {
+				t.Errorf("text(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRejoinHyphenation(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []Line
+		want []string
+	}{
+		{
+			name: "word and rest",
+			in:   []Line{{Text: "develop-"}, {Text: "ment of APIs"}},
+			want: []string{"development", "of APIs"},
+		},
+		{
+			name: "single word continuation",
+			in:   []Line{{Text: "A strong commit-"}, {Text: "ment."}, {Text: "Next"}},
+			want: []string{"A strong commitment.", "Next"},
+		},
+		{
+			name: "soft hyphen",
+			in:   []Line{{Text: "manage\u00ad"}, {Text: "ment"}},
+			want: []string{"management"},
+		},
+		{
+			name: "capitalized continuation",
+			in:   []Line{{Text: "Front-"}, {Text: "End"}},
+			want: []string{"Front-", "End"},
+		},
+		{
+			name: "other column",
+			in:   []Line{{Text: "develop-"}, {Text: "ment", Column: 1}},
+			want: []string{"develop-", "ment"},
+		},
+		{
+			name: "dash after a number",
+			in:   []Line{{Text: "2019 -"}, {Text: "present"}},
+			want: []string{"2019 -", "present"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineTexts(rejoinHyphenation(tt.in)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rejoinHyphenation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNormalization(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Normalization
+		wantErr bool
+	}{
+		{in: "all", want: DefaultNormalization()},
+		{in: "none", want: Normalization{}},
+		{in: "ligatures, Punctuation", want: Normalization{Ligatures: true, Punctuation: true}},
+		{in: "spelling", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseNormalization(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseNormalization(%q) = %+v, %v", tt.in, got, err)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"regexp"
 	"resumeparser/internal/models"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -12,58 +14,63 @@ import (
 // Parser represents the resume parser
 type Parser struct {
 	sectionDetectors map[string][]string
+	sectionOrder     []string // section names in config order, which settles ties between matches
+	sectionTypes     map[string]models.SectionType
 	preprocessor     *Preprocessor
 }
 
-// NewParser creates a new Parser instance
-func NewParser() *Parser {
-	p := &Parser{
-		sectionDetectors: make(map[string][]string),
-		preprocessor:     NewPreprocessor(),
-	}
+// Option configures a Parser
+type Option func(*Parser)
 
-	// Initialize section detectors with common variations
-	p.sectionDetectors["education"] = []string{
-		"education",
-		"academic background",
-		"academic history",
-		"educational background",
+// WithNormalization selects the text normalization steps applied before
+// sections are identified
+func WithNormalization(n Normalization) Option {
+	return func(p *Parser) {
+		p.preprocessor.Normalization = n
 	}
+}
 
-	p.sectionDetectors["experience"] = []string{
-		"experience",
-		"work experience",
-		"employment history",
-		"professional experience",
+// WithSections replaces the built-in section definitions. Merge a config
+// onto DefaultSectionConfig to add sections instead.
+func WithSections(config *SectionConfig) Option {
+	return func(p *Parser) {
+		p.setSections(config)
 	}
+}
 
-	p.sectionDetectors["skills"] = []string{
-		"skills",
-		"technical skills",
-		"core competencies",
-		"expertise",
+// NewParser creates a new Parser instance
+func NewParser(opts ...Option) *Parser {
+	p := &Parser{
+		preprocessor: NewPreprocessor(),
 	}
-
-	p.sectionDetectors["projects"] = []string{
-		"projects",
-		"personal projects",
-		"project experience",
+	p.setSections(DefaultSectionConfig())
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
+}
 
-	p.sectionDetectors["achievements"] = []string{
-		"achievements",
-		"awards",
-		"honors",
-		"accomplishments",
+// setSections indexes section definitions by name. The header block is
+// always read as the contact section, so a config without one keeps the
+// built-in definition.
+func (p *Parser) setSections(config *SectionConfig) {
+	p.sectionDetectors = make(map[string][]string)
+	p.sectionOrder = nil
+	p.sectionTypes = make(map[string]models.SectionType)
+	for _, s := range config.Sections {
+		p.sectionDetectors[s.Name] = s.Aliases
+		p.sectionOrder = append(p.sectionOrder, s.Name)
+		p.sectionTypes[s.Name] = s.Type
 	}
-
-	p.sectionDetectors["contact"] = []string{
-		"contact",
-		"contact information",
-		"personal information",
+	if _, ok := p.sectionTypes[contactSection]; !ok {
+		for _, s := range DefaultSectionConfig().Sections {
+			if s.Name == contactSection {
+				p.sectionDetectors[s.Name] = s.Aliases
+				p.sectionOrder = append(p.sectionOrder, s.Name)
+				p.sectionTypes[s.Name] = s.Type
+			}
+		}
 	}
-
-	return p
 }
 
 // Parse the input text and returns a structured Resume
@@ -72,106 +79,270 @@ func (p *Parser) Parse(text string) (*models.Resume, error) {
 		return nil, fmt.Errorf("empty input")
 	}
 
-	lines := p.preprocessor.Process(text)
+	resume, err := p.parseLines(p.preprocessor.Process(text))
+	if err != nil {
+		return nil, err
+	}
+	recordMetadata(resume, nil, strings.Count(text, "\f")+1, text)
+	return resume, nil
+}
+
+// ParseDocument parses extracted layout, letting section detection use
+// typography such as font size and weight
+func (p *Parser) ParseDocument(doc *models.Document) (*models.Resume, error) {
+	if doc == nil || strings.TrimSpace(doc.Text()) == "" {
+		return nil, fmt.Errorf("empty input")
+	}
+
+	resume, err := p.parseLines(p.preprocessor.ProcessDocument(doc))
+	if err != nil {
+		return nil, err
+	}
+	addProfileLinks(resume, doc.Links)
+	recordMetadata(resume, doc.Metadata, len(doc.Pages), doc.Text())
+	return resume, nil
+}
+
+// recordMetadata copies the document properties found by the extractor and
+// adds the page and character counts
+func recordMetadata(resume *models.Resume, meta map[string]string, pages int, text string) {
+	for key, value := range meta {
+		resume.Metadata[key] = value
+	}
+	characters := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			characters++
+		}
+	}
+	if _, ok := resume.Metadata[models.MetaPages]; !ok {
+		resume.Metadata[models.MetaPages] = strconv.Itoa(pages)
+	}
+	resume.Metadata[models.MetaCharacters] = strconv.Itoa(characters)
+}
+
+// parseLines parses the preprocessed lines into sections. The furniture
+// removed from the pages is only searched for contact details.
+func (p *Parser) parseLines(lines, furniture []Line) (*models.Resume, error) {
 	fmt.Fprintf(os.Stderr, "Preprocessed %d lines\n", len(lines))
 
-	sections := p.identifySections(lines)
+	lang := detectLanguage(lineTexts(lines))
+	sections := p.identifySections(lines, lang)
 	var sectionNames []string
-	for name := range sections {
-		sectionNames = append(sectionNames, name)
+	for _, section := range sections {
+		sectionNames = append(sectionNames, fmt.Sprintf("%s (%.2f)", section.name, section.confidence))
 	}
 	fmt.Fprintf(os.Stderr, "Identified sections: %s\n", strings.Join(sectionNames, ", "))
 
 	resume := &models.Resume{
 		Raw:      make(map[string]string),
-		Sections: make(map[string]models.Sections),
-		Metadata: make(map[string]string),
+		Sections: make([]models.Sections, 0, len(sections)),
+		Metadata: map[string]string{models.MetaDetectedLanguage: lang.Code},
 	}
 
 	// Process each section
-	for name, sectionLines := range sections {
-		fmt.Fprintf(os.Stderr, "Processing section: %s (%d lines)\n", name, len(sectionLines))
+	for _, section := range sections {
+		fmt.Fprintf(os.Stderr, "Processing section: %s (%d lines)\n", section.name, len(section.lines))
 
-		sectionType := p.getSectionType(name)
-		content, err := p.parseSection(name, sectionLines)
+		sectionType := p.getSectionType(section.name)
+		content, err := p.parseSection(section.name, section.lines, lang)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing section %s: %w", name, err)
+			return nil, fmt.Errorf("error parsing section %s: %w", section.name, err)
 		}
 
 		// Store the section
-		resume.Sections[name] = models.Sections{
-			Type:    sectionType,
-			Content: content,
+		resume.Sections = append(resume.Sections, models.Sections{
+			Name:       section.name,
+			Heading:    section.heading,
+			Type:       sectionType,
+			Content:    content,
+			Confidence: section.confidence,
+		})
+	}
+
+	// An email address or phone number under "References" is a referee's,
+	// not the candidate's
+	var contactLines []Line
+	for _, section := range sections {
+		if section.name != referencesSection {
+			contactLines = append(contactLines, section.lines...)
 		}
 	}
+	findContactDetails(resume, append(contactLines, furniture...))
 
 	return resume, nil
 }
 
-// identifySections identifies and groups lines into sections
-func (p *Parser) identifySections(lines []string) map[string][]string {
-	sections := make(map[string][]string)
-	var currentSection string
-	var currentLines []string
-
-	for i, line := range lines {
-		if section := p.detectSection(line); section != "" {
-			// Store previous section if it exists
-			if currentSection != "" && len(currentLines) > 0 {
-				sections[currentSection] = currentLines
-			}
-			currentSection = section
-			currentLines = nil
-		} else if currentSection != "" {
-			currentLines = append(currentLines, line)
-		} else if i == 0 {
-			// First line is likely contact information
-			currentSection = "contact"
-			currentLines = append(currentLines, line)
-		}
+// sectionLines are the lines of a section, with its heading as written and
+// the confidence of the header that started it
+type sectionLines struct {
+	name       string
+	heading    string
+	confidence float64
+	lines      []Line
+	continued  bool        // the section's heading repeated; the next line starts a new block
+	style      headerStyle // look of the header that opened the section
+}
+
+// add appends a line to the section, setting apart the first line after a
+// repeated heading
+func (s *sectionLines) add(l Line) {
+	if s.continued {
+		l.Gap = true
+		s.continued = false
 	}
+	s.lines = append(s.lines, l)
+}
 
-	// Add the last section
-	if currentSection != "" && len(currentLines) > 0 {
-		sections[currentSection] = currentLines
+// identifySections identifies and groups lines into sections, in the order
+// they first appear. A heading that repeats, such as "Experience" on the
+// first page and "Experience (continued)" on the next, adds to the section
+// it started before. Each layout column keeps its own current section, so
+// sidebar lines are not appended to a section from the main column.
+// Headers of sections the config doesn't define start a section of their
+// own, recognized by the style of the headers that were matched.
+func (p *Parser) identifySections(lines []Line, lang *languagePack) []*sectionLines {
+	var ordered []*sectionLines
+	byName := make(map[string]*sectionLines)
+	open := func(name string, l Line, heading string, confidence float64) *sectionLines {
+		if s, ok := byName[name]; ok {
+			s.confidence = max(s.confidence, confidence)
+			s.continued = len(s.lines) > 0
+			return s
+		}
+		s := &sectionLines{name: name, heading: heading, confidence: confidence, style: styleOf(l)}
+		byName[name] = s
+		ordered = append(ordered, s)
+		return s
+	}
+	current := make(map[int]*sectionLines)
+	var last *sectionLines
+	var headerBlock []Line
+
+	type header struct {
+		name       string
+		confidence float64
+		inline     string
+	}
+	headers := make([]header, len(lines))
+	styles := make(map[headerStyle]bool)
+	gapped := make(map[headerStyle]bool)
+	for i, l := range lines {
+		name, confidence, inline := p.classifySection(lines, i, lang)
+		headers[i] = header{name, confidence, inline}
+		if name != "" && inline == "" {
+			styles[styleOf(l)] = true
+			gapped[styleOf(l)] = gapped[styleOf(l)] || l.Gap
+		}
 	}
 
-	return sections
-}
+	prevInline := false
+	for i, l := range lines {
+		line := l.Text
+		section, confidence, inline := headers[i].name, headers[i].confidence, headers[i].inline
+		if section == "" && i > 0 {
+			section, confidence, inline = unknownSection(lines, i, styles, gapped)
+			// "Frameworks: React" is a category within skills, and "Stack:
+			// Go" a detail of a job. An unknown label leading its content
+			// only starts a section when it stands out, follows another
+			// section started this way, or follows a gap outside a list.
+			// A label on a line of its own is a category as well when it
+			// doesn't stand out in a list. Right below a header, a short
+			// line is the section's first entry, such as a project's name,
+			// and in a timeline a line on its own styled unlike the
+			// section's header or followed by a date range heads an entry.
+			cur := current[l.Column]
+			inList := cur != nil && p.getSectionType(cur.name) == models.ListSection
+			fresh := cur != nil && (cur.continued || !slices.ContainsFunc(cur.lines, func(l Line) bool { return l.Text != "" }))
+			category := inList && inline == "" && !l.Header && !l.Gap
+			entry := cur != nil && p.getSectionType(cur.name) == models.TimelineSection && inline == "" &&
+				(styleOf(l) != cur.style || headsEntry(lines, i, lang))
+			if fresh || category || entry || inline != "" && !l.Header && !prevInline && !(l.Gap && !inList) {
+				section, inline = "", ""
+			}
+		}
+		if cur := current[l.Column]; section != "" && cur != nil && cur.name == section {
+			// A header repeated within its own section, as on a continued
+			// page or for a category like "Technical Skills: Go", doesn't
+			// start the section over
+			if inline == "" {
+				prevInline = false
+				continue
+			}
+			section = ""
+		}
+		prevInline = section != "" && inline != ""
 
-// detectSection tries to identify if a line is a section header
-func (p *Parser) detectSection(line string) string {
-	line = strings.TrimSpace(strings.ToLower(line))
-	if line == "" {
-		return ""
+		if section != "" {
+			heading := strings.TrimSpace(line)
+			if inline != "" {
+				heading, _, _ = strings.Cut(heading, ":")
+			}
+			current[l.Column] = open(section, l, strings.TrimSpace(strings.TrimSuffix(heading, ":")), confidence)
+			last = current[l.Column]
+			if inline != "" {
+				// Content after "Skills:" is the section's first line
+				first := l
+				first.Text, first.Header, first.Gap = inline, false, false
+				last.add(first)
+			}
+		} else if cur := current[l.Column]; cur != nil {
+			cur.add(l)
+			last = cur
+		} else if last == nil {
+			// Everything above the first header is the header block
+			headerBlock = append(headerBlock, l)
+		} else if line != "" {
+			// A column that starts without a header continues the section
+			// being read when the previous column ended
+			current[l.Column] = last
+			last.add(l)
+		}
 	}
 
-	// Quick check for common section headers
-	for name, patterns := range p.sectionDetectors {
-		for _, pattern := range patterns {
-			if line == pattern {
-				return name
+	// The header block comes before every section. Its contact details and
+	// summary are the start of the sections of those names, should the
+	// resume have them further down as well.
+	contact, summary := splitHeaderBlock(headerBlock)
+	var first []*sectionLines
+	for _, block := range []struct {
+		name  string
+		lines []Line
+	}{{contactSection, contact}, {"summary", summary}} {
+		if len(block.lines) == 0 {
+			continue
+		}
+		s, ok := byName[block.name]
+		if ok {
+			ordered = slices.DeleteFunc(ordered, func(o *sectionLines) bool { return o == s })
+			if len(s.lines) > 0 {
+				s.lines[0].Gap = true
 			}
+		} else {
+			s = &sectionLines{name: block.name}
 		}
+		s.lines = append(block.lines[:len(block.lines):len(block.lines)], s.lines...)
+		first = append(first, s)
 	}
+	ordered = append(first, ordered...)
 
-	return ""
+	sections := ordered[:0]
+	for _, s := range ordered {
+		if len(s.lines) > 0 {
+			sections = append(sections, s)
+		}
+	}
+	return sections
 }
 
 func (p *Parser) getSectionType(name string) models.SectionType {
-	switch name {
-	case "contact":
-		return models.ContactSection
-	case "education", "experience", "projects":
-		return models.TimelineSection
-	case "skills", "achievements":
-		return models.ListSection
-	default:
-		return models.FreeformSection
+	if t, ok := p.sectionTypes[name]; ok {
+		return t
 	}
+	return models.FreeformSection
 }
 
-func (p *Parser) parseSection(name string, lines []string) (interface{}, error) {
+func (p *Parser) parseSection(name string, lines []Line, lang *languagePack) (interface{}, error) {
 	if len(lines) == 0 {
 		return nil, fmt.Errorf("empty section")
 	}
@@ -183,9 +354,9 @@ func (p *Parser) parseSection(name string, lines []string) (interface{}, error)
 	case models.ContactSection:
 		return p.parseContact(lines)
 	case models.TimelineSection:
-		return p.parseTimeline(lines)
+		return p.parseTimeline(lines, lang)
 	case models.ListSection:
-		return p.parseList(lines)
+		return p.parseList(lineTexts(lines))
 	default:
 		return p.parseFreeform(lines)
 	}
@@ -201,14 +372,20 @@ func containsAny(s string, subs ...string) bool {
 	return false
 }
 
-// cleanSectionLines removes empty lines and normalizes formatting
-func (p *Parser) cleanSectionLines(lines []string) []string {
-	var cleaned []string
+// cleanSectionLines removes empty lines and normalizes formatting. An
+// empty line marks a gap before the line that follows it.
+func (p *Parser) cleanSectionLines(lines []Line) []Line {
+	var cleaned []Line
+	gap := false
 	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			cleaned = append(cleaned, line)
+		line.Text = strings.TrimSpace(line.Text)
+		if line.Text == "" {
+			gap = len(cleaned) > 0
+			continue
 		}
+		line.Gap = line.Gap || gap
+		gap = false
+		cleaned = append(cleaned, line)
 	}
 	return cleaned
 }
@@ -220,8 +397,9 @@ func isBulletPoint(line string) bool {
 		return false
 	}
 
-	// Check for numbered bullets (e.g., "1.", "2)", "(1)", "a.", "b)")
-	if matched, _ := regexp.MatchString(`^(?:\d+[\.\)]|\([a-zA-Z0-9]+\)|[a-zA-Z][\.\)])`, line); matched {
+	// Check for numbered bullets (e.g., "1.", "2)", "(1)", "a.", "b)"); a letter
+	// needs a space after it so degrees such as "B.S." are not bullets
+	if matched, _ := regexp.MatchString(`^(?:\d+[\.\)]|\([a-zA-Z0-9]+\)|[a-zA-Z][\.\)](?:\s|$))`, line); matched {
 		return true
 	}
 
@@ -240,7 +418,7 @@ func normalizeBulletPoint(line string) string {
 	line = strings.TrimSpace(line)
 
 	// Handle numbered bullets
-	if matched, _ := regexp.MatchString(`^(?:\d+[\.\)]|\([a-zA-Z0-9]+\)|[a-zA-Z][\.\)])`, line); matched {
+	if matched, _ := regexp.MatchString(`^(?:\d+[\.\)]|\([a-zA-Z0-9]+\)|[a-zA-Z][\.\)](?:\s|$))`, line); matched {
 		// Find the end of the bullet marker
 		idx := strings.IndexFunc(line, func(r rune) bool {
 			return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '(' && r != ')' && r != '.'
@@ -0,0 +1,201 @@
+package parser
+
+import (
+	"reflect"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestIdentifySections(t *testing.T) {
+	type section struct {
+		name  string
+		lines string
+	}
+	tests := []struct {
+		name string
+		text string
+		want []section
+	}{
+		{
+			name: "title case",
+			text: "Jane Doe\nWork Experience\nAcme Corp\nEducation\nTU Berlin\n",
+			want: []section{{"contact", "Jane Doe"}, {"experience", "Acme Corp"}, {"education", "TU Berlin"}},
+		},
+		{
+			name: "underlined",
+			text: "Jane Doe\n\nWork experience\n---------------\nAcme Corp\n",
+			want: []section{{"contact", "Jane Doe"}, {"experience", "Acme Corp"}},
+		},
+		{
+			name: "colon with inline content",
+			text: "Jane Doe\nSkills: Go, Python\nTechnical Skills: Rust\nEducation: TU Berlin\n",
+			want: []section{{"contact", "Jane Doe"}, {"skills", "Go, Python|Technical Skills: Rust"}, {"education", "TU Berlin"}},
+		},
+		{
+			name: "title case after gap",
+			text: "Jane Doe\n\nEducation\nTU Munich\n\nSkills & Tools\nGo, Python\n\nPublications\nParsing resumes, 2021\n\nInterests\nHiking, chess\n",
+			want: []section{{"contact", "Jane Doe"}, {"education", "TU Munich"}, {"skills", "Go, Python"}, {"publications", "Parsing resumes, 2021"}, {"interests", "Hiking, chess"}},
+		},
+		{
+			name: "entry after gap",
+			text: "Jane Doe\n\nExperience\nAcme\nEngineer, 2020 - Present\n\nGlobex\nIntern, 2018 - 2019\n\nEducation\nTU Munich\n",
+			want: []section{{"contact", "Jane Doe"}, {"experience", "Acme|Engineer, 2020 - Present|Globex|Intern, 2018 - 2019"}, {"education", "TU Munich"}},
+		},
+		{
+			name: "title case between gaps",
+			text: "Jane Doe\n\nExperience\nAcme Corp\n\nInterests\n\nHiking, Chess\n",
+			want: []section{{"contact", "Jane Doe"}, {"experience", "Acme Corp"}, {"interests", "Hiking, Chess"}},
+		},
+		{
+			name: "entry below header",
+			text: "Jane Doe\n\nProjects\n--------\nParser\n\nA paragraph about the parser\n",
+			want: []section{{"contact", "Jane Doe"}, {"projects", "Parser|A paragraph about the parser"}},
+		},
+		{
+			name: "inline label",
+			text: "EXPERIENCE\nAcme Corp\n\nLanguages: English, German\n",
+			want: []section{{"experience", "Acme Corp"}, {"languages", "English, German"}},
+		},
+		{
+			name: "categories",
+			text: "SKILLS\nLanguages:\nGo, Python\nFrameworks: React\n",
+			want: []section{{"skills", "Languages:|Go, Python|Frameworks: React"}},
+		},
+		{
+			name: "detail of an entry",
+			text: "EXPERIENCE\nAcme Corp\nStack: Go, Kafka\n",
+			want: []section{{"experience", "Acme Corp|Stack: Go, Kafka"}},
+		},
+		{
+			name: "continued on the next page",
+			text: "Jane Doe\n\nEXPERIENCE\nAcme Corp\n\nEDUCATION\nTU Berlin\n\fEXPERIENCE (continued)\nInitech\n\nSKILLS\nGo\n",
+			want: []section{{"contact", "Jane Doe"}, {"experience", "Acme Corp||Initech|"}, {"education", "TU Berlin"}, {"skills", "Go"}},
+		},
+		{
+			name: "summary after the header block",
+			text: "Jane Doe\nBackend engineer with eight years of experience building payments.\n\nEXPERIENCE\nAcme Corp\n\nSUMMARY\nLeads teams.\n",
+			want: []section{{"contact", "Jane Doe"}, {"summary", "Backend engineer with eight years of experience building payments.|Leads teams."}, {"experience", "Acme Corp|"}},
+		},
+		{
+			name: "first appearance sets the order",
+			text: "EXPERIENCE\nAcme Corp\n\nSUMMARY\nEngineer.\n\nEXPERIENCE\nInitech\n",
+			want: []section{{"experience", "Acme Corp||Initech"}, {"summary", "Engineer.|"}},
+		},
+	}
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, _ := p.preprocessor.Process(tt.text)
+			var got []section
+			for _, s := range p.identifySections(lines, languagePacks["en"]) {
+				got = append(got, section{s.name, strings.Join(lineTexts(s.lines), "|")})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("identifySections() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionLinesAdd(t *testing.T) {
+	tests := []struct {
+		name      string
+		continued bool
+		want      []bool
+	}{
+		{name: "first heading", want: []bool{false, false}},
+		{name: "repeated heading", continued: true, want: []bool{true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sectionLines{name: "experience", continued: tt.continued}
+			s.add(Line{Text: "Initech"})
+			s.add(Line{Text: "Engineer"})
+			for i, l := range s.lines {
+				if l.Gap != tt.want[i] {
+					t.Errorf("line %d gap = %v, want %v", i, l.Gap, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIdentifySectionsEntryHeadings(t *testing.T) {
+	type text struct {
+		text  string
+		bold  bool
+		level int
+		gap   bool
+	}
+	tests := []struct {
+		name  string
+		lines []text
+		want  []string
+	}{
+		{
+			name: "bold entries",
+			lines: []text{
+				{text: "Jane Doe", bold: true},
+				{text: "Experience", bold: true, gap: true},
+				{text: "Acme", bold: true},
+				{text: "Engineer, Jan 2020 - Present"},
+				{text: "Globex", bold: true, gap: true},
+				{text: "Intern, 2018 - 2019"},
+				{text: "Education", bold: true, gap: true},
+				{text: "TU Munich", bold: true},
+				{text: "B.Sc. Informatics, 2014 - 2018"},
+			},
+			want: []string{"contact", "experience", "education"},
+		},
+		{
+			name: "bold entry below header",
+			lines: []text{
+				{text: "Jane Doe", bold: true},
+				{text: "Projects", bold: true, gap: true},
+				{text: "Parser", bold: true},
+				{text: "A tool that reads resumes"},
+			},
+			want: []string{"contact", "projects"},
+		},
+		{
+			name: "markdown levels",
+			lines: []text{
+				{text: "Jane Doe", bold: true, level: 1},
+				{text: "Experience", bold: true, level: 2, gap: true},
+				{text: "Acme Corp - Berlin", bold: true, level: 3, gap: true},
+				{text: "Engineer, Jan 2020 - Present"},
+				{text: "Globex", bold: true, level: 3, gap: true},
+				{text: "Intern, 2018 - 2019"},
+				{text: "Publications", bold: true, level: 2, gap: true},
+				{text: "Parsing resumes, 2021", gap: true},
+			},
+			want: []string{"contact", "experience", "publications"},
+		},
+	}
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := models.Page{Number: 1}
+			y := 50.0
+			for _, l := range tt.lines {
+				y += 14
+				if l.gap {
+					y += 10
+				}
+				page.Lines = append(page.Lines, models.TextLine{
+					Runs:    []models.TextRun{{Text: l.text, X: 50, Y: y, FontSize: 11, Bold: l.bold}},
+					Heading: l.level,
+				})
+			}
+			lines, _ := p.preprocessor.ProcessDocument(&models.Document{Pages: []models.Page{page}})
+			var got []string
+			for _, s := range p.identifySections(lines, languagePacks["en"]) {
+				got = append(got, s.name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("identifySections() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
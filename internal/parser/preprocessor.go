@@ -1,54 +1,254 @@
 package parser
 
 import (
+	"math"
 	"regexp"
+	"resumeparser/internal/models"
+	"sort"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-type Preprocessor struct{}
+type Preprocessor struct {
+	Normalization Normalization // text repairs applied to every line
+}
 
 func NewPreprocessor() *Preprocessor {
-	return &Preprocessor{}
+	return &Preprocessor{Normalization: DefaultNormalization()}
+}
+
+// Process preprocesses the input text. Form feeds separate pages. The
+// running headers, footers and page numbers removed from the pages are
+// returned as furniture.
+func (p *Preprocessor) Process(text string) (lines, furniture []Line) {
+	var pages [][]Line
+	for _, pageText := range strings.Split(text, "\f") {
+		var lines []Line
+		for _, line := range strings.Split(pageText, "\n") {
+			lines = append(lines, Line{Text: line, Indent: float64(countIndentation(line))})
+		}
+		pages = append(pages, lines)
+	}
+	return p.processPages(pages)
 }
 
-// Process preprocesses the input text
-func (p *Preprocessor) Process(text string) []string {
-	// Split text into lines
-	lines := strings.Split(text, "\n")
-	var processed []string
+// ProcessDocument preprocesses extracted layout, using font size and
+// weight in addition to casing to recognize section headers. Like Process,
+// it returns the page furniture apart.
+func (p *Preprocessor) ProcessDocument(doc *models.Document) (lines, furniture []Line) {
+	bodySize := bodyFontSize(doc)
+
+	var pages [][]Line
+	for _, page := range doc.Pages {
+		var lines []Line
+		links := linkLines(page, doc.Links)
+		for i, textLine := range page.Lines {
+			line := Line{
+				Text:     textLine.Text(),
+				FontSize: textLine.FontSize(),
+				Bold:     textLine.Bold(),
+				Italic:   textLine.Italic(),
+				Level:    textLine.Heading,
+				Column:   textLine.Column,
+				Links:    links[i],
+			}
+			// Heading styles from the source document mark headers directly
+			line.Header = textLine.Heading > 0 || isTypographicHeader(line, bodySize)
+			lines = append(lines, line)
+		}
+		layoutCues(page, lines)
+		pages = append(pages, lines)
+	}
+	return p.processPages(pages)
+}
+
+// processPages strips the furniture from the pages and repairs, joins and
+// cleans the lines that remain
+func (p *Preprocessor) processPages(pages [][]Line) (lines, furniture []Line) {
+	lines, furniture = stripPageFurniture(pages)
+	for i := range furniture {
+		furniture[i].Text = strings.TrimSpace(p.Normalization.text(furniture[i].Text))
+	}
+	return p.processLines(joinWrappedLines(p.Normalization.apply(lines))), furniture
+}
+
+// lineGapRatio is how much wider than the usual line spacing the space
+// above a line must be to set it apart
+const lineGapRatio = 1.25
+
+// layoutCues measures the lines of a page against its layout: each line's
+// indentation from the left margin of its column, and whether extra
+// vertical space separates it from the line above.
+func layoutCues(page models.Page, lines []Line) {
+	margins := make(map[int]float64)
+	for _, textLine := range page.Lines {
+		if strings.TrimSpace(textLine.Text()) == "" {
+			continue
+		}
+		if margin, ok := margins[textLine.Column]; !ok || textLine.X() < margin {
+			margins[textLine.Column] = textLine.X()
+		}
+	}
+
+	previous := make(map[int]models.TextLine)
+	spacing := make([]float64, len(page.Lines))
+	var spacings []float64
+	for i, textLine := range page.Lines {
+		lines[i].Indent = math.Max(textLine.X()-margins[textLine.Column], 0)
+		if strings.TrimSpace(textLine.Text()) == "" {
+			continue
+		}
+		if prev, ok := previous[textLine.Column]; ok {
+			if dy := textLine.Y() - prev.Y(); dy > 0 {
+				spacing[i] = dy
+				spacings = append(spacings, dy)
+			}
+		}
+		previous[textLine.Column] = textLine
+	}
+
+	// Extractors without geometry leave every line at the same position
+	if len(spacings) < 3 {
+		return
+	}
+	sort.Float64s(spacings)
+	usual := spacings[len(spacings)/2]
+	for i, dy := range spacing {
+		if dy > usual*lineGapRatio && dy > usual+2 {
+			lines[i].Gap = true
+		}
+	}
+}
+
+// furnitureLines is how many lines at the top and bottom of a page are
+// checked for running headers, footers and page numbers
+const furnitureLines = 3
+
+var (
+	pageNumberLine = regexp.MustCompile(`(?i)^(?:page|pg\.?|p\.|seite|página|pagina)?\s*\d{1,3}(?:\s*(?:of|/|von|de|sur)\s*\d{1,3})?$|^[-–—]\s*\d{1,3}\s*[-–—]$`)
+	pageOfPages    = regexp.MustCompile(`(?i)\bpage\s+\d{1,3}\s*(?:of|/)\s*\d{1,3}\b`)
+	furnitureDigit = regexp.MustCompile(`\d+`)
+)
+
+// stripPageFurniture removes running headers, footers and page numbers
+// from the top and bottom lines of each page, so they don't end up inside
+// the section that continues across the page break. A header is a line
+// repeated at the top of several pages; the first page keeps its copy,
+// which is usually the contact block. Footers repeat at the bottom. The
+// removed lines are returned as furniture, since a footer may be the only
+// place the resume gives an email address or phone number.
+func stripPageFurniture(pages [][]Line) (lines, furniture []Line) {
+	type edges struct{ top, bottom []int }
+	pageEdges := make([]edges, len(pages))
+	topCount := make(map[string]int)
+	bottomCount := make(map[string]int)
+	for i, page := range pages {
+		var nonEmpty []int
+		for j, line := range page {
+			if strings.TrimSpace(line.Text) != "" {
+				nonEmpty = append(nonEmpty, j)
+			}
+		}
+		top := nonEmpty[:min(len(nonEmpty), furnitureLines)]
+		bottom := nonEmpty[max(len(top), len(nonEmpty)-furnitureLines):]
+		pageEdges[i] = edges{top, bottom}
+
+		count := func(counts map[string]int, indexes []int) {
+			seen := make(map[string]bool)
+			for _, j := range indexes {
+				if key := furnitureKey(page[j].Text); !seen[key] {
+					seen[key] = true
+					counts[key]++
+				}
+			}
+		}
+		count(topCount, top)
+		count(bottomCount, bottom)
+	}
+
+	for i, page := range pages {
+		remove := make(map[int]bool)
+		for _, j := range pageEdges[i].top {
+			remove[j] = isPageNumber(page[j].Text) || i > 0 && topCount[furnitureKey(page[j].Text)] >= 2
+		}
+		for _, j := range pageEdges[i].bottom {
+			remove[j] = isPageNumber(page[j].Text) || bottomCount[furnitureKey(page[j].Text)] >= 2
+		}
+		for j, line := range page {
+			if remove[j] {
+				furniture = append(furniture, line)
+			} else {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines, furniture
+}
+
+// furnitureKey normalizes a line for comparison across pages, masking
+// numbers so "Page 2 of 3" matches "Page 3 of 3"
+func furnitureKey(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	return furnitureDigit.ReplaceAllString(text, "#")
+}
+
+// isPageNumber reports whether a line is a page number such as "2",
+// "- 2 -", "Page 2 of 3" or "Jane Doe – Page 2 of 3"
+func isPageNumber(text string) bool {
+	text = strings.TrimSpace(text)
+	return pageNumberLine.MatchString(text) || pageOfPages.MatchString(text)
+}
+
+func (p *Preprocessor) processLines(lines []Line) []Line {
+	var processed []Line
+	gap := false
 
 	// Process each line
 	for _, line := range lines {
-		// Clean the line
-		line = strings.TrimSpace(line)
-		if line == "" {
+		// Clean the line; a blank line is kept as a gap before the next one
+		line.Text = strings.TrimSpace(line.Text)
+		if line.Text == "" {
+			gap = len(processed) > 0
 			continue
 		}
+		// A rule underlines the header above it and separates what follows
+		if isRule(line.Text) {
+			if n := len(processed); n > 0 && !gap && !line.Gap && isUnderlinedHeader(processed[n-1]) {
+				processed[n-1].Header = true
+			}
+			gap = len(processed) > 0
+			continue
+		}
+		line.Gap = line.Gap || gap
+		gap = false
 
 		// Handle bullet points
-		if isBulletPoint(line) {
-			processed = append(processed, normalizeBulletPoint(line))
+		if isBulletPoint(line.Text) {
+			line.Text = normalizeBulletPoint(line.Text)
+			line.Header = false
+			processed = append(processed, line)
 			continue
 		}
 
-		// Handle section headers (all caps)
-		if isSectionHeader(line) {
-			if len(processed) > 0 && processed[len(processed)-1] != "" {
-				processed = append(processed, "")
+		// Handle section headers (all caps or typographically emphasized)
+		if line.Header || isSectionHeader(line.Text) {
+			line.Header = true
+			if len(processed) > 0 && processed[len(processed)-1].Text != "" {
+				processed = append(processed, Line{Column: line.Column})
 			}
 			processed = append(processed, line)
 			continue
 		}
 
 		// Handle dates and locations (often in parentheses or after commas)
-		if strings.Contains(line, ",") || strings.Contains(line, "(") {
+		if strings.Contains(line.Text, ",") || strings.Contains(line.Text, "(") {
 			processed = append(processed, line)
 			continue
 		}
 
 		// Handle contact information (emails, phones, links)
-		if strings.Contains(line, "@") || strings.Contains(line, "http") || containsPhoneNumber(line) {
+		if strings.Contains(line.Text, "@") || strings.Contains(line.Text, "http") || containsPhoneNumber(line.Text) {
 			processed = append(processed, line)
 			continue
 		}
@@ -60,6 +260,188 @@ func (p *Preprocessor) Process(text string) []string {
 	return processed
 }
 
+// minWrappedLength is the length a line must reach before the next one can
+// be its continuation; shorter lines ended on purpose
+const minWrappedLength = 30
+
+var (
+	wrapConnector = regexp.MustCompile(`(?i)(?:[,&/(+]|\b(?:and|or|of|to|for|with|in|on|at|by|the|a|an|using|including|via))$`)
+	wrapWebsite   = regexp.MustCompile(`(?i)\b[a-z0-9-]+\.(?:com|org|net|io|dev|me|edu)\b`)
+)
+
+// joinWrappedLines rejoins lines that continue the bullet or paragraph
+// above them after wrapping. A line continues the previous one in the
+// same column when that one is long enough to have wrapped and doesn't end
+// a sentence, and it starts in lower case, follows a connecting word or
+// comma, or sits at the hanging indent of a bullet. Blank lines, headers,
+// bullets, contact details and lines set apart by a gap are never joined.
+func joinWrappedLines(lines []Line) []Line {
+	var joined []Line
+	for _, line := range lines {
+		if n := len(joined); n > 0 && continuesLine(joined[n-1], line) {
+			prev := &joined[n-1]
+			prev.Text = strings.TrimRightFunc(prev.Text, unicode.IsSpace) + " " + strings.TrimSpace(line.Text)
+			prev.Links = append(prev.Links, line.Links...)
+			prev.Bold = prev.Bold && line.Bold
+			prev.Italic = prev.Italic && line.Italic
+			continue
+		}
+		joined = append(joined, line)
+	}
+	return joined
+}
+
+// continuesLine reports whether line is the wrapped continuation of prev
+func continuesLine(prev, line Line) bool {
+	prevText := strings.TrimSpace(prev.Text)
+	text := strings.TrimSpace(line.Text)
+	if prevText == "" || text == "" || line.Gap || prev.Column != line.Column || isRule(text) {
+		return false
+	}
+	if prev.Header || line.Header || isSectionHeader(prevText) || isSectionHeader(text) || isBulletPoint(text) {
+		return false
+	}
+	if prev.FontSize > 0 && line.FontSize > 0 && math.Abs(prev.FontSize-line.FontSize) > 0.5 {
+		return false
+	}
+	if isContactDetail(prevText) || isContactDetail(text) {
+		return false
+	}
+	if utf8.RuneCountInString(prevText) < minWrappedLength || strings.ContainsAny(prevText[len(prevText)-1:], ".!?:;") {
+		return false
+	}
+
+	first, _ := utf8.DecodeRuneInString(text)
+	if unicode.IsLower(first) || wrapConnector.MatchString(prevText) {
+		return true
+	}
+	// The text of a wrapped bullet lines up after the bullet glyph
+	return isBulletPoint(prevText) && line.Indent > prev.Indent+1
+}
+
+// isRule reports whether a line is a horizontal rule typed out in
+// characters, such as "-----", "=====", "_____" or "* * *"
+func isRule(text string) bool {
+	count := 0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("-=_*~#.·•—–━─═", r):
+			count++
+		case unicode.IsSpace(r):
+		default:
+			return false
+		}
+	}
+	return count >= 3
+}
+
+// isUnderlinedHeader reports whether a line followed by a rule is a header
+// underlined with it rather than the end of a paragraph
+func isUnderlinedHeader(line Line) bool {
+	return !isBulletPoint(line.Text) && len(strings.Fields(line.Text)) <= 6
+}
+
+// isContactDetail reports whether text holds an email address, phone
+// number or web address
+func isContactDetail(text string) bool {
+	return strings.Contains(text, "@") || strings.Contains(text, "http") || strings.Contains(text, "www.") ||
+		wrapWebsite.MatchString(text) || containsPhoneNumber(text)
+}
+
+// linkLines maps the page's links to the index of the line each one is
+// anchored on. Links with a position go to the line they overlap most;
+// others are found by their anchor text, searching in reading order.
+func linkLines(page models.Page, links []models.Link) map[int][]string {
+	result := make(map[int][]string)
+	cursor := 0
+	for _, link := range links {
+		if link.Page != page.Number {
+			continue
+		}
+
+		index := -1
+		if link.Width > 0 && link.Height > 0 {
+			var best float64
+			for i, line := range page.Lines {
+				for _, run := range line.Runs {
+					w := math.Min(link.X+link.Width, run.X+run.Width) - math.Max(link.X, run.X)
+					h := math.Min(link.Y+link.Height, run.Y+run.Height) - math.Max(link.Y, run.Y)
+					if w > 0 && h > 0 && w*h > best {
+						best = w * h
+						index = i
+					}
+				}
+			}
+		} else if anchor := strings.ToLower(strings.Join(strings.Fields(link.Text), " ")); anchor != "" {
+			find := func(from int) int {
+				for i := from; i < len(page.Lines); i++ {
+					if strings.Contains(strings.ToLower(page.Lines[i].Text()), anchor) {
+						return i
+					}
+				}
+				return -1
+			}
+			if index = find(cursor); index < 0 {
+				index = find(0)
+			}
+			if index >= 0 {
+				cursor = index
+			}
+		}
+
+		if index >= 0 {
+			result[index] = append(result[index], link.URI)
+		}
+	}
+	return result
+}
+
+// bodyFontSize returns the font size used for most of the document's text
+func bodyFontSize(doc *models.Document) float64 {
+	counts := make(map[float64]int)
+	for _, page := range doc.Pages {
+		for _, line := range page.Lines {
+			for _, run := range line.Runs {
+				if run.FontSize > 0 {
+					counts[math.Round(run.FontSize*2)/2] += len(run.Text)
+				}
+			}
+		}
+	}
+
+	sizes := make([]float64, 0, len(counts))
+	for size := range counts {
+		sizes = append(sizes, size)
+	}
+	sort.Float64s(sizes)
+
+	var body float64
+	for _, size := range sizes {
+		if body == 0 || counts[size] > counts[body] {
+			body = size
+		}
+	}
+	return body
+}
+
+// isTypographicHeader reports whether a short line stands out from body
+// text by being larger or entirely bold.
+func isTypographicHeader(line Line, bodySize float64) bool {
+	text := strings.TrimSpace(line.Text)
+	if bodySize == 0 || text == "" || len(text) > 50 || len(strings.Fields(text)) > 5 {
+		return false
+	}
+	if isBulletPoint(text) || strings.Contains(text, "@") || strings.Contains(text, "http") || containsPhoneNumber(text) {
+		return false
+	}
+	if line.FontSize >= bodySize*1.15 {
+		return true
+	}
+	// Bold body text is only a header when it doesn't read like an entry
+	// title with a location or dates
+	return line.Bold && line.FontSize >= bodySize && !strings.ContainsAny(text, ",|0123456789")
+}
+
 func isSectionHeader(line string) bool {
 	// Must be relatively short
 	if len(line) > 50 {
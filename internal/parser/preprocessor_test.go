@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"reflect"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+// textPages splits text into pages of lines at form feeds
+func textPages(text string) [][]Line {
+	var pages [][]Line
+	for _, page := range strings.Split(text, "\f") {
+		var lines []Line
+		for _, line := range strings.Split(page, "\n") {
+			lines = append(lines, Line{Text: line})
+		}
+		pages = append(pages, lines)
+	}
+	return pages
+}
+
+func TestStripPageFurniture(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		want      []string
+		furniture []string
+	}{
+		{
+			name: "single page",
+			text: "Jane Doe\nEXPERIENCE\nAcme Corp\nBuilt things",
+			want: []string{"Jane Doe", "EXPERIENCE", "Acme Corp", "Built things"},
+		},
+		{
+			name:      "page numbers",
+			text:      "Jane Doe\nEXPERIENCE\nAcme Corp\n- 1 -\fBuilt things\nMore things\nPage 2 of 2",
+			want:      []string{"Jane Doe", "EXPERIENCE", "Acme Corp", "Built things", "More things"},
+			furniture: []string{"- 1 -", "Page 2 of 2"},
+		},
+		{
+			name:      "running header",
+			text:      "Jane Doe\nEXPERIENCE\nAcme Corp\fJane Doe\nBuilt things\nMore things",
+			want:      []string{"Jane Doe", "EXPERIENCE", "Acme Corp", "Built things", "More things"},
+			furniture: []string{"Jane Doe"},
+		},
+		{
+			name:      "footer",
+			text:      "Jane Doe\nEXPERIENCE\nAcme Corp\njane@example.com\fBuilt things\nMore things\nEDUCATION\nTU Berlin\njane@example.com",
+			want:      []string{"Jane Doe", "EXPERIENCE", "Acme Corp", "Built things", "More things", "EDUCATION", "TU Berlin"},
+			furniture: []string{"jane@example.com", "jane@example.com"},
+		},
+		{
+			name: "blank lines",
+			text: "Jane Doe\n\nEXPERIENCE\n\fBuilt things\n\n",
+			want: []string{"Jane Doe", "", "EXPERIENCE", "", "Built things", "", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, furniture := stripPageFurniture(textPages(tt.text))
+			if got := lineTexts(lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lines = %q, want %q", got, tt.want)
+			}
+			if got := lineTexts(furniture); len(got) > 0 || len(tt.furniture) > 0 {
+				if !reflect.DeepEqual(got, tt.furniture) {
+					t.Errorf("furniture = %q, want %q", got, tt.furniture)
+				}
+			}
+		})
+	}
+}
+
+func TestContinuesLine(t *testing.T) {
+	long := "Built the billing service that handles invoices"
+	tests := []struct {
+		name       string
+		prev, line Line
+		want       bool
+	}{
+		{name: "lower case", prev: Line{Text: long}, line: Line{Text: "for every customer"}, want: true},
+		{name: "connector", prev: Line{Text: long + " and"}, line: Line{Text: "Payments"}, want: true},
+		{name: "hanging indent", prev: Line{Text: "• " + long}, line: Line{Text: "Payments", Indent: 12}, want: true},
+		{name: "capitalized", prev: Line{Text: long}, line: Line{Text: "Payments"}},
+		{name: "short line", prev: Line{Text: "Built billing"}, line: Line{Text: "for every customer"}},
+		{name: "sentence end", prev: Line{Text: long + "."}, line: Line{Text: "for every customer"}},
+		{name: "gap", prev: Line{Text: long}, line: Line{Text: "for every customer", Gap: true}},
+		{name: "other column", prev: Line{Text: long}, line: Line{Text: "for every customer", Column: 1}},
+		{name: "bullet", prev: Line{Text: long}, line: Line{Text: "• for every customer"}},
+		{name: "header", prev: Line{Text: long}, line: Line{Text: "EDUCATION"}},
+		{name: "font size", prev: Line{Text: long, FontSize: 10}, line: Line{Text: "for every customer", FontSize: 14}},
+		{name: "contact detail", prev: Line{Text: long}, line: Line{Text: "jane@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := continuesLine(tt.prev, tt.line); got != tt.want {
+				t.Errorf("continuesLine(%q, %q) = %v, want %v", tt.prev.Text, tt.line.Text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinWrappedLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []Line
+		want  []Line
+	}{
+		{
+			name: "wrapped bullet",
+			lines: []Line{
+				{Text: "• Built the billing service that handles invoices", Bold: true, Links: []string{"a"}},
+				{Text: "for every customer", Links: []string{"b"}},
+				{Text: "• Ran the team"},
+			},
+			want: []Line{
+				{Text: "• Built the billing service that handles invoices for every customer", Links: []string{"a", "b"}},
+				{Text: "• Ran the team"},
+			},
+		},
+		{
+			name:  "separate lines",
+			lines: []Line{{Text: "Acme Corp"}, {Text: "Senior Engineer"}},
+			want:  []Line{{Text: "Acme Corp"}, {Text: "Senior Engineer"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinWrappedLines(tt.lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("joinWrappedLines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutCues(t *testing.T) {
+	type pos struct {
+		x, y   float64
+		column int
+	}
+	tests := []struct {
+		name   string
+		lines  []pos
+		indent []float64
+		gap    []bool
+	}{
+		{
+			name:   "paragraph gap",
+			lines:  []pos{{50, 100, 0}, {50, 112, 0}, {60, 124, 0}, {50, 150, 0}, {50, 162, 0}},
+			indent: []float64{0, 0, 10, 0, 0},
+			gap:    []bool{false, false, false, true, false},
+		},
+		{
+			name:   "columns",
+			lines:  []pos{{50, 100, 1}, {300, 100, 2}, {50, 112, 1}, {310, 112, 2}, {50, 124, 1}, {300, 140, 2}},
+			indent: []float64{0, 0, 0, 10, 0, 0},
+			gap:    []bool{false, false, false, false, false, true},
+		},
+		{
+			name:   "no geometry",
+			lines:  []pos{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}},
+			indent: []float64{0, 0, 0},
+			gap:    []bool{false, false, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var page models.Page
+			lines := make([]Line, len(tt.lines))
+			for _, p := range tt.lines {
+				page.Lines = append(page.Lines, models.TextLine{
+					Runs:   []models.TextRun{{Text: "text", X: p.x, Y: p.y, FontSize: 10}},
+					Column: p.column,
+				})
+			}
+			layoutCues(page, lines)
+			for i, l := range lines {
+				if l.Indent != tt.indent[i] || l.Gap != tt.gap[i] {
+					t.Errorf("line %d: indent = %v, gap = %v, want %v, %v", i, l.Indent, l.Gap, tt.indent[i], tt.gap[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIsTypographicHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		line Line
+		want bool
+	}{
+		{name: "larger", line: Line{Text: "Experience", FontSize: 14}, want: true},
+		{name: "bold", line: Line{Text: "Experience", FontSize: 10, Bold: true}, want: true},
+		{name: "body", line: Line{Text: "Experience", FontSize: 10}},
+		{name: "bold entry title", line: Line{Text: "Acme Corp, Berlin", FontSize: 10, Bold: true}},
+		{name: "bold dates", line: Line{Text: "Engineer 2020", FontSize: 10, Bold: true}},
+		{name: "long", line: Line{Text: "Led a team of six engineers building payments", FontSize: 14}},
+		{name: "email", line: Line{Text: "jane@example.com", FontSize: 14}},
+		{name: "bullet", line: Line{Text: "• Go", FontSize: 14}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTypographicHeader(tt.line, 10); got != tt.want {
+				t.Errorf("isTypographicHeader(%+v) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessUnderlinedHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		header bool
+	}{
+		{name: "underlined", text: "Projects\n--------\nParser", header: true},
+		{name: "double rule", text: "Projects\n========\nParser", header: true},
+		{name: "after paragraph", text: "Built a parser for resumes in several formats and languages\n-----\nParser"},
+		{name: "bullet", text: "• Parser\n-----\nMore"},
+		{name: "rule after gap", text: "Projects\n\n--------\nParser"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, _ := NewPreprocessor().Process(tt.text)
+			if len(lines) == 0 || lines[0].Header != tt.header {
+				t.Errorf("lines = %+v, want header %v first", lines, tt.header)
+			}
+			if last := lines[len(lines)-1]; !last.Gap {
+				t.Errorf("line after rule %q has no gap", last.Text)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"resumeparser/internal/models"
+	"strings"
+)
+
+//go:embed assets/sections.json
+var defaultSections []byte
+
+// contactSection is the name of the section the header block is read as
+const contactSection = "contact"
+
+// referencesSection lists referees, whose contact details aren't the
+// candidate's
+const referencesSection = "references"
+
+// SectionDefinition describes a section the parser recognizes: the name it
+// is stored under, the headings that introduce it and its type, which
+// selects the parser for its lines
+type SectionDefinition struct {
+	Name    string             `json:"name"`
+	Type    models.SectionType `json:"type"` // contact, timeline, list or freeform
+	Aliases []string           `json:"aliases"`
+}
+
+// SectionConfig is the set of sections the parser recognizes
+type SectionConfig struct {
+	Sections []SectionDefinition `json:"sections"`
+}
+
+// DefaultSectionConfig returns the built-in sections: education,
+// experience, skills, projects, achievements, summary, contact and
+// references
+func DefaultSectionConfig() *SectionConfig {
+	config, err := ParseSectionConfig(defaultSections)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in section config: %v", err))
+	}
+	return config
+}
+
+// LoadSectionConfig reads a section config from a JSON file
+func LoadSectionConfig(path string) (*SectionConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read section config: %w", err)
+	}
+	config, err := ParseSectionConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseSectionConfig reads a section config from JSON. Names and aliases
+// are matched in lower case; a section without aliases is introduced by
+// its name.
+func ParseSectionConfig(data []byte) (*SectionConfig, error) {
+	var config SectionConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid section config: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range config.Sections {
+		s := &config.Sections[i]
+		s.Name = strings.ToLower(strings.TrimSpace(s.Name))
+		if s.Name == "" {
+			return nil, fmt.Errorf("section %d has no name", i+1)
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("section %q is defined twice", s.Name)
+		}
+		seen[s.Name] = true
+
+		switch s.Type {
+		case models.ContactSection, models.TimelineSection, models.ListSection, models.FreeformSection:
+		case "":
+			s.Type = models.FreeformSection
+		default:
+			return nil, fmt.Errorf("section %q has unknown type %q", s.Name, s.Type)
+		}
+		if s.Name == contactSection && s.Type != models.ContactSection {
+			return nil, fmt.Errorf("section %q must have type %q", s.Name, models.ContactSection)
+		}
+
+		aliases := s.Aliases[:0]
+		for _, alias := range s.Aliases {
+			if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+				aliases = append(aliases, alias)
+			}
+		}
+		if len(aliases) == 0 {
+			aliases = append(aliases, s.Name)
+		}
+		s.Aliases = aliases
+	}
+	return &config, nil
+}
+
+// Merge returns the sections of c with those of other added, replacing
+// the sections of c that other defines again
+func (c *SectionConfig) Merge(other *SectionConfig) *SectionConfig {
+	merged := &SectionConfig{}
+	index := make(map[string]int)
+	for _, s := range c.Sections {
+		index[s.Name] = len(merged.Sections)
+		merged.Sections = append(merged.Sections, s)
+	}
+	for _, s := range other.Sections {
+		if i, ok := index[s.Name]; ok {
+			merged.Sections[i] = s
+			continue
+		}
+		index[s.Name] = len(merged.Sections)
+		merged.Sections = append(merged.Sections, s)
+	}
+	return merged
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"reflect"
+	"resumeparser/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestParseSectionConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []SectionDefinition
+		wantErr string
+	}{
+		{
+			name: "normalized",
+			data: `{"sections": [{"name": " Publications ", "type": "list", "aliases": ["Papers", " ", "PUBLICATIONS"]}]}`,
+			want: []SectionDefinition{{Name: "publications", Type: models.ListSection, Aliases: []string{"papers", "publications"}}},
+		},
+		{
+			name: "defaults",
+			data: `{"sections": [{"name": "Interests"}]}`,
+			want: []SectionDefinition{{Name: "interests", Type: models.FreeformSection, Aliases: []string{"interests"}}},
+		},
+		{name: "invalid json", data: `{"sections": [`, wantErr: "invalid section config"},
+		{name: "no name", data: `{"sections": [{"type": "list"}]}`, wantErr: "has no name"},
+		{name: "duplicate", data: `{"sections": [{"name": "skills"}, {"name": "Skills"}]}`, wantErr: "defined twice"},
+		{name: "unknown type", data: `{"sections": [{"name": "skills", "type": "table"}]}`, wantErr: "unknown type"},
+		{name: "contact type", data: `{"sections": [{"name": "contact", "type": "list"}]}`, wantErr: "must have type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseSectionConfig([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseSectionConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSectionConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(config.Sections, tt.want) {
+				t.Errorf("sections = %+v, want %+v", config.Sections, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionConfigMerge(t *testing.T) {
+	base := &SectionConfig{Sections: []SectionDefinition{
+		{Name: "skills", Type: models.ListSection, Aliases: []string{"skills"}},
+		{Name: "contact", Type: models.ContactSection, Aliases: []string{"contact"}},
+	}}
+	other := &SectionConfig{Sections: []SectionDefinition{
+		{Name: "publications", Type: models.ListSection, Aliases: []string{"papers"}},
+		{Name: "skills", Type: models.ListSection, Aliases: []string{"tools"}},
+	}}
+
+	merged := base.Merge(other)
+	var got []string
+	for _, s := range merged.Sections {
+		got = append(got, s.Name+":"+strings.Join(s.Aliases, "/"))
+	}
+	want := []string{"skills:tools", "contact:contact", "publications:papers"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+	if base.Sections[0].Aliases[0] != "skills" {
+		t.Errorf("Merge() modified the receiver")
+	}
+}
+
+func TestDefaultSectionConfig(t *testing.T) {
+	p := NewParser()
+	for name, typ := range map[string]models.SectionType{
+		"education": models.TimelineSection,
+		"skills":    models.ListSection,
+		"contact":   models.ContactSection,
+		"summary":   models.FreeformSection,
+		"unknown":   models.FreeformSection,
+	} {
+		if got := p.getSectionType(name); got != typ {
+			t.Errorf("getSectionType(%q) = %q, want %q", name, got, typ)
+		}
+	}
+}
+
+func TestWithSectionsWithoutContact(t *testing.T) {
+	config, err := ParseSectionConfig([]byte(`{"sections": [{"name": "experience", "type": "timeline"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewParser(WithSections(config))
+	resume, err := p.Parse("Jane Doe\njane@example.com\n\nEXPERIENCE\nAcme Corp\nJan 2020 - Present\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	section, ok := resume.Section("contact")
+	if !ok {
+		t.Fatal("no contact section")
+	}
+	contact, ok := section.Content.(*models.ContactContent)
+	if !ok || contact.Name != "Jane Doe" || len(contact.Email) != 1 {
+		t.Errorf("contact = %+v", section.Content)
+	}
+	if p.getSectionType("skills") != models.FreeformSection {
+		t.Errorf("built-in skills section kept, want replaced config")
+	}
+}
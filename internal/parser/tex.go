@@ -0,0 +1,333 @@
+package parser
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type texKind int
+
+const (
+	texText texKind = iota
+	texCommand
+	texGroup
+)
+
+// texToken is a piece of LaTeX source: running text, a control sequence or
+// a brace group with its contents
+type texToken struct {
+	kind  texKind
+	text  string // text, or the command name without the backslash
+	group []texToken
+}
+
+// tokenizeTeX splits LaTeX source into tokens. Comments and math shifts
+// are dropped, escaped characters and symbols become text, accents are
+// composed with their letter and & becomes a tab between table cells.
+// Brackets are separate tokens so optional arguments can be found.
+func tokenizeTeX(src string) []texToken {
+	t := &texTokenizer{src: src}
+	return t.tokens(false)
+}
+
+type texTokenizer struct {
+	src string
+	pos int
+}
+
+func (t *texTokenizer) tokens(inGroup bool) []texToken {
+	var toks []texToken
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			toks = append(toks, texToken{kind: texText, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		t.pos++
+		switch c {
+		case '%':
+			// A comment hides the line break and the next line's indentation
+			end := strings.IndexByte(t.src[t.pos:], '\n')
+			if end < 0 {
+				t.pos = len(t.src)
+				continue
+			}
+			t.pos += end + 1
+			for t.pos < len(t.src) && (t.src[t.pos] == ' ' || t.src[t.pos] == '\t') {
+				t.pos++
+			}
+		case '{':
+			flush()
+			toks = append(toks, texToken{kind: texGroup, group: t.tokens(true)})
+		case '}':
+			if inGroup {
+				flush()
+				return toks
+			}
+		case '[', ']':
+			flush()
+			toks = append(toks, texToken{kind: texText, text: string(c)})
+		case '$':
+		case '~':
+			text.WriteByte(' ')
+		case '&':
+			text.WriteByte('\t')
+		case '\\':
+			if t.pos >= len(t.src) {
+				continue
+			}
+			c = t.src[t.pos]
+			switch {
+			case isLetter(c):
+				start := t.pos
+				for t.pos < len(t.src) && isLetter(t.src[t.pos]) {
+					t.pos++
+				}
+				name := t.src[start:t.pos]
+				if t.pos < len(t.src) && t.src[t.pos] == '*' {
+					name += "*"
+					t.pos++
+				}
+				t.skipSpace()
+				if symbol, ok := texSymbols[name]; ok {
+					text.WriteString(symbol)
+				} else if _, ok := texAccents[name]; ok {
+					text.WriteString(composeAccent(name, t.accentArg()))
+				} else {
+					flush()
+					toks = append(toks, texToken{kind: texCommand, text: name})
+				}
+			case c == '\\':
+				t.pos++
+				if t.pos < len(t.src) && t.src[t.pos] == '*' {
+					t.pos++
+				}
+				flush()
+				toks = append(toks, texToken{kind: texCommand, text: `\`})
+			case strings.IndexByte("'`^\"~=.", c) >= 0:
+				t.pos++
+				text.WriteString(composeAccent(string(c), t.accentArg()))
+			case c == ' ' || c == ';' || c == ':' || c == '\n':
+				t.pos++
+				text.WriteByte(' ')
+			case c == ',' || c == '!' || c == '/' || c == '@' || c == '-':
+				t.pos++
+			default:
+				// \& \% \$ \# \_ \{ \} and the like
+				t.pos++
+				text.WriteByte(c)
+			}
+		default:
+			text.WriteByte(c)
+		}
+	}
+	flush()
+	return toks
+}
+
+// skipSpace skips the spaces and single line break after a control word
+func (t *texTokenizer) skipSpace() {
+	for t.pos < len(t.src) && (t.src[t.pos] == ' ' || t.src[t.pos] == '\t') {
+		t.pos++
+	}
+	if t.pos < len(t.src) && t.src[t.pos] == '\n' && !strings.HasPrefix(strings.TrimLeft(t.src[t.pos+1:], " \t"), "\n") {
+		t.pos++
+		for t.pos < len(t.src) && (t.src[t.pos] == ' ' || t.src[t.pos] == '\t') {
+			t.pos++
+		}
+	}
+}
+
+// accentArg reads the letter an accent applies to, e.g. e, {e} or {\i}
+func (t *texTokenizer) accentArg() string {
+	for t.pos < len(t.src) && t.src[t.pos] == ' ' {
+		t.pos++
+	}
+	if t.pos >= len(t.src) {
+		return ""
+	}
+	if t.src[t.pos] == '{' {
+		end := strings.IndexByte(t.src[t.pos:], '}')
+		if end < 0 {
+			return ""
+		}
+		arg := strings.TrimSpace(t.src[t.pos+1 : t.pos+end])
+		t.pos += end + 1
+		return strings.TrimPrefix(arg, `\`)
+	}
+	if t.src[t.pos] == '\\' && t.pos+1 < len(t.src) && (t.src[t.pos+1] == 'i' || t.src[t.pos+1] == 'j') {
+		t.pos += 2
+		return t.src[t.pos-1 : t.pos]
+	}
+	_, size := utf8.DecodeRuneInString(t.src[t.pos:])
+	t.pos += size
+	return t.src[t.pos-size : t.pos]
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// texSymbols maps control words to the text they print
+var texSymbols = map[string]string{
+	"LaTeX": "LaTeX", "TeX": "TeX", "ldots": "…", "dots": "…", "textellipsis": "…",
+	"textbullet": "•", "bullet": "•", "cdot": "·", "cdotp": "·", "textperiodcentered": "·",
+	"diamond": "⋄", "textbar": "|", "vert": "|", "mid": "|", "textbackslash": `\`,
+	"textasciitilde": "~", "sim": "~", "textasciicircum": "^", "textunderscore": "_",
+	"textendash": "–", "textemdash": "—", "textless": "<", "textgreater": ">",
+	"rightarrow": "→", "to": "→", "textrightarrow": "→", "times": "×", "pm": "±",
+	"copyright": "©", "textregistered": "®", "texttrademark": "™", "pounds": "£", "euro": "€",
+	"quad": " ", "qquad": " ", "enspace": " ", "thinspace": " ", "space": " ", "hfill": " ",
+	"slash": "/", "i": "ı", "j": "ȷ", "o": "ø", "O": "Ø", "ss": "ß", "ae": "æ", "AE": "Æ",
+	"oe": "œ", "OE": "Œ", "aa": "å", "AA": "Å", "l": "ł", "L": "Ł",
+}
+
+// texAccents lists the letters each accent composes with, in pairs of the
+// base letter and the accented one
+var texAccents = map[string]string{
+	"'":  "aáeéiíıíoóuúyýAÁEÉIÍOÓUÚYÝcćCĆnńNŃsśSŚzźZŹ",
+	"`":  "aàeèiìıìoòuùAÀEÈIÌOÒUÙ",
+	"^":  "aâeêiîıîoôuûAÂEÊIÎOÔUÛ",
+	"\"": "aäeëiïıïoöuüyÿAÄEËIÏOÖUÜ",
+	"~":  "aãnñoõAÃNÑOÕ",
+	"=":  "aāeēiīoōuūAĀEĒIĪOŌUŪ",
+	".":  "zżZŻeėEĖ",
+	"c":  "cçCÇsşSŞ",
+	"v":  "cčCČsšSŠzžZŽrřRŘeěEĚnňNŇ",
+	"H":  "oőOŐuűUŰ",
+	"r":  "aåAÅuůUŮ",
+	"u":  "aăAĂgğGĞ",
+	"k":  "aąAĄeęEĘ",
+}
+
+// composeAccent returns letter with the accent applied, or the letter
+// unchanged when the combination is unknown
+func composeAccent(accent, letter string) string {
+	pairs := []rune(texAccents[accent])
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if string(pairs[i]) == letter {
+			return string(pairs[i+1])
+		}
+	}
+	return letter
+}
+
+// texCursor reads tokens in order, along with the arguments of commands
+type texCursor struct {
+	toks []texToken
+	i    int
+}
+
+func (c *texCursor) next() (texToken, bool) {
+	if c.i >= len(c.toks) {
+		return texToken{}, false
+	}
+	c.i++
+	return c.toks[c.i-1], true
+}
+
+// skipSpace skips white space between a command and its arguments
+func (c *texCursor) skipSpace() {
+	for c.i < len(c.toks) && c.toks[c.i].kind == texText && strings.TrimSpace(c.toks[c.i].text) == "" {
+		c.i++
+	}
+}
+
+// optional returns an optional [argument] if one follows
+func (c *texCursor) optional() ([]texToken, bool) {
+	start := c.i
+	c.skipSpace()
+	if c.i >= len(c.toks) || c.toks[c.i].kind != texText || c.toks[c.i].text != "[" {
+		c.i = start
+		return nil, false
+	}
+	depth := 0
+	for j := c.i; j < len(c.toks); j++ {
+		if c.toks[j].kind != texText {
+			continue
+		}
+		switch c.toks[j].text {
+		case "[":
+			depth++
+		case "]":
+			depth--
+			if depth == 0 {
+				arg := c.toks[c.i+1 : j]
+				c.i = j + 1
+				return arg, true
+			}
+		}
+	}
+	c.i = start
+	return nil, false
+}
+
+// arg returns the next mandatory argument: a group's contents, a command
+// or a single character
+func (c *texCursor) arg() []texToken {
+	c.skipSpace()
+	if c.i >= len(c.toks) {
+		return nil
+	}
+	tok := c.toks[c.i]
+	switch tok.kind {
+	case texGroup:
+		c.i++
+		return tok.group
+	case texCommand:
+		c.i++
+		return []texToken{tok}
+	}
+	text := strings.TrimLeft(tok.text, " \t\n")
+	_, size := utf8.DecodeRuneInString(text)
+	if rest := text[size:]; rest != "" {
+		c.toks[c.i].text = rest
+	} else {
+		c.i++
+	}
+	return []texToken{{kind: texText, text: text[:size]}}
+}
+
+// group returns the next argument only if it is a brace group, for
+// commands whose trailing arguments are optional in some templates
+func (c *texCursor) group() ([]texToken, bool) {
+	start := c.i
+	c.skipSpace()
+	if c.i < len(c.toks) && c.toks[c.i].kind == texGroup {
+		c.i++
+		return c.toks[c.i-1].group, true
+	}
+	c.i = start
+	return nil, false
+}
+
+// skipArgs drops n arguments of a command, along with any optional ones
+// before or after them
+func (c *texCursor) skipArgs(n int) {
+	for i := 0; i < n; i++ {
+		for _, ok := c.optional(); ok; _, ok = c.optional() {
+		}
+		c.arg()
+	}
+	for _, ok := c.optional(); ok; _, ok = c.optional() {
+	}
+}
+
+// texRaw joins the text of tokens without interpreting commands, for
+// arguments such as URLs and environment names
+func texRaw(toks []texToken) string {
+	var b strings.Builder
+	for _, tok := range toks {
+		switch tok.kind {
+		case texText:
+			b.WriteString(tok.text)
+		case texGroup:
+			b.WriteString(texRaw(tok.group))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
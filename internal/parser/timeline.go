@@ -1,9 +1,10 @@
 package parser
 
 import (
-	"regexp"
 	"resumeparser/internal/models"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type dateInfo struct {
@@ -11,92 +12,56 @@ type dateInfo struct {
 	end   string
 }
 
-// extractDates attempts to extract start and end dates from a line of text
-func extractDates(line string) (dateInfo, bool) {
-	// Common date formats
-	datePatterns := []string{
-		`(January|February|March|April|May|June|July|August|September|October|November|December)\s+\d{4}`,
-		`(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)\.?\s+\d{4}`,
-		`\d{2}/\d{4}`,
-		`\d{2}-\d{4}`,
-	}
-
-	var dates []string
-	for _, pattern := range datePatterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindAllString(line, -1)
-		dates = append(dates, matches...)
-	}
-
-	// Check for "Present" or "Current"
-	if strings.Contains(line, "Present") || strings.Contains(line, "Current") {
-		if len(dates) > 0 {
-			return dateInfo{
-				start: dates[0],
-				end:   "Present",
-			}, true
-		}
-	}
-
-	if len(dates) >= 2 {
-		return dateInfo{
-			start: dates[0],
-			end:   dates[1],
-		}, true
-	} else if len(dates) == 1 {
-		return dateInfo{
-			start: dates[0],
-			end:   dates[0],
-		}, true
-	}
-
-	return dateInfo{}, false
-}
-
-func (p *Parser) parseTimeline(lines []string) (*models.TimelineContent, error) {
+// parseTimeline reads entries from the layout of their lines. An entry
+// starts with a heading line, usually the organization, followed by lines
+// for the title, location and dates, then its bullets. A line that isn't
+// indented under the heading starts the next entry once the current one has
+// bullets, after a gap, when it repeats the heading's bold type, or when
+// the entry already has its title and dates.
+func (p *Parser) parseTimeline(lines []Line, lang *languagePack) (*models.TimelineContent, error) {
 	content := &models.TimelineContent{
 		Entries: make([]models.TimelineEntry, 0),
 	}
 	var currentEntry *models.TimelineEntry
+	var heading Line
+	inDetails := false
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	for _, l := range lines {
+		line := strings.TrimSpace(l.Text)
 		if line == "" {
 			continue
 		}
 
-		indentation := countIndentation(line)
-		if indentation == 0 && !isBulletPoint(line) {
+		bullet := isBulletPoint(line)
+		if currentEntry == nil || !bullet && startsEntry(heading, l, currentEntry, inDetails) {
 			// New entry
 			if currentEntry != nil {
 				content.Entries = append(content.Entries, *currentEntry)
 			}
 			currentEntry = &models.TimelineEntry{
-				Organization: line,
-				Details:      make([]string, 0),
-				Metadata:     make(map[string]string),
+				Details:  make([]string, 0),
+				Metadata: make(map[string]string),
 			}
-		} else if currentEntry != nil {
-			// Process entry details
-			if isBulletPoint(line) {
-				detail := removeBulletPoint(line)
-				currentEntry.Details = append(currentEntry.Details, detail)
-			} else if dates, ok := extractDates(line); ok {
-				currentEntry.StartDate = dates.start
-				currentEntry.EndDate = dates.end
-			} else if indentation == 1 {
-				// This could be title or location
-				if strings.Contains(line, ",") {
-					parts := strings.Split(line, ",")
-					if len(parts) >= 2 {
-						currentEntry.Title = strings.TrimSpace(parts[0])
-						currentEntry.Location = strings.TrimSpace(parts[1])
-					} else {
-						currentEntry.Location = line
-					}
-				} else {
-					currentEntry.Title = line
-				}
+			heading = l
+			inDetails = bullet
+			if bullet {
+				currentEntry.Details = append(currentEntry.Details, removeBulletPoint(line))
+			} else {
+				setEntryHeading(currentEntry, line, lang)
+			}
+		} else if bullet {
+			currentEntry.Details = append(currentEntry.Details, removeBulletPoint(line))
+			inDetails = true
+		} else if inDetails {
+			// Text indented under the bullets
+			currentEntry.Details = append(currentEntry.Details, line)
+		} else {
+			addEntryLine(currentEntry, line, lang)
+		}
+
+		for _, uri := range l.Links {
+			if !contains(currentEntry.Links, uri) {
+				currentEntry.Links = append(currentEntry.Links, uri)
 			}
 		}
 	}
@@ -108,3 +73,105 @@ func (p *Parser) parseTimeline(lines []string) (*models.TimelineContent, error)
 
 	return content, nil
 }
+
+// startsEntry reports whether a line that isn't a bullet begins a new entry
+// rather than continuing the one started by heading
+func startsEntry(heading, l Line, entry *models.TimelineEntry, inDetails bool) bool {
+	if l.Indent > heading.Indent+1 {
+		return false
+	}
+	if inDetails || l.Gap {
+		return true
+	}
+	// Titles are usually set apart from the heading's type, so a line in
+	// the same bold type is the next heading
+	if heading.Bold && l.Bold && !l.Italic && !heading.Italic {
+		return true
+	}
+	return entry.Title != "" && entry.Organization != "" && entry.StartDate != ""
+}
+
+// setEntryHeading reads the organization from an entry's first line, along
+// with any dates and location on the same line. Education entries often
+// lead with the degree instead, which becomes the title.
+func setEntryHeading(entry *models.TimelineEntry, line string, lang *languagePack) {
+	if dates, ok := lang.extractDates(line); ok {
+		entry.StartDate = dates.start
+		entry.EndDate = dates.end
+		line = lang.stripDates(line)
+	}
+	if lang.isDegree(line) {
+		// What follows the degree is usually the school, as in "B.S.
+		// Computer Science, State University"
+		entry.Title, entry.Organization = splitPlace(line)
+		return
+	}
+	entry.Organization, entry.Location = splitPlace(line)
+}
+
+// addEntryLine fills in the title, organization, dates or location of an
+// entry from one of the lines between its heading and its bullets
+func addEntryLine(entry *models.TimelineEntry, line string, lang *languagePack) {
+	if dates, ok := lang.extractDates(line); ok && entry.StartDate == "" {
+		entry.StartDate = dates.start
+		entry.EndDate = dates.end
+		if line = lang.stripDates(line); line == "" {
+			return
+		}
+	}
+
+	switch {
+	case entry.Organization == "" && entry.Title != "":
+		// The heading was the degree
+		organization, location := splitPlace(line)
+		entry.Organization = organization
+		if entry.Location == "" {
+			entry.Location = location
+		}
+	case entry.Title == "":
+		title, location := splitPlace(line)
+		entry.Title = title
+		if entry.Location == "" {
+			entry.Location = location
+		}
+	case entry.Location == "":
+		entry.Location = line
+	default:
+		entry.Details = append(entry.Details, line)
+	}
+}
+
+var organizationSuffixes = []string{"inc", "inc.", "llc", "ltd", "ltd.", "corp", "corp.", "co.", "llp", "plc", "gmbh", "ag", "s.a.", "b.v."}
+
+// splitPlace splits a trailing location such as "San Francisco, CA" from
+// "Acme Corp, San Francisco, CA". After a comma only
+// capitalized words count as a place, so "Acme, Inc." and "B.S., minor in
+// Math" stay whole.
+func splitPlace(line string) (string, string) {
+	parts := strings.Split(line, ",")
+	for i := 1; i < len(parts); i++ {
+		if isPlace(parts[i:]) {
+			return strings.TrimSpace(strings.Join(parts[:i], ",")), strings.TrimSpace(strings.Join(parts[i:], ","))
+		}
+	}
+	return line, ""
+}
+
+// isPlace reports whether the parts of a line after a comma name a place
+func isPlace(parts []string) bool {
+	for _, part := range parts {
+		words := strings.Fields(part)
+		if len(words) == 0 || len(words) > 4 {
+			return false
+		}
+		for _, word := range words {
+			if contains(organizationSuffixes, strings.ToLower(word)) {
+				return false
+			}
+			if r, _ := utf8.DecodeRuneInString(word); !unicode.IsUpper(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package pdf
+
+// Link is a URI link annotation
+type Link struct {
+	URI  string
+	Rect Rect // active area in default user space
+}
+
+// Links returns the page's link annotations that point at a URI. Links to
+// destinations inside the document are skipped.
+func (p *Page) Links() []Link {
+	var links []Link
+	for _, obj := range p.r.resolveArray(p.dict["Annots"]) {
+		annot := p.r.resolveDict(obj)
+		if annot == nil || toName(annot["Subtype"]) != "Link" {
+			continue
+		}
+		action := p.r.resolveDict(annot["A"])
+		if action == nil || toName(action["S"]) != "URI" {
+			continue
+		}
+		uri, ok := p.r.resolve(action["URI"]).(String)
+		if !ok || uri == "" {
+			continue
+		}
+
+		link := Link{URI: string(uri)}
+		if arr := p.r.resolveArray(annot["Rect"]); len(arr) == 4 {
+			var v [4]float64
+			for i := range arr {
+				v[i], _ = toFloat(p.r.resolve(arr[i]))
+			}
+			link.Rect = Rect{min(v[0], v[2]), min(v[1], v[3]), max(v[0], v[2]), max(v[1], v[3])}
+		}
+		links = append(links, link)
+	}
+	return links
+}
@@ -0,0 +1,243 @@
+package pdf
+
+import (
+	"unicode/utf16"
+)
+
+type codeRange struct {
+	lo, hi uint32
+	n      int // code length in bytes
+}
+
+type bfRange struct {
+	lo, hi uint32
+	n      int
+	dst    []byte   // UTF-16BE base value, incremented across the range
+	dsts   []string // explicit destinations
+}
+
+type cidRange struct {
+	lo, hi uint32
+	n      int
+	cid    int
+}
+
+// cmap holds the parts of a CMap needed for text extraction: codespace
+// ranges for splitting strings into codes, code-to-Unicode mappings and
+// code-to-CID mappings.
+type cmap struct {
+	space   []codeRange
+	chars   map[int]map[uint32]string // code length -> code -> text
+	ranges  []bfRange
+	cids    map[uint32]int
+	cidRngs []cidRange
+}
+
+func newCMap() *cmap {
+	return &cmap{
+		chars: make(map[int]map[uint32]string),
+		cids:  make(map[uint32]int),
+	}
+}
+
+func codeOf(s string) uint32 {
+	var v uint32
+	for i := 0; i < len(s); i++ {
+		v = v<<8 | uint32(s[i])
+	}
+	return v
+}
+
+func utf16BE(b []byte) string {
+	if len(b)%2 == 1 {
+		// Odd-length destinations are treated as single-byte text
+		return string(b)
+	}
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(u))
+}
+
+// parseCMap reads codespace, bfchar/bfrange and cidchar/cidrange sections
+func parseCMap(data []byte) *cmap {
+	cm := newCMap()
+	lex := newLexer(data, 0)
+	p := &parser{lex: lex}
+
+	var operands []Object
+	for {
+		tok, err := lex.next()
+		if err != nil || tok.kind == tokEOF {
+			break
+		}
+		if tok.kind != tokKeyword {
+			obj, err := p.objectFrom(tok)
+			if err != nil {
+				break
+			}
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch tok.text {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				lo, ok1 := operands[i].(String)
+				hi, ok2 := operands[i+1].(String)
+				if ok1 && ok2 && len(lo) > 0 {
+					cm.space = append(cm.space, codeRange{lo: codeOf(string(lo)), hi: codeOf(string(hi)), n: len(lo)})
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok := operands[i].(String)
+				if !ok {
+					continue
+				}
+				switch dst := operands[i+1].(type) {
+				case String:
+					cm.setChar(string(src), utf16BE([]byte(dst)))
+				case Name:
+					cm.setChar(string(src), glyphToUnicode(string(dst)))
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(String)
+				hi, ok2 := operands[i+1].(String)
+				if !ok1 || !ok2 {
+					continue
+				}
+				r := bfRange{lo: codeOf(string(lo)), hi: codeOf(string(hi)), n: len(lo)}
+				switch dst := operands[i+2].(type) {
+				case String:
+					r.dst = []byte(dst)
+				case Array:
+					for _, d := range dst {
+						s, _ := d.(String)
+						r.dsts = append(r.dsts, utf16BE([]byte(s)))
+					}
+				}
+				cm.ranges = append(cm.ranges, r)
+			}
+		case "endcidchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok := operands[i].(String)
+				cid, ok2 := toInt(operands[i+1])
+				if ok && ok2 {
+					cm.cids[codeOf(string(src))] = cid
+				}
+			}
+		case "endcidrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(String)
+				hi, ok2 := operands[i+1].(String)
+				cid, ok3 := toInt(operands[i+2])
+				if ok1 && ok2 && ok3 {
+					cm.cidRngs = append(cm.cidRngs, cidRange{lo: codeOf(string(lo)), hi: codeOf(string(hi)), n: len(lo), cid: cid})
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	return cm
+}
+
+func (cm *cmap) setChar(src, text string) {
+	m := cm.chars[len(src)]
+	if m == nil {
+		m = make(map[uint32]string)
+		cm.chars[len(src)] = m
+	}
+	m[codeOf(src)] = text
+}
+
+// split breaks a string into character codes using the codespace ranges,
+// defaulting to the given width when the CMap declares none.
+func (cm *cmap) split(s string, defaultWidth int) []charCode {
+	var codes []charCode
+	for i := 0; i < len(s); {
+		n := 0
+		if cm != nil {
+			for _, r := range cm.space {
+				if i+r.n > len(s) {
+					continue
+				}
+				c := codeOf(s[i : i+r.n])
+				if c >= r.lo && c <= r.hi {
+					n = r.n
+					break
+				}
+			}
+		}
+		if n == 0 {
+			n = defaultWidth
+			if i+n > len(s) {
+				n = len(s) - i
+			}
+		}
+		codes = append(codes, charCode{code: codeOf(s[i : i+n]), n: n})
+		i += n
+	}
+	return codes
+}
+
+// lookup returns the Unicode text for a code, if the CMap maps it
+func (cm *cmap) lookup(c charCode) (string, bool) {
+	if cm == nil {
+		return "", false
+	}
+	if m := cm.chars[c.n]; m != nil {
+		if s, ok := m[c.code]; ok {
+			return s, true
+		}
+	}
+	for _, r := range cm.ranges {
+		if r.n != c.n || c.code < r.lo || c.code > r.hi {
+			continue
+		}
+		off := int(c.code - r.lo)
+		if r.dsts != nil {
+			if off < len(r.dsts) {
+				return r.dsts[off], true
+			}
+			return "", false
+		}
+		if len(r.dst) == 0 {
+			return "", false
+		}
+		dst := append([]byte(nil), r.dst...)
+		// Increment the last byte, carrying into the previous ones
+		carry := off
+		for i := len(dst) - 1; i >= 0 && carry > 0; i-- {
+			v := int(dst[i]) + carry
+			dst[i] = byte(v)
+			carry = v >> 8
+		}
+		return utf16BE(dst), true
+	}
+	return "", false
+}
+
+// cid returns the CID selected by a code, or the code itself for
+// identity mappings.
+func (cm *cmap) cid(c charCode) int {
+	if cm != nil {
+		if v, ok := cm.cids[c.code]; ok {
+			return v
+		}
+		for _, r := range cm.cidRngs {
+			if r.n == c.n && c.code >= r.lo && c.code <= r.hi {
+				return r.cid + int(c.code-r.lo)
+			}
+		}
+	}
+	return int(c.code)
+}
+
+type charCode struct {
+	code uint32
+	n    int
+}
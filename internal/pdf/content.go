@@ -0,0 +1,275 @@
+package pdf
+
+import (
+	"bytes"
+)
+
+type gstate struct {
+	ctm       matrix
+	font      *Font
+	fontSize  float64
+	charSpace float64
+	wordSpace float64
+	hscale    float64
+	leading   float64
+	rise      float64
+}
+
+func defaultGState() gstate {
+	return gstate{ctm: identity, hscale: 1}
+}
+
+// interpreter executes the text-related operators of content streams and
+// records the images drawn
+type interpreter struct {
+	r      *Reader
+	gs     gstate
+	stack  []gstate
+	tm     matrix
+	tlm    matrix
+	out    []Text
+	images []Image
+}
+
+const maxFormDepth = 16
+
+func (in *interpreter) run(data []byte, resources Dict, depth int) {
+	lex := newLexer(data, 0)
+	p := &parser{lex: lex}
+	var operands []Object
+
+	for {
+		tok, err := lex.next()
+		if err != nil || tok.kind == tokEOF {
+			return
+		}
+		if tok.kind != tokKeyword {
+			obj, err := p.objectFrom(tok)
+			if err != nil {
+				return
+			}
+			operands = append(operands, obj)
+			continue
+		}
+
+		op := tok.text
+		if op == "BI" {
+			skipInlineImage(lex)
+			operands = operands[:0]
+			continue
+		}
+		in.exec(op, operands, resources, depth)
+		operands = operands[:0]
+	}
+}
+
+// skipInlineImage moves the lexer past "ID <data> EI"
+func skipInlineImage(lex *lexer) {
+	idx := bytes.Index(lex.data[lex.pos:], []byte("ID"))
+	if idx < 0 {
+		lex.pos = len(lex.data)
+		return
+	}
+	pos := lex.pos + idx + 3
+	for pos < len(lex.data) {
+		i := bytes.Index(lex.data[pos:], []byte("EI"))
+		if i < 0 {
+			lex.pos = len(lex.data)
+			return
+		}
+		end := pos + i
+		if end > 0 && isSpace(lex.data[end-1]) && (end+2 >= len(lex.data) || !isRegular(lex.data[end+2])) {
+			lex.pos = end + 2
+			return
+		}
+		pos = end + 2
+	}
+	lex.pos = len(lex.data)
+}
+
+func num(operands []Object, i int) float64 {
+	if i < 0 || i >= len(operands) {
+		return 0
+	}
+	f, _ := toFloat(operands[i])
+	return f
+}
+
+func (in *interpreter) exec(op string, args []Object, resources Dict, depth int) {
+	gs := &in.gs
+	switch op {
+	case "q":
+		in.stack = append(in.stack, in.gs)
+	case "Q":
+		if n := len(in.stack); n > 0 {
+			in.gs = in.stack[n-1]
+			in.stack = in.stack[:n-1]
+		}
+	case "cm":
+		if len(args) == 6 {
+			m, _ := toMatrix(Array(args))
+			gs.ctm = m.mul(gs.ctm)
+		}
+	case "BT":
+		in.tm = identity
+		in.tlm = identity
+	case "Tf":
+		if len(args) == 2 {
+			fonts := in.r.resolveDict(resources["Font"])
+			if name, ok := args[0].(Name); ok && fonts != nil && fonts[name] != nil {
+				gs.font = in.r.loadFont(fonts[name])
+			}
+			gs.fontSize = num(args, 1)
+		}
+	case "Tc":
+		gs.charSpace = num(args, 0)
+	case "Tw":
+		gs.wordSpace = num(args, 0)
+	case "Tz":
+		gs.hscale = num(args, 0) / 100
+	case "TL":
+		gs.leading = num(args, 0)
+	case "Ts":
+		gs.rise = num(args, 0)
+	case "Td":
+		in.moveLine(num(args, 0), num(args, 1))
+	case "TD":
+		gs.leading = -num(args, 1)
+		in.moveLine(num(args, 0), num(args, 1))
+	case "Tm":
+		if len(args) == 6 {
+			m, _ := toMatrix(Array(args))
+			in.tm = m
+			in.tlm = m
+		}
+	case "T*":
+		in.moveLine(0, -gs.leading)
+	case "Tj":
+		if len(args) == 1 {
+			in.show(args[0])
+		}
+	case "'":
+		in.moveLine(0, -gs.leading)
+		if len(args) == 1 {
+			in.show(args[0])
+		}
+	case "\"":
+		if len(args) == 3 {
+			gs.wordSpace = num(args, 0)
+			gs.charSpace = num(args, 1)
+			in.moveLine(0, -gs.leading)
+			in.show(args[2])
+		}
+	case "TJ":
+		if len(args) == 1 {
+			arr, _ := args[0].(Array)
+			for _, v := range arr {
+				if adj, ok := toFloat(v); ok {
+					tx := -adj / 1000 * gs.fontSize * gs.hscale
+					in.tm = translate(tx, 0).mul(in.tm)
+					continue
+				}
+				in.show(v)
+			}
+		}
+	case "Do":
+		if len(args) == 1 {
+			in.doXObject(args[0], resources, depth)
+		}
+	}
+}
+
+func (in *interpreter) moveLine(tx, ty float64) {
+	in.tlm = translate(tx, ty).mul(in.tlm)
+	in.tm = in.tlm
+}
+
+func (in *interpreter) show(obj Object) {
+	s, ok := obj.(String)
+	gs := &in.gs
+	if !ok || gs.font == nil {
+		return
+	}
+
+	trm := matrix{gs.fontSize * gs.hscale, 0, 0, gs.fontSize, 0, gs.rise}.mul(in.tm).mul(gs.ctm)
+	x0, y0 := trm[4], trm[5]
+	size := trm.scaleY()
+
+	var buf bytes.Buffer
+	for _, g := range gs.font.decode(string(s)) {
+		buf.WriteString(g.text)
+		tx := g.width*gs.fontSize + gs.charSpace
+		if g.space {
+			tx += gs.wordSpace
+		}
+		in.tm = translate(tx*gs.hscale, 0).mul(in.tm)
+	}
+
+	end := matrix{gs.fontSize * gs.hscale, 0, 0, gs.fontSize, 0, gs.rise}.mul(in.tm).mul(gs.ctm)
+	if buf.Len() == 0 {
+		return
+	}
+	in.out = append(in.out, Text{
+		S:        buf.String(),
+		X:        x0,
+		Y:        y0,
+		W:        end[4] - x0,
+		FontSize: size,
+		Font:     gs.font,
+	})
+}
+
+func (in *interpreter) doXObject(name Object, resources Dict, depth int) {
+	n, ok := name.(Name)
+	if !ok || depth >= maxFormDepth {
+		return
+	}
+	xobjects := in.r.resolveDict(resources["XObject"])
+	stm, ok := in.r.resolve(xobjects[n]).(Stream)
+	if !ok {
+		return
+	}
+	if toName(stm.Dict["Subtype"]) == "Image" {
+		in.drawImage(stm, resources)
+		return
+	}
+	if toName(stm.Dict["Subtype"]) != "Form" {
+		return
+	}
+	data, err := in.r.decodeStream(stm)
+	if err != nil {
+		return
+	}
+
+	formResources := in.r.resolveDict(stm.Dict["Resources"])
+	if formResources == nil {
+		formResources = resources
+	}
+
+	saved, savedStack := in.gs, len(in.stack)
+	savedTm, savedTlm := in.tm, in.tlm
+	if m, ok := toMatrix(in.r.resolve(stm.Dict["Matrix"])); ok {
+		in.gs.ctm = m.mul(in.gs.ctm)
+	}
+	in.run(data, formResources, depth+1)
+	in.gs, in.stack = saved, in.stack[:savedStack]
+	in.tm, in.tlm = savedTm, savedTlm
+}
+
+// drawImage records an image XObject with the page area it covers, the
+// unit square mapped through the current transformation matrix
+func (in *interpreter) drawImage(stm Stream, resources Dict) {
+	ctm := in.gs.ctm
+	x0, y0 := ctm.apply(0, 0)
+	rect := Rect{x0, y0, x0, y0}
+	for _, corner := range [][2]float64{{1, 0}, {0, 1}, {1, 1}} {
+		x, y := ctm.apply(corner[0], corner[1])
+		rect.X0, rect.Y0 = min(rect.X0, x), min(rect.Y0, y)
+		rect.X1, rect.Y1 = max(rect.X1, x), max(rect.Y1, y)
+	}
+	w, _ := toInt(in.r.resolve(stm.Dict["Width"]))
+	h, _ := toInt(in.r.resolve(stm.Dict["Height"]))
+	img := Image{Rect: rect, Width: w, Height: h, r: in.r, stm: stm}
+	img.components, img.palette = in.r.colorSpace(stm.Dict["ColorSpace"], resources)
+	in.images = append(in.images, img)
+}
@@ -0,0 +1,447 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// ErrPassword is returned when the document needs a password that was not
+// given, or the given password is neither the user nor the owner password
+var ErrPassword = errors.New("pdf: incorrect password")
+
+// passwordPad pads passwords for the RC4-based security handlers
+var passwordPad = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+type cryptMethod int
+
+const (
+	cryptNone cryptMethod = iota
+	cryptRC4
+	cryptAESV2
+	cryptAESV3
+)
+
+// decrypter holds the file key of a document using the standard security
+// handler, revisions 2 to 6
+type decrypter struct {
+	key             []byte
+	strMethod       cryptMethod
+	stmMethod       cryptMethod
+	encryptMetadata bool
+}
+
+// standardHandler holds the Encrypt dictionary values used to derive the
+// file key from a password
+type standardHandler struct {
+	rev             int
+	length          int // key length in bytes
+	o, u, oe, ue    []byte
+	p               uint32
+	id              []byte
+	encryptMetadata bool
+}
+
+// setupDecryption authenticates the password against the trailer's
+// Encrypt dictionary. The empty password is tried as the user password
+// first, so documents restricted only by an owner password open as usual.
+func (r *Reader) setupDecryption(password string) error {
+	encRef, _ := r.trailer["Encrypt"].(Ref)
+	enc := r.resolveDict(r.trailer["Encrypt"])
+	if enc == nil {
+		return fmt.Errorf("%w: missing Encrypt dictionary", ErrEncrypted)
+	}
+	var id []byte
+	if ids := r.resolveArray(r.trailer["ID"]); len(ids) > 0 {
+		s, _ := r.resolve(ids[0]).(String)
+		id = []byte(s)
+	}
+
+	d, err := r.newDecrypter(enc, id, password)
+	if errors.Is(err, ErrPassword) && password != "" {
+		// A password given for a document that doesn't need one
+		d, err = r.newDecrypter(enc, id, "")
+	}
+	if err != nil {
+		return err
+	}
+	r.crypt = d
+
+	// Objects read while locating the Encrypt dictionary were not decrypted
+	r.cache = make(map[int]Object)
+	r.objStms = make(map[int]*objStm)
+	if encRef.Num > 0 {
+		r.cryptRef = encRef
+		r.cache[encRef.Num] = enc
+	}
+	return nil
+}
+
+func (r *Reader) newDecrypter(enc Dict, id []byte, password string) (*decrypter, error) {
+	if filter := toName(r.resolve(enc["Filter"])); filter != "Standard" {
+		return nil, fmt.Errorf("%w: unsupported security handler %s", ErrEncrypted, filter)
+	}
+
+	v, _ := toInt(r.resolve(enc["V"]))
+	rev, _ := toInt(r.resolve(enc["R"]))
+	p, _ := toInt(r.resolve(enc["P"]))
+	str := func(key Name) []byte {
+		s, _ := r.resolve(enc[key]).(String)
+		return []byte(s)
+	}
+
+	h := &standardHandler{
+		rev:             rev,
+		length:          5,
+		o:               str("O"),
+		u:               str("U"),
+		oe:              str("OE"),
+		ue:              str("UE"),
+		p:               uint32(int32(p)),
+		id:              id,
+		encryptMetadata: true,
+	}
+	if b, ok := r.resolve(enc["EncryptMetadata"]).(bool); ok {
+		h.encryptMetadata = b
+	}
+
+	d := &decrypter{encryptMetadata: h.encryptMetadata}
+	switch v {
+	case 1, 2:
+		d.strMethod, d.stmMethod = cryptRC4, cryptRC4
+		if bits, ok := toInt(r.resolve(enc["Length"])); ok && v == 2 {
+			h.length = bits / 8
+		}
+	case 4, 5:
+		cf := r.resolveDict(enc["CF"])
+		d.strMethod = r.cryptFilter(cf, enc["StrF"])
+		d.stmMethod = r.cryptFilter(cf, enc["StmF"])
+		h.length = 16
+		if bits, ok := toInt(r.resolve(enc["Length"])); ok && v == 4 {
+			h.length = bits / 8
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported encryption version %d", ErrEncrypted, v)
+	}
+	if h.length < 5 || h.length > 16 {
+		h.length = 16
+	}
+
+	var key []byte
+	switch rev {
+	case 2, 3, 4:
+		if len(h.o) < 32 || len(h.u) < 32 {
+			return nil, fmt.Errorf("%w: malformed Encrypt dictionary", ErrEncrypted)
+		}
+		key = h.userKey(padPassword([]byte(password)))
+		if key == nil {
+			key = h.ownerKey([]byte(password))
+		}
+	case 5, 6:
+		if len(h.o) < 48 || len(h.u) < 48 || len(h.oe) < 32 || len(h.ue) < 32 {
+			return nil, fmt.Errorf("%w: malformed Encrypt dictionary", ErrEncrypted)
+		}
+		key = h.aesKey([]byte(password))
+	default:
+		return nil, fmt.Errorf("%w: unsupported security handler revision %d", ErrEncrypted, rev)
+	}
+	if key == nil {
+		return nil, ErrPassword
+	}
+	d.key = key
+	return d, nil
+}
+
+// cryptFilter maps a StrF/StmF crypt filter name to its method. Identity
+// and unknown names leave data as is.
+func (r *Reader) cryptFilter(cf Dict, name Object) cryptMethod {
+	n := toName(r.resolve(name))
+	if n == "" || n == "Identity" {
+		return cryptNone
+	}
+	filter := r.resolveDict(cf[n])
+	switch toName(r.resolve(filter["CFM"])) {
+	case "V2":
+		return cryptRC4
+	case "AESV2":
+		return cryptAESV2
+	case "AESV3":
+		return cryptAESV3
+	}
+	return cryptNone
+}
+
+func padPassword(pw []byte) []byte {
+	padded := make([]byte, 32)
+	n := copy(padded, pw)
+	copy(padded[n:], passwordPad)
+	return padded
+}
+
+// fileKey derives the RC4/AESV2 file key from a padded user password
+func (h *standardHandler) fileKey(padded []byte) []byte {
+	m := md5.New()
+	m.Write(padded)
+	m.Write(h.o[:32])
+	binary.Write(m, binary.LittleEndian, h.p)
+	m.Write(h.id)
+	if h.rev >= 4 && !h.encryptMetadata {
+		m.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF})
+	}
+	key := m.Sum(nil)
+
+	n := h.length
+	if h.rev == 2 {
+		n = 5
+	}
+	if h.rev >= 3 {
+		for i := 0; i < 50; i++ {
+			sum := md5.Sum(key[:n])
+			key = sum[:]
+		}
+	}
+	return key[:n]
+}
+
+// userKey returns the file key if padded is the user password
+func (h *standardHandler) userKey(padded []byte) []byte {
+	key := h.fileKey(padded)
+	if h.rev == 2 {
+		if bytes.Equal(rc4Crypt(key, passwordPad), h.u[:32]) {
+			return key
+		}
+		return nil
+	}
+
+	m := md5.New()
+	m.Write(passwordPad)
+	m.Write(h.id)
+	x := rc4Crypt(key, m.Sum(nil))
+	for i := 1; i <= 19; i++ {
+		x = rc4Crypt(xorKey(key, byte(i)), x)
+	}
+	if bytes.Equal(x[:16], h.u[:16]) {
+		return key
+	}
+	return nil
+}
+
+// ownerKey recovers the user password from O using the owner password and
+// returns the file key
+func (h *standardHandler) ownerKey(pw []byte) []byte {
+	sum := md5.Sum(padPassword(pw))
+	key := sum[:]
+	if h.rev >= 3 {
+		for i := 0; i < 50; i++ {
+			sum = md5.Sum(key)
+			key = sum[:]
+		}
+	}
+	n := h.length
+	if h.rev == 2 {
+		n = 5
+	}
+	key = key[:n]
+
+	user := h.o[:32]
+	if h.rev == 2 {
+		user = rc4Crypt(key, user)
+	} else {
+		for i := 19; i >= 0; i-- {
+			user = rc4Crypt(xorKey(key, byte(i)), user)
+		}
+	}
+	return h.userKey(user)
+}
+
+// aesKey authenticates a password for the AES-256 handlers, owner first,
+// and unwraps the file key from OE or UE
+func (h *standardHandler) aesKey(pw []byte) []byte {
+	if len(pw) > 127 {
+		pw = pw[:127]
+	}
+	u := h.u[:48]
+	if bytes.Equal(h.hash(pw, h.o[32:40], u), h.o[:32]) {
+		return aesUnwrap(h.hash(pw, h.o[40:48], u), h.oe[:32])
+	}
+	if bytes.Equal(h.hash(pw, h.u[32:40], nil), h.u[:32]) {
+		return aesUnwrap(h.hash(pw, h.u[40:48], nil), h.ue[:32])
+	}
+	return nil
+}
+
+// hash is SHA-256 for revision 5 and the iterated hash of revision 6
+func (h *standardHandler) hash(pw, salt, udata []byte) []byte {
+	sum := sha256.Sum256(concat(pw, salt, udata))
+	k := sum[:]
+	if h.rev == 5 {
+		return k
+	}
+
+	for i := 0; ; {
+		k1 := bytes.Repeat(concat(pw, k, udata), 64)
+		block, _ := aes.NewCipher(k[:16])
+		e := make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		var total int
+		for _, b := range e[:16] {
+			total += int(b)
+		}
+		var next hash.Hash
+		switch total % 3 {
+		case 0:
+			next = sha256.New()
+		case 1:
+			next = sha512.New384()
+		default:
+			next = sha512.New()
+		}
+		next.Write(e)
+		k = next.Sum(nil)
+
+		i++
+		if i >= 64 && int(e[len(e)-1]) <= i-32 {
+			break
+		}
+	}
+	return k[:32]
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func xorKey(key []byte, b byte) []byte {
+	out := make([]byte, len(key))
+	for i := range key {
+		out[i] = key[i] ^ b
+	}
+	return out
+}
+
+func rc4Crypt(key, data []byte) []byte {
+	c, err := rc4.NewCipher(key)
+	if err != nil {
+		return nil
+	}
+	out := make([]byte, len(data))
+	c.XORKeyStream(out, data)
+	return out
+}
+
+// aesUnwrap decrypts a 32-byte key with AES-256 in CBC mode and a zero IV
+func aesUnwrap(key, data []byte) []byte {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(out, data)
+	return out
+}
+
+// objectKey derives the key for one object's strings and streams
+func (d *decrypter) objectKey(ref Ref, m cryptMethod) []byte {
+	if m == cryptAESV3 {
+		return d.key
+	}
+	h := md5.New()
+	h.Write(d.key)
+	h.Write([]byte{byte(ref.Num), byte(ref.Num >> 8), byte(ref.Num >> 16), byte(ref.Gen), byte(ref.Gen >> 8)})
+	if m == cryptAESV2 {
+		h.Write([]byte("sAlT"))
+	}
+	return h.Sum(nil)[:min(len(d.key)+5, 16)]
+}
+
+func (d *decrypter) decrypt(ref Ref, m cryptMethod, data []byte) []byte {
+	switch m {
+	case cryptRC4:
+		return rc4Crypt(d.objectKey(ref, m), data)
+	case cryptAESV2, cryptAESV3:
+		return aesDecrypt(d.objectKey(ref, m), data)
+	}
+	return data
+}
+
+// aesDecrypt decrypts CBC data prefixed with its IV and strips the padding
+func aesDecrypt(key, data []byte) []byte {
+	block, err := aes.NewCipher(key)
+	if err != nil || len(data) < 2*aes.BlockSize {
+		return nil
+	}
+	body := data[aes.BlockSize:]
+	body = body[:len(body)-len(body)%aes.BlockSize]
+	out := make([]byte, len(body))
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(out, body)
+
+	if n := int(out[len(out)-1]); n >= 1 && n <= aes.BlockSize && n <= len(out) {
+		return out[:len(out)-n]
+	}
+	return out
+}
+
+// decryptObject decrypts the strings of an object read from the file.
+// Stream data is decrypted later, when the stream is decoded.
+func (d *decrypter) decryptObject(ref Ref, obj Object) Object {
+	switch v := obj.(type) {
+	case String:
+		return String(d.decrypt(ref, d.strMethod, []byte(v)))
+	case Array:
+		out := make(Array, len(v))
+		for i, item := range v {
+			out[i] = d.decryptObject(ref, item)
+		}
+		return out
+	case Dict:
+		out := make(Dict, len(v))
+		for k, item := range v {
+			out[k] = d.decryptObject(ref, item)
+		}
+		return out
+	case Stream:
+		v.Dict = d.decryptObject(ref, v.Dict).(Dict)
+		return v
+	}
+	return obj
+}
+
+// decryptStream decrypts stream data, honouring Crypt filters that
+// exempt a stream and unencrypted metadata
+func (r *Reader) decryptStream(stm Stream, filters []Name, params []Dict) ([]byte, []Name, []Dict) {
+	if len(filters) > 0 && filters[0] == "Crypt" {
+		// Named filters other than Identity use the default stream method
+		if name := toName(params[0]["Name"]); name == "" || name == "Identity" {
+			return stm.Raw, filters[1:], params[1:]
+		}
+		filters, params = filters[1:], params[1:]
+	}
+	if r.crypt == nil || stm.ref.Num == 0 || stm.ref == r.cryptRef {
+		return stm.Raw, filters, params
+	}
+	switch toName(stm.Dict["Type"]) {
+	case "XRef":
+		return stm.Raw, filters, params
+	case "Metadata":
+		if !r.crypt.encryptMetadata {
+			return stm.Raw, filters, params
+		}
+	}
+	return r.crypt.decrypt(stm.ref, r.crypt.stmMethod, stm.Raw), filters, params
+}
@@ -0,0 +1,145 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"resumeparser/internal/pdf/pdftest"
+	"testing"
+)
+
+const testFileID = "0123456789abcdef"
+
+// encryptedPDF builds a one-page document whose content stream (object 4)
+// and Info title (object 6) are encrypted with the given handler revision
+func encryptedPDF(rev int, user, owner string) []byte {
+	const content = "BT /F1 12 Tf 72 720 Td (Secret Resume) Tj ET"
+	var enc string
+	var d *decrypter
+	var encrypt func(ref Ref, data []byte) []byte
+
+	switch rev {
+	case 3:
+		// Algorithm 3: O is the padded user password encrypted with a key
+		// derived from the owner password
+		sum := md5.Sum(padPassword([]byte(owner)))
+		key := sum[:]
+		for i := 0; i < 50; i++ {
+			sum = md5.Sum(key)
+			key = sum[:]
+		}
+		o := rc4Crypt(key, padPassword([]byte(user)))
+		for i := 1; i <= 19; i++ {
+			o = rc4Crypt(xorKey(key, byte(i)), o)
+		}
+
+		h := &standardHandler{rev: 3, length: 16, o: o, p: uint32(0xFFFFF0C4), id: []byte(testFileID)}
+		fileKey := h.fileKey(padPassword([]byte(user)))
+		m := md5.Sum(append(append([]byte{}, passwordPad...), testFileID...))
+		u := rc4Crypt(fileKey, m[:])
+		for i := 1; i <= 19; i++ {
+			u = rc4Crypt(xorKey(fileKey, byte(i)), u)
+		}
+		u = append(u, make([]byte, 16)...)
+
+		d = &decrypter{key: fileKey, strMethod: cryptRC4, stmMethod: cryptRC4}
+		encrypt = func(ref Ref, data []byte) []byte { return d.decrypt(ref, cryptRC4, data) }
+		enc = fmt.Sprintf("<< /Filter /Standard /V 2 /R 3 /Length 128 /P -3900 /O <%x> /U <%x> >>", o, u)
+
+	case 6:
+		fileKey := bytes.Repeat([]byte{0x5A}, 32)
+		h := &standardHandler{rev: 6}
+		wrap := func(key []byte) []byte {
+			block, _ := aes.NewCipher(key)
+			out := make([]byte, 32)
+			cipher.NewCBCEncrypter(block, make([]byte, 16)).CryptBlocks(out, fileKey)
+			return out
+		}
+		u := append(h.hash([]byte(user), []byte("uvalsalt"), nil), "uvalsaltukeysalt"...)
+		ue := wrap(h.hash([]byte(user), []byte("ukeysalt"), nil))
+		o := append(h.hash([]byte(owner), []byte("ovalsalt"), u), "ovalsaltokeysalt"...)
+		oe := wrap(h.hash([]byte(owner), []byte("okeysalt"), u))
+
+		encrypt = func(ref Ref, data []byte) []byte {
+			n := aes.BlockSize - len(data)%aes.BlockSize
+			padded := append(append([]byte{}, data...), bytes.Repeat([]byte{byte(n)}, n)...)
+			iv := []byte("0123456789ABCDEF")
+			block, _ := aes.NewCipher(fileKey)
+			out := make([]byte, len(padded))
+			cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, padded)
+			return append(iv, out...)
+		}
+		enc = fmt.Sprintf("<< /Filter /Standard /V 5 /R 6 /Length 256 /P -3900 "+
+			"/CF << /StdCF << /CFM /AESV3 /AuthEvent /DocOpen /Length 32 >> >> /StmF /StdCF /StrF /StdCF "+
+			"/O <%x> /U <%x> /OE <%x> /UE <%x> >>", o, u, oe, ue)
+	}
+
+	stream := encrypt(Ref{Num: 4}, []byte(content))
+	return pdftest.BuildTrailer([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		pdftest.Stream(string(stream)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Title <%x> >>", encrypt(Ref{Num: 6}, []byte("Jane Smith CV"))),
+		enc,
+	}, fmt.Sprintf("/Encrypt 7 0 R /Info 6 0 R /ID [<%x> <%x>]", testFileID, testFileID))
+}
+
+func TestEncryption(t *testing.T) {
+	tests := []struct {
+		name     string
+		rev      int
+		user     string
+		owner    string
+		password string
+		wantErr  error
+	}{
+		{name: "rc4 owner password only", rev: 3, owner: "owner"},
+		{name: "rc4 user password", rev: 3, user: "user", owner: "owner", password: "user"},
+		{name: "rc4 owner password", rev: 3, user: "user", owner: "owner", password: "owner"},
+		{name: "rc4 missing password", rev: 3, user: "user", owner: "owner", wantErr: ErrPassword},
+		{name: "rc4 wrong password", rev: 3, user: "user", owner: "owner", password: "guess", wantErr: ErrPassword},
+		{name: "aes-256 owner password only", rev: 6, owner: "owner"},
+		{name: "aes-256 user password", rev: 6, user: "user", owner: "owner", password: "user"},
+		{name: "aes-256 owner password", rev: 6, user: "user", owner: "owner", password: "owner"},
+		{name: "aes-256 wrong password", rev: 6, user: "user", owner: "owner", password: "guess", wantErr: ErrPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := OpenWithPassword(encryptedPDF(tt.rev, tt.user, tt.owner), tt.password)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("OpenWithPassword() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenWithPassword() error = %v", err)
+			}
+
+			content, err := r.Page(1).Content()
+			if err != nil || len(content) != 1 || content[0].S != "Secret Resume" {
+				t.Errorf("Content() = %+v, %v", content, err)
+			}
+			info := r.resolveDict(r.Trailer()["Info"])
+			if title, _ := info["Title"].(String); title != "Jane Smith CV" {
+				t.Errorf("Info title = %q", title)
+			}
+		})
+	}
+}
+
+func TestUnsupportedEncryption(t *testing.T) {
+	data := pdftest.BuildTrailer([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [] /Count 0 >>",
+		"<< /Filter /Adobe.PubSec /V 4 /R 4 >>",
+	}, "/Encrypt 3 0 R")
+	if _, err := Open(data); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("Open() error = %v, want ErrEncrypted", err)
+	}
+}
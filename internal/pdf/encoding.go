@@ -0,0 +1,202 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Glyph names for ASCII codes 0x20-0x7E in the Adobe Glyph List
+var asciiGlyphNames = [...]string{
+	"space", "exclam", "quotedbl", "numbersign", "dollar", "percent", "ampersand", "quotesingle",
+	"parenleft", "parenright", "asterisk", "plus", "comma", "hyphen", "period", "slash",
+	"zero", "one", "two", "three", "four", "five", "six", "seven",
+	"eight", "nine", "colon", "semicolon", "less", "equal", "greater", "question",
+	"at", "A", "B", "C", "D", "E", "F", "G",
+	"H", "I", "J", "K", "L", "M", "N", "O",
+	"P", "Q", "R", "S", "T", "U", "V", "W",
+	"X", "Y", "Z", "bracketleft", "backslash", "bracketright", "asciicircum", "underscore",
+	"grave", "a", "b", "c", "d", "e", "f", "g",
+	"h", "i", "j", "k", "l", "m", "n", "o",
+	"p", "q", "r", "s", "t", "u", "v", "w",
+	"x", "y", "z", "braceleft", "bar", "braceright", "asciitilde",
+}
+
+// Glyph names for Latin-1 codes 0xA0-0xFF
+var latin1GlyphNames = [...]string{
+	"nbspace", "exclamdown", "cent", "sterling", "currency", "yen", "brokenbar", "section",
+	"dieresis", "copyright", "ordfeminine", "guillemotleft", "logicalnot", "sfthyphen", "registered", "macron",
+	"degree", "plusminus", "twosuperior", "threesuperior", "acute", "mu", "paragraph", "periodcentered",
+	"cedilla", "onesuperior", "ordmasculine", "guillemotright", "onequarter", "onehalf", "threequarters", "questiondown",
+	"Agrave", "Aacute", "Acircumflex", "Atilde", "Adieresis", "Aring", "AE", "Ccedilla",
+	"Egrave", "Eacute", "Ecircumflex", "Edieresis", "Igrave", "Iacute", "Icircumflex", "Idieresis",
+	"Eth", "Ntilde", "Ograve", "Oacute", "Ocircumflex", "Otilde", "Odieresis", "multiply",
+	"Oslash", "Ugrave", "Uacute", "Ucircumflex", "Udieresis", "Yacute", "Thorn", "germandbls",
+	"agrave", "aacute", "acircumflex", "atilde", "adieresis", "aring", "ae", "ccedilla",
+	"egrave", "eacute", "ecircumflex", "edieresis", "igrave", "iacute", "icircumflex", "idieresis",
+	"eth", "ntilde", "ograve", "oacute", "ocircumflex", "otilde", "odieresis", "divide",
+	"oslash", "ugrave", "uacute", "ucircumflex", "udieresis", "yacute", "thorn", "ydieresis",
+}
+
+// Windows-1252 code points for 0x80-0x9F, zero where undefined
+var cp1252High = [32]rune{
+	0x20AC, 0, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017D, 0,
+	0, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0, 0x017E, 0x0178,
+}
+
+// Additional glyph names that commonly appear in Differences arrays
+var extraGlyphNames = map[string]rune{
+	"Euro": 0x20AC, "quotesinglbase": 0x201A, "florin": 0x0192, "quotedblbase": 0x201E,
+	"ellipsis": 0x2026, "dagger": 0x2020, "daggerdbl": 0x2021, "circumflex": 0x02C6,
+	"perthousand": 0x2030, "Scaron": 0x0160, "guilsinglleft": 0x2039, "OE": 0x0152,
+	"Zcaron": 0x017D, "quoteleft": 0x2018, "quoteright": 0x2019, "quotedblleft": 0x201C,
+	"quotedblright": 0x201D, "bullet": 0x2022, "endash": 0x2013, "emdash": 0x2014,
+	"tilde": 0x02DC, "trademark": 0x2122, "scaron": 0x0161, "guilsinglright": 0x203A,
+	"oe": 0x0153, "zcaron": 0x017E, "Ydieresis": 0x0178,
+	"fi": 0xFB01, "fl": 0xFB02, "ff": 0xFB00, "ffi": 0xFB03, "ffl": 0xFB04,
+	"dotlessi": 0x0131, "Lslash": 0x0141, "lslash": 0x0142, "fraction": 0x2044,
+	"breve": 0x02D8, "dotaccent": 0x02D9, "ring": 0x02DA, "ogonek": 0x02DB,
+	"hungarumlaut": 0x02DD, "caron": 0x02C7, "minus": 0x2212, "space": ' ',
+	"hyphen": '-', "softhyphen": 0x00AD, "middot": 0x00B7, "notequal": 0x2260,
+	"infinity": 0x221E, "lessequal": 0x2264, "greaterequal": 0x2265, "partialdiff": 0x2202,
+	"summation": 0x2211, "product": 0x220F, "pi": 0x03C0, "integral": 0x222B,
+	"Omega": 0x2126, "radical": 0x221A, "approxequal": 0x2248, "Delta": 0x2206,
+	"lozenge": 0x25CA, "apple": 0xF8FF, "arrowright": 0x2192, "arrowleft": 0x2190,
+	"arrowup": 0x2191, "arrowdown": 0x2193, "checkmark": 0x2713, "filledbox": 0x25A0,
+	"H18533": 0x25CF, "H18543": 0x25AA, "H22073": 0x25A1, "circle": 0x25CB,
+	"blackcircle": 0x25CF, "whitebullet": 0x25E6, "periodcentered.loclCAT": 0x00B7,
+}
+
+var glyphNames map[string]rune
+
+func init() {
+	glyphNames = make(map[string]rune, 400)
+	for i, name := range asciiGlyphNames {
+		glyphNames[name] = rune(0x20 + i)
+	}
+	for i, name := range latin1GlyphNames {
+		glyphNames[name] = rune(0xA0 + i)
+	}
+	for name, r := range extraGlyphNames {
+		glyphNames[name] = r
+	}
+}
+
+// glyphToUnicode maps a glyph name to text using the Adobe Glyph List
+// conventions, including uniXXXX and uXXXX names.
+func glyphToUnicode(name string) string {
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		if r, ok := glyphNames[name]; ok {
+			return string(r)
+		}
+		name = name[:i]
+	}
+	if r, ok := glyphNames[name]; ok {
+		return string(r)
+	}
+
+	// Ligature names such as "f_f_i" join their components
+	if strings.Contains(name, "_") {
+		var b strings.Builder
+		for _, part := range strings.Split(name, "_") {
+			b.WriteString(glyphToUnicode(part))
+		}
+		return b.String()
+	}
+
+	if strings.HasPrefix(name, "uni") && len(name) >= 7 && (len(name)-3)%4 == 0 {
+		var b strings.Builder
+		for i := 3; i+4 <= len(name); i += 4 {
+			v, err := strconv.ParseUint(name[i:i+4], 16, 32)
+			if err != nil {
+				return ""
+			}
+			b.WriteRune(rune(v))
+		}
+		return b.String()
+	}
+	if strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7 {
+		if v, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+			return string(rune(v))
+		}
+	}
+	return ""
+}
+
+// encoding maps single-byte character codes to text
+type encoding [256]string
+
+func newWinAnsiEncoding() *encoding {
+	var e encoding
+	for c := 0x20; c < 0x7F; c++ {
+		e[c] = string(rune(c))
+	}
+	for i, r := range cp1252High {
+		if r != 0 {
+			e[0x80+i] = string(r)
+		}
+	}
+	for c := 0xA0; c <= 0xFF; c++ {
+		e[c] = string(rune(c))
+	}
+	// Fonts commonly draw the bullet and space at otherwise unused codes
+	e[0x7F] = "•"
+	e[0xA0] = " "
+	e[0xAD] = "-"
+	return &e
+}
+
+func newStandardEncoding() *encoding {
+	var e encoding
+	for c := 0x20; c < 0x7F; c++ {
+		e[c] = string(rune(c))
+	}
+	e[0x27] = "’"
+	e[0x60] = "‘"
+	high := map[int]rune{
+		0xA1: '¡', 0xA2: '¢', 0xA3: '£', 0xA4: '⁄', 0xA5: '¥', 0xA6: 'ƒ', 0xA7: '§',
+		0xA8: '¤', 0xA9: '\'', 0xAA: '“', 0xAB: '«', 0xAC: '‹', 0xAD: '›', 0xAE: 'ﬁ',
+		0xAF: 'ﬂ', 0xB1: '–', 0xB2: '†', 0xB3: '‡', 0xB4: '·', 0xB6: '¶', 0xB7: '•',
+		0xB8: '‚', 0xB9: '„', 0xBA: '”', 0xBB: '»', 0xBC: '…', 0xBD: '‰', 0xBF: '¿',
+		0xC1: '`', 0xC2: '´', 0xC3: 'ˆ', 0xC4: '˜', 0xC5: '¯', 0xC6: '˘', 0xC7: '˙',
+		0xC8: '¨', 0xCA: '˚', 0xCB: '¸', 0xCD: '˝', 0xCE: '˛', 0xCF: 'ˇ', 0xD0: '—',
+		0xE1: 'Æ', 0xE3: 'ª', 0xE8: 'Ł', 0xE9: 'Ø', 0xEA: 'Œ', 0xEB: 'º', 0xF1: 'æ',
+		0xF5: 'ı', 0xF8: 'ł', 0xF9: 'ø', 0xFA: 'œ', 0xFB: 'ß',
+	}
+	for c, r := range high {
+		e[c] = string(r)
+	}
+	return &e
+}
+
+// macRomanHigh lists the Mac OS Roman characters for codes 0x80-0xFF
+const macRomanHigh = "ÄÅÇÉÑÖÜáàâäãåçéèêëíìîïñóòôöõúùûü" +
+	"†°¢£§•¶ß®©™´¨≠ÆØ∞±≤≥¥µ∂∑∏π∫ªºΩæø" +
+	"¿¡¬√ƒ≈∆«»… ÀÃÕŒœ–—“”‘’÷◊ÿŸ⁄€‹›ﬁﬂ" +
+	"‡·‚„‰ÂÊÁËÈÍÎÏÌÓÔÒÚÛÙıˆ˜¯˘˙˚¸˝˛ˇ"
+
+func newMacRomanEncoding() *encoding {
+	var e encoding
+	for c := 0x20; c < 0x7F; c++ {
+		e[c] = string(rune(c))
+	}
+	c := 0x80
+	for _, r := range macRomanHigh {
+		e[c] = string(r)
+		c++
+	}
+	return &e
+}
+
+func baseEncoding(name Name) *encoding {
+	switch name {
+	case "WinAnsiEncoding":
+		return newWinAnsiEncoding()
+	case "MacRomanEncoding", "MacExpertEncoding":
+		return newMacRomanEncoding()
+	case "StandardEncoding":
+		return newStandardEncoding()
+	}
+	return nil
+}
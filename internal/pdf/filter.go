@@ -0,0 +1,346 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"fmt"
+	"io"
+)
+
+// decodeStream applies the stream's filter chain to its raw data
+func (r *Reader) decodeStream(stm Stream) ([]byte, error) {
+	filters, params := r.streamFilters(stm)
+	data, filters, params := r.decryptStream(stm, filters, params)
+	for i, name := range filters {
+		var err error
+		data, err = applyFilter(name, data, params[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// streamFilters returns the stream's filter names with their parameters
+func (r *Reader) streamFilters(stm Stream) ([]Name, []Dict) {
+	var filters []Name
+	var params []Dict
+	switch f := r.resolve(stm.Dict["Filter"]).(type) {
+	case Name:
+		filters = []Name{f}
+		params = []Dict{r.resolveDict(stm.Dict["DecodeParms"])}
+	case Array:
+		parms := r.resolveArray(stm.Dict["DecodeParms"])
+		for i, v := range f {
+			filters = append(filters, toName(r.resolve(v)))
+			var d Dict
+			if i < len(parms) {
+				d = r.resolveDict(parms[i])
+			}
+			params = append(params, d)
+		}
+	}
+	return filters, params
+}
+
+// maxStreamSize bounds the decoded size of a single stream, so a small
+// compressed stream cannot exhaust memory
+const maxStreamSize = 256 << 20
+
+// errStreamTooLarge is returned for streams that decode to more than
+// maxStreamSize bytes
+var errStreamTooLarge = fmt.Errorf("pdf: decoded stream exceeds %d bytes", maxStreamSize)
+
+// errUnsupportedFilter marks image filters that are left for the caller
+type errUnsupportedFilter struct {
+	name Name
+}
+
+func (e errUnsupportedFilter) Error() string {
+	return fmt.Sprintf("pdf: unsupported filter %s", e.name)
+}
+
+func applyFilter(name Name, data []byte, params Dict) ([]byte, error) {
+	switch name {
+	case "FlateDecode", "Fl":
+		out, err := inflate(data)
+		if err != nil {
+			return nil, err
+		}
+		return applyPredictor(out, params)
+	case "LZWDecode", "LZW":
+		early := 1
+		if v, ok := toInt(params["EarlyChange"]); ok {
+			early = v
+		}
+		out, err := lzwDecode(data, early == 1)
+		if err != nil {
+			return nil, err
+		}
+		return applyPredictor(out, params)
+	case "ASCIIHexDecode", "AHx":
+		return asciiHexDecode(data), nil
+	case "ASCII85Decode", "A85":
+		return ascii85Decode(data)
+	case "RunLengthDecode", "RL":
+		return runLengthDecode(data), nil
+	}
+	return nil, errUnsupportedFilter{name: name}
+}
+
+// inflate decompresses zlib data, keeping whatever could be recovered
+// from truncated or corrupt streams.
+func inflate(data []byte) ([]byte, error) {
+	var rc io.ReadCloser
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		// Some producers omit the zlib header
+		rc = flate.NewReader(bytes.NewReader(data))
+	} else {
+		rc = zr
+	}
+	defer rc.Close()
+
+	out, err := io.ReadAll(io.LimitReader(rc, maxStreamSize+1))
+	if len(out) > maxStreamSize {
+		return nil, errStreamTooLarge
+	}
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("pdf: flate decode: %w", err)
+	}
+	return out, nil
+}
+
+// maxColors is the most color components a PDF color space can have
+const maxColors = 32
+
+func applyPredictor(data []byte, params Dict) ([]byte, error) {
+	predictor, _ := toInt(params["Predictor"])
+	if predictor <= 1 {
+		return data, nil
+	}
+
+	colors := 1
+	if v, ok := toInt(params["Colors"]); ok && v > 0 {
+		colors = v
+	}
+	bpc := 8
+	if v, ok := toInt(params["BitsPerComponent"]); ok && v > 0 {
+		bpc = v
+	}
+	columns := 1
+	if v, ok := toInt(params["Columns"]); ok && v > 0 {
+		columns = v
+	}
+	if len(data) == 0 {
+		return data, nil
+	}
+	// Rows longer than the data are malformed, and checking the factors
+	// first keeps the row length from overflowing
+	if colors > maxColors || bpc > 16 || columns > len(data)*8/(colors*bpc)+1 {
+		return nil, fmt.Errorf("pdf: predictor rows of %d columns exceed the stream data", columns)
+	}
+	bpp := (colors*bpc + 7) / 8
+	rowLen := (colors*bpc*columns + 7) / 8
+	if rowLen > len(data) {
+		return nil, fmt.Errorf("pdf: predictor rows of %d columns exceed the stream data", columns)
+	}
+
+	if predictor == 2 {
+		// TIFF predictor, only the common 8 bits per component case
+		if bpc != 8 {
+			return data, nil
+		}
+		out := append([]byte(nil), data...)
+		for row := 0; row+rowLen <= len(out); row += rowLen {
+			for i := bpp; i < rowLen; i++ {
+				out[row+i] += out[row+i-bpp]
+			}
+		}
+		return out, nil
+	}
+
+	// PNG predictors: every row starts with its own filter type byte
+	var out []byte
+	prev := make([]byte, rowLen)
+	for pos := 0; pos < len(data); pos += rowLen + 1 {
+		end := pos + 1 + rowLen
+		if end > len(data) {
+			break
+		}
+		typ := data[pos]
+		cur := append([]byte(nil), data[pos+1:end]...)
+		for i := range cur {
+			var left, up, upLeft byte
+			if i >= bpp {
+				left = cur[i-bpp]
+				upLeft = prev[i-bpp]
+			}
+			up = prev[i]
+			switch typ {
+			case 1:
+				cur[i] += left
+			case 2:
+				cur[i] += up
+			case 3:
+				cur[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				cur[i] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, cur...)
+		prev = cur
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func asciiHexDecode(data []byte) []byte {
+	var out []byte
+	var hi byte
+	odd := false
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		v, ok := unhex(c)
+		if !ok {
+			continue
+		}
+		if odd {
+			out = append(out, hi<<4|v)
+		} else {
+			hi = v
+		}
+		odd = !odd
+	}
+	if odd {
+		out = append(out, hi<<4)
+	}
+	return out
+}
+
+func ascii85Decode(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out, err := io.ReadAll(ascii85.NewDecoder(bytes.NewReader(data)))
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("pdf: ascii85 decode: %w", err)
+	}
+	return out, nil
+}
+
+func runLengthDecode(data []byte) []byte {
+	var out []byte
+	for i := 0; i < len(data); {
+		n := int(data[i])
+		i++
+		switch {
+		case n == 128:
+			return out
+		case n < 128:
+			end := min(i+n+1, len(data))
+			out = append(out, data[i:end]...)
+			i = end
+		default:
+			if i < len(data) {
+				out = append(out, bytes.Repeat(data[i:i+1], 257-n)...)
+			}
+			i++
+		}
+	}
+	return out
+}
+
+// lzwDecode implements the PDF variant of LZW, which differs from
+// compress/lzw in its optional early code width change.
+func lzwDecode(data []byte, earlyChange bool) ([]byte, error) {
+	const (
+		clearCode = 256
+		eodCode   = 257
+	)
+	var out []byte
+	var table [][]byte
+	reset := func() {
+		table = table[:0]
+		for i := 0; i < 256; i++ {
+			table = append(table, []byte{byte(i)})
+		}
+		table = append(table, nil, nil)
+	}
+	reset()
+
+	width := 9
+	var bitBuf uint32
+	bitCount := 0
+	var prev []byte
+	early := 0
+	if earlyChange {
+		early = 1
+	}
+
+	for _, b := range data {
+		bitBuf = bitBuf<<8 | uint32(b)
+		bitCount += 8
+		for bitCount >= width {
+			code := int(bitBuf>>(bitCount-width)) & (1<<width - 1)
+			bitCount -= width
+
+			switch {
+			case code == clearCode:
+				reset()
+				width = 9
+				prev = nil
+				continue
+			case code == eodCode:
+				return out, nil
+			}
+
+			var entry []byte
+			if code < len(table) && table[code] != nil {
+				entry = table[code]
+			} else if prev != nil {
+				entry = append(append([]byte(nil), prev...), prev[0])
+			} else {
+				continue
+			}
+			out = append(out, entry...)
+			if len(out) > maxStreamSize {
+				return nil, errStreamTooLarge
+			}
+
+			if prev != nil {
+				table = append(table, append(append([]byte(nil), prev...), entry[0]))
+			}
+			prev = entry
+
+			if len(table)+early >= 1<<width && width < 12 {
+				width++
+			}
+		}
+	}
+	return out, nil
+}
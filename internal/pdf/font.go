@@ -0,0 +1,257 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// Font decodes the strings shown with a PDF font into text and glyph widths
+type Font struct {
+	BaseFont string
+	Subtype  Name
+	Bold     bool
+	Italic   bool
+
+	enc          *encoding // single-byte fonts
+	toUnicode    *cmap
+	cidMap       *cmap // composite font encoding CMap
+	composite    bool
+	ucs2         bool // composite codes are UCS-2 values
+	widths       map[int]float64
+	defaultWidth float64
+	scale        float64 // glyph space to text space
+}
+
+// glyph is one decoded character code
+type glyph struct {
+	text  string
+	width float64 // horizontal displacement in unscaled text space
+	space bool    // single-byte code 32, which receives word spacing
+}
+
+func (r *Reader) loadFont(obj Object) *Font {
+	ref, isRef := obj.(Ref)
+	if isRef {
+		if f, ok := r.fonts[ref]; ok {
+			return f
+		}
+	}
+
+	dict := r.resolveDict(obj)
+	f := &Font{
+		BaseFont:     stripSubsetPrefix(string(toName(r.resolve(dict["BaseFont"])))),
+		Subtype:      toName(r.resolve(dict["Subtype"])),
+		widths:       make(map[int]float64),
+		defaultWidth: 500,
+		scale:        0.001,
+	}
+
+	if stm, ok := r.resolve(dict["ToUnicode"]).(Stream); ok {
+		if data, err := r.decodeStream(stm); err == nil {
+			f.toUnicode = parseCMap(data)
+		}
+	}
+
+	desc := r.resolveDict(dict["FontDescriptor"])
+	if f.Subtype == "Type0" {
+		desc = r.loadCompositeFont(f, dict)
+	} else {
+		r.loadSimpleFont(f, dict)
+	}
+	f.Bold, f.Italic = r.fontStyle(f.BaseFont, desc)
+
+	if isRef {
+		r.fonts[ref] = f
+	}
+	return f
+}
+
+// stripSubsetPrefix removes the "ABCDEF+" tag from embedded subset fonts
+func stripSubsetPrefix(name string) string {
+	if len(name) > 7 && name[6] == '+' && strings.ToUpper(name[:6]) == name[:6] {
+		return name[7:]
+	}
+	return name
+}
+
+func (r *Reader) loadSimpleFont(f *Font, dict Dict) {
+	desc := r.resolveDict(dict["FontDescriptor"])
+	flags, _ := toInt(r.resolve(desc["Flags"]))
+	symbolic := flags&4 != 0
+
+	switch f.Subtype {
+	case "TrueType":
+		if !symbolic {
+			f.enc = newWinAnsiEncoding()
+		}
+	case "Type3":
+		if m, ok := toMatrix(r.resolve(dict["FontMatrix"])); ok {
+			f.scale = m[0]
+		}
+		f.enc = newStandardEncoding()
+	default:
+		f.enc = newStandardEncoding()
+	}
+
+	switch enc := r.resolve(dict["Encoding"]).(type) {
+	case Name:
+		if e := baseEncoding(enc); e != nil {
+			f.enc = e
+		}
+	case Dict:
+		if e := baseEncoding(toName(r.resolve(enc["BaseEncoding"]))); e != nil {
+			f.enc = e
+		}
+		if f.enc == nil {
+			f.enc = newStandardEncoding()
+		}
+		code := 0
+		for _, v := range r.resolveArray(enc["Differences"]) {
+			switch d := r.resolve(v).(type) {
+			case int64:
+				code = int(d)
+			case Name:
+				if code >= 0 && code < 256 {
+					f.enc[code] = glyphToUnicode(string(d))
+				}
+				code++
+			}
+		}
+	}
+
+	if mw, ok := toFloat(r.resolve(desc["MissingWidth"])); ok && mw > 0 {
+		f.defaultWidth = mw
+	}
+	first, _ := toInt(r.resolve(dict["FirstChar"]))
+	for i, w := range r.resolveArray(dict["Widths"]) {
+		if v, ok := toFloat(r.resolve(w)); ok {
+			f.widths[first+i] = v
+		}
+	}
+}
+
+// fontStyle derives bold and italic flags from the font descriptor and name
+func (r *Reader) fontStyle(name string, desc Dict) (bold, italic bool) {
+	lower := strings.ToLower(name)
+	for _, s := range []string{"bold", "black", "heavy", "semibold", "demi"} {
+		if strings.Contains(lower, s) {
+			bold = true
+		}
+	}
+	italic = strings.Contains(lower, "italic") || strings.Contains(lower, "oblique")
+
+	flags, _ := toInt(r.resolve(desc["Flags"]))
+	if flags&(1<<18) != 0 {
+		bold = true
+	}
+	if flags&(1<<6) != 0 {
+		italic = true
+	}
+	if weight, ok := toFloat(r.resolve(desc["FontWeight"])); ok && weight >= 600 {
+		bold = true
+	}
+	if angle, ok := toFloat(r.resolve(desc["ItalicAngle"])); ok && angle != 0 {
+		italic = true
+	}
+	return bold, italic
+}
+
+// loadCompositeFont reads a Type0 font and returns the descriptor of its
+// descendant CIDFont.
+func (r *Reader) loadCompositeFont(f *Font, dict Dict) Dict {
+	f.composite = true
+	f.defaultWidth = 1000
+
+	switch enc := r.resolve(dict["Encoding"]).(type) {
+	case Name:
+		f.ucs2 = strings.Contains(string(enc), "UCS2") || strings.Contains(string(enc), "UTF16")
+	case Stream:
+		if data, err := r.decodeStream(enc); err == nil {
+			f.cidMap = parseCMap(data)
+		}
+	}
+
+	descendants := r.resolveArray(dict["DescendantFonts"])
+	if len(descendants) == 0 {
+		return nil
+	}
+	cidFont := r.resolveDict(descendants[0])
+	if dw, ok := toFloat(r.resolve(cidFont["DW"])); ok {
+		f.defaultWidth = dw
+	}
+
+	// W entries are either "c [w1 w2 ...]" or "cFirst cLast w"
+	w := r.resolveArray(cidFont["W"])
+	for i := 0; i < len(w); {
+		start, ok := toInt(r.resolve(w[i]))
+		if !ok || i+1 >= len(w) {
+			break
+		}
+		if arr, ok := r.resolve(w[i+1]).(Array); ok {
+			for j, v := range arr {
+				if width, ok := toFloat(r.resolve(v)); ok {
+					f.widths[start+j] = width
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			break
+		}
+		end, _ := toInt(r.resolve(w[i+1]))
+		width, _ := toFloat(r.resolve(w[i+2]))
+		for c := start; c <= end && c-start < 65536; c++ {
+			f.widths[c] = width
+		}
+		i += 3
+	}
+	return r.resolveDict(cidFont["FontDescriptor"])
+}
+
+// decode splits a shown string into glyphs
+func (f *Font) decode(s string) []glyph {
+	width := 1
+	if f.composite {
+		width = 2
+	}
+
+	var space *cmap
+	switch {
+	case f.cidMap != nil && len(f.cidMap.space) > 0:
+		space = f.cidMap
+	case f.toUnicode != nil && len(f.toUnicode.space) > 0 && f.composite:
+		space = f.toUnicode
+	}
+
+	codes := space.split(s, width)
+	glyphs := make([]glyph, 0, len(codes))
+	for _, c := range codes {
+		g := glyph{space: c.n == 1 && c.code == 32}
+
+		if text, ok := f.toUnicode.lookup(c); ok {
+			g.text = text
+		} else if f.composite {
+			if f.ucs2 {
+				g.text = string(rune(c.code))
+			}
+		} else if f.enc != nil && f.enc[c.code&0xFF] != "" {
+			g.text = f.enc[c.code&0xFF]
+		} else if c.code >= 0x20 {
+			// Symbolic fonts without an encoding often use the private
+			// use area mapping of their character codes.
+			g.text = string(rune(c.code))
+		}
+
+		key := int(c.code)
+		if f.composite {
+			key = f.cidMap.cid(c)
+		}
+		w, ok := f.widths[key]
+		if !ok {
+			w = f.defaultWidth
+		}
+		g.width = w * f.scale
+		glyphs = append(glyphs, g)
+	}
+	return glyphs
+}
@@ -0,0 +1,235 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Image is an image XObject drawn on a page
+type Image struct {
+	Rect          Rect // area covered on the page in default user space
+	Width, Height int  // size in samples
+	r             *Reader
+	stm           Stream
+	components    int           // color components per sample, 0 if unknown
+	palette       []color.Color // lookup table of an Indexed color space
+}
+
+// Encode returns the image in a standard file format together with the
+// format name. JPEG ("jpeg") and JPEG 2000 ("jp2") data is returned as
+// embedded and CCITT fax data wrapped in a TIFF file ("tiff"); other
+// images are decoded and encoded as PNG ("png"). JBIG2 images are not
+// supported.
+func (img Image) Encode() ([]byte, string, error) {
+	filters, params := img.r.streamFilters(img.stm)
+	data, filters, params := img.r.decryptStream(img.stm, filters, params)
+	for i, name := range filters {
+		switch name {
+		case "DCTDecode", "DCT":
+			return data, "jpeg", nil
+		case "JPXDecode":
+			return data, "jp2", nil
+		case "CCITTFaxDecode", "CCF":
+			return ccittTIFF(data, params[i], img.Width, img.Height, img.inverted()), "tiff", nil
+		}
+		var err error
+		data, err = applyFilter(name, data, params[i])
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	m, err := img.decode(data)
+	if err != nil {
+		return nil, "", err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, m); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "png", nil
+}
+
+// maxImagePixels bounds the size of a decoded image
+const maxImagePixels = 1 << 26
+
+// decode converts unfiltered image samples to an image
+func (img Image) decode(data []byte) (image.Image, error) {
+	w, h := img.Width, img.Height
+	if w <= 0 || h <= 0 || w > maxImagePixels || h > maxImagePixels || int64(w)*int64(h) > maxImagePixels {
+		return nil, fmt.Errorf("pdf: invalid image size %dx%d", w, h)
+	}
+	d := img.stm.Dict
+	bpc, _ := toInt(img.r.resolve(d["BitsPerComponent"]))
+	n := img.components
+	if mask, _ := img.r.resolve(d["ImageMask"]).(bool); mask {
+		bpc, n = 1, 1
+	}
+	switch bpc {
+	case 1, 2, 4, 8, 16:
+	default:
+		bpc = 8
+	}
+	if n == 0 {
+		// Guess the color space from the amount of data
+		n = int(int64(len(data)) * 8 / (int64(w) * int64(h) * int64(bpc)))
+	}
+	if n != 1 && n != 3 && n != 4 {
+		return nil, fmt.Errorf("pdf: unsupported image with %d color components", n)
+	}
+
+	stride := int((int64(w)*int64(n)*int64(bpc) + 7) / 8)
+	if int64(len(data)) < int64(stride)*int64(h) {
+		return nil, fmt.Errorf("pdf: image data too short")
+	}
+	maxVal := uint32(1)<<bpc - 1
+	sample := func(row []byte, i int) uint32 {
+		switch bpc {
+		case 8:
+			return uint32(row[i])
+		case 16:
+			return uint32(row[2*i])<<8 | uint32(row[2*i+1])
+		}
+		bit := i * bpc
+		return uint32(row[bit/8]>>(8-bpc-bit%8)) & maxVal
+	}
+	invert := img.inverted()
+
+	bounds := image.Rect(0, 0, w, h)
+	if len(img.palette) > 0 && n == 1 {
+		m := image.NewPaletted(bounds, color.Palette(img.palette))
+		for y := 0; y < h; y++ {
+			row := data[y*stride:]
+			for x := 0; x < w; x++ {
+				m.Pix[y*m.Stride+x] = uint8(min(int(sample(row, x)), len(img.palette)-1))
+			}
+		}
+		return m, nil
+	}
+
+	var pix []uint8
+	var m image.Image
+	switch n {
+	case 1:
+		g := image.NewGray(bounds)
+		pix, m = g.Pix, g
+	case 3:
+		rgba := image.NewRGBA(bounds)
+		for i := 3; i < len(rgba.Pix); i += 4 {
+			rgba.Pix[i] = 0xFF
+		}
+		pix, m = rgba.Pix, rgba
+	case 4:
+		cmyk := image.NewCMYK(bounds)
+		pix, m = cmyk.Pix, cmyk
+	}
+	step := n
+	if n == 3 {
+		step = 4 // RGBA keeps an alpha byte per pixel
+	}
+	for y := 0; y < h; y++ {
+		row := data[y*stride:]
+		for x := 0; x < w; x++ {
+			for c := 0; c < n; c++ {
+				v := sample(row, x*n+c) * 255 / maxVal
+				if invert {
+					v = 255 - v
+				}
+				pix[(y*w+x)*step+c] = uint8(v)
+			}
+		}
+	}
+	return m, nil
+}
+
+// inverted reports whether the image's Decode array maps samples from
+// dark to light, as [1 0] does
+func (img Image) inverted() bool {
+	decode := img.r.resolveArray(img.stm.Dict["Decode"])
+	if len(decode) < 2 {
+		return false
+	}
+	lo, _ := toFloat(img.r.resolve(decode[0]))
+	hi, _ := toFloat(img.r.resolve(decode[1]))
+	return lo > hi
+}
+
+// colorSpace returns the number of components of an image color space
+// and, for Indexed spaces, the color lookup table. Names are looked up in
+// the resources' ColorSpace dictionary.
+func (r *Reader) colorSpace(obj Object, resources Dict) (int, []color.Color) {
+	obj = r.resolve(obj)
+	if name, ok := obj.(Name); ok {
+		switch name {
+		case "DeviceGray", "G", "CalGray":
+			return 1, nil
+		case "DeviceRGB", "RGB", "CalRGB":
+			return 3, nil
+		case "DeviceCMYK", "CMYK":
+			return 4, nil
+		}
+		named := r.resolveDict(resources["ColorSpace"])[name]
+		if named == nil {
+			return 0, nil
+		}
+		return r.colorSpace(named, nil)
+	}
+
+	arr, ok := obj.(Array)
+	if !ok || len(arr) == 0 {
+		return 0, nil
+	}
+	switch toName(r.resolve(arr[0])) {
+	case "CalGray", "Separation":
+		return 1, nil
+	case "CalRGB", "Lab":
+		return 3, nil
+	case "ICCBased":
+		if len(arr) > 1 {
+			if stm, ok := r.resolve(arr[1]).(Stream); ok {
+				n, _ := toInt(r.resolve(stm.Dict["N"]))
+				return n, nil
+			}
+		}
+	case "DeviceN":
+		if len(arr) > 1 {
+			return len(r.resolveArray(arr[1])), nil
+		}
+	case "Indexed", "I":
+		if len(arr) < 4 {
+			return 0, nil
+		}
+		base, _ := r.colorSpace(arr[1], resources)
+		if base != 1 && base != 3 && base != 4 {
+			return 0, nil
+		}
+		var lookup []byte
+		switch v := r.resolve(arr[3]).(type) {
+		case String:
+			lookup = []byte(v)
+		case Stream:
+			lookup, _ = r.decodeStream(v)
+		}
+		hival, _ := toInt(r.resolve(arr[2]))
+		var palette []color.Color
+		for i := 0; i <= hival && i < 256 && (i+1)*base <= len(lookup); i++ {
+			palette = append(palette, deviceColor(lookup[i*base:(i+1)*base]))
+		}
+		return 1, palette
+	}
+	return 0, nil
+}
+
+// deviceColor converts gray, RGB or CMYK components to a color
+func deviceColor(c []byte) color.Color {
+	switch len(c) {
+	case 3:
+		return color.RGBA{c[0], c[1], c[2], 0xFF}
+	case 4:
+		return color.CMYK{C: c[0], M: c[1], Y: c[2], K: c[3]}
+	}
+	return color.Gray{Y: c[0]}
+}
@@ -0,0 +1,126 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Info holds the document information dictionary entries
+type Info struct {
+	Title    string
+	Author   string
+	Subject  string
+	Creator  string // application that created the original document
+	Producer string // application that converted it to PDF
+	Created  time.Time
+	Modified time.Time
+}
+
+// Info returns the document information dictionary
+func (r *Reader) Info() Info {
+	d := r.resolveDict(r.trailer["Info"])
+	text := func(key Name) string {
+		s, _ := r.resolve(d[key]).(String)
+		return strings.TrimSpace(textString(s))
+	}
+	created, _ := parseDate(text("CreationDate"))
+	modified, _ := parseDate(text("ModDate"))
+	return Info{
+		Title:    text("Title"),
+		Author:   text("Author"),
+		Subject:  text("Subject"),
+		Creator:  text("Creator"),
+		Producer: text("Producer"),
+		Created:  created,
+		Modified: modified,
+	}
+}
+
+// Lang returns the natural language declared in the document catalog,
+// such as "en-US"
+func (r *Reader) Lang() string {
+	root := r.resolveDict(r.trailer["Root"])
+	s, _ := r.resolve(root["Lang"]).(String)
+	return strings.TrimSpace(textString(s))
+}
+
+// pdfDocEncoding lists the PDFDocEncoding characters that differ from Latin-1
+var pdfDocEncoding = map[byte]rune{
+	0x18: '˘', 0x19: 'ˇ', 0x1A: 'ˆ', 0x1B: '˙', 0x1C: '˝', 0x1D: '˛', 0x1E: '˚', 0x1F: '˜',
+	0x80: '•', 0x81: '†', 0x82: '‡', 0x83: '…', 0x84: '—', 0x85: '–', 0x86: 'ƒ', 0x87: '⁄',
+	0x88: '‹', 0x89: '›', 0x8A: '−', 0x8B: '‰', 0x8C: '„', 0x8D: '“', 0x8E: '”', 0x8F: '‘',
+	0x90: '’', 0x91: '‚', 0x92: '™', 0x93: 'ﬁ', 0x94: 'ﬂ', 0x95: 'Ł', 0x96: 'Œ', 0x97: 'Š',
+	0x98: 'Ÿ', 0x99: 'Ž', 0x9A: 'ı', 0x9B: 'ł', 0x9C: 'œ', 0x9D: 'š', 0x9E: 'ž', 0xA0: '€',
+}
+
+// textString decodes a PDF text string: UTF-16BE or UTF-8 with a byte
+// order mark, otherwise PDFDocEncoding
+func textString(s String) string {
+	switch {
+	case strings.HasPrefix(string(s), "\xFE\xFF"):
+		return utf16BE([]byte(s[2:]))
+	case strings.HasPrefix(string(s), "\xEF\xBB\xBF"):
+		return string(s[3:])
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if r, ok := pdfDocEncoding[s[i]]; ok {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(rune(s[i]))
+		}
+	}
+	return b.String()
+}
+
+// parseDate reads a PDF date such as "D:20240131120000+01'00'". Missing
+// trailing fields default to their minimum and a missing offset means UTC.
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "D:")
+	if len(s) < 4 {
+		return time.Time{}, false
+	}
+
+	fields := []int{0, 1, 1, 0, 0, 0} // year, month, day, hour, minute, second
+	widths := []int{4, 2, 2, 2, 2, 2}
+	pos := 0
+	for i, w := range widths {
+		if pos+w > len(s) || !isDigits(s[pos:pos+w]) {
+			break
+		}
+		fields[i], _ = strconv.Atoi(s[pos : pos+w])
+		pos += w
+	}
+	if pos < 4 {
+		return time.Time{}, false
+	}
+
+	loc := time.UTC
+	if rest := s[pos:]; rest != "" && (rest[0] == '+' || rest[0] == '-') {
+		parts := strings.FieldsFunc(rest[1:], func(r rune) bool { return r == '\'' })
+		var hours, minutes int
+		if len(parts) > 0 {
+			hours, _ = strconv.Atoi(parts[0])
+		}
+		if len(parts) > 1 {
+			minutes, _ = strconv.Atoi(parts[1])
+		}
+		offset := hours*3600 + minutes*60
+		if rest[0] == '-' {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
+	}
+
+	return time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], 0, loc), true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,325 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tokenKind identifies the lexical class of a token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokKeyword
+	tokInteger
+	tokReal
+	tokName
+	tokString
+	tokArrayStart
+	tokArrayEnd
+	tokDictStart
+	tokDictEnd
+	tokProcStart
+	tokProcEnd
+)
+
+type token struct {
+	kind tokenKind
+	text string // keyword, name or decoded string bytes
+	i    int64
+	f    float64
+	pos  int // offset of the token in the input
+}
+
+// lexer splits PDF syntax (file bodies, content streams and CMaps) into tokens
+type lexer struct {
+	data   []byte
+	pos    int
+	unread []token
+}
+
+func newLexer(data []byte, pos int) *lexer {
+	return &lexer{data: data, pos: pos}
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func isRegular(c byte) bool {
+	return !isSpace(c) && !isDelimiter(c)
+}
+
+// skipSpace advances past whitespace and comments
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isSpace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		break
+	}
+}
+
+func (l *lexer) unreadToken(t token) {
+	l.unread = append(l.unread, t)
+}
+
+func (l *lexer) next() (token, error) {
+	if n := len(l.unread); n > 0 {
+		t := l.unread[n-1]
+		l.unread = l.unread[:n-1]
+		return t, nil
+	}
+
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.data[l.pos]
+	switch c {
+	case '[':
+		l.pos++
+		return token{kind: tokArrayStart, pos: start}, nil
+	case ']':
+		l.pos++
+		return token{kind: tokArrayEnd, pos: start}, nil
+	case '{':
+		l.pos++
+		return token{kind: tokProcStart, pos: start}, nil
+	case '}':
+		l.pos++
+		return token{kind: tokProcEnd, pos: start}, nil
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return token{kind: tokDictStart, pos: start}, nil
+		}
+		s, err := l.readHexString()
+		return token{kind: tokString, text: s, pos: start}, err
+	case '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return token{kind: tokDictEnd, pos: start}, nil
+		}
+		l.pos++
+		return token{kind: tokKeyword, text: ">", pos: start}, nil
+	case '(':
+		s, err := l.readLiteralString()
+		return token{kind: tokString, text: s, pos: start}, err
+	case ')':
+		l.pos++
+		return token{kind: tokKeyword, text: ")", pos: start}, nil
+	case '/':
+		l.pos++
+		return token{kind: tokName, text: l.readName(), pos: start}, nil
+	}
+
+	// Numbers and keywords are runs of regular characters
+	for l.pos < len(l.data) && isRegular(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if isNumeric(word) {
+		if i, err := strconv.ParseInt(word, 10, 64); err == nil {
+			return token{kind: tokInteger, i: i, f: float64(i), pos: start}, nil
+		}
+		if f, ok := parseReal(word); ok {
+			return token{kind: tokReal, f: f, pos: start}, nil
+		}
+	}
+	return token{kind: tokKeyword, text: word, pos: start}, nil
+}
+
+func isNumeric(word string) bool {
+	if word == "" {
+		return false
+	}
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		if (c < '0' || c > '9') && c != '.' && c != '-' && c != '+' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseReal parses a PDF real number, tolerating malformed producers
+// that write values such as "--1" or "1.2.3".
+func parseReal(word string) (float64, bool) {
+	neg := false
+	for len(word) > 0 && (word[0] == '-' || word[0] == '+') {
+		if word[0] == '-' {
+			neg = !neg
+		}
+		word = word[1:]
+	}
+	if i := strings.IndexByte(word, '.'); i >= 0 {
+		if j := strings.IndexByte(word[i+1:], '.'); j >= 0 {
+			word = word[:i+1+j]
+		}
+	}
+	word = trimTrailingSigns(word)
+	if word == "" || word == "." {
+		return 0, true
+	}
+	f, err := strconv.ParseFloat(word, 64)
+	if err != nil {
+		return 0, false
+	}
+	if neg {
+		f = -f
+	}
+	return f, true
+}
+
+func trimTrailingSigns(s string) string {
+	for len(s) > 0 && (s[len(s)-1] == '-' || s[len(s)-1] == '+') {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func (l *lexer) readName() string {
+	var buf []byte
+	for l.pos < len(l.data) && isRegular(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				buf = append(buf, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		buf = append(buf, c)
+		l.pos++
+	}
+	return string(buf)
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+func (l *lexer) readHexString() (string, error) {
+	l.pos++ // skip '<'
+	var buf []byte
+	var hi byte
+	odd := false
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			if odd {
+				buf = append(buf, hi<<4)
+			}
+			return string(buf), nil
+		}
+		v, ok := unhex(c)
+		if !ok {
+			continue
+		}
+		if odd {
+			buf = append(buf, hi<<4|v)
+		} else {
+			hi = v
+		}
+		odd = !odd
+	}
+	return string(buf), fmt.Errorf("unterminated hex string")
+}
+
+func (l *lexer) readLiteralString() (string, error) {
+	l.pos++ // skip '('
+	var buf []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return string(buf), nil
+			}
+		case '\r':
+			// End-of-line markers inside strings are read as a single newline
+			if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+				l.pos++
+			}
+			c = '\n'
+		case '\\':
+			if l.pos >= len(l.data) {
+				continue
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				v := int(e - '0')
+				for i := 0; i < 2 && l.pos < len(l.data); i++ {
+					d := l.data[l.pos]
+					if d < '0' || d > '7' {
+						break
+					}
+					v = v*8 + int(d-'0')
+					l.pos++
+				}
+				c = byte(v)
+			default:
+				c = e
+			}
+		}
+		buf = append(buf, c)
+	}
+	return string(buf), fmt.Errorf("unterminated literal string")
+}
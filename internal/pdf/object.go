@@ -0,0 +1,187 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+)
+
+// Object is any PDF object: nil, bool, int64, float64, String, Name,
+// Array, Dict, Stream or Ref.
+type Object interface{}
+
+// Name is a PDF name object without the leading slash
+type Name string
+
+// String holds the raw bytes of a PDF string object
+type String string
+
+// Array is a PDF array object
+type Array []Object
+
+// Dict is a PDF dictionary object
+type Dict map[Name]Object
+
+// Ref is an indirect object reference
+type Ref struct {
+	Num int
+	Gen int
+}
+
+// Stream is a PDF stream object with its undecoded data
+type Stream struct {
+	Dict Dict
+	Raw  []byte
+	ref  Ref
+}
+
+// keyword is a bare operator or keyword such as "obj" or "Tj"
+type keyword string
+
+// parser reads objects from a lexer
+type parser struct {
+	lex *lexer
+}
+
+func (p *parser) readObject() (Object, error) {
+	tok, err := p.lex.next()
+	if err != nil {
+		return nil, err
+	}
+	return p.objectFrom(tok)
+}
+
+func (p *parser) objectFrom(tok token) (Object, error) {
+	switch tok.kind {
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of data")
+	case tokInteger:
+		// Look ahead for "gen R" to build an indirect reference
+		t2, err := p.lex.next()
+		if err != nil {
+			return nil, err
+		}
+		if t2.kind == tokInteger {
+			t3, err := p.lex.next()
+			if err != nil {
+				return nil, err
+			}
+			if t3.kind == tokKeyword && t3.text == "R" {
+				return Ref{Num: int(tok.i), Gen: int(t2.i)}, nil
+			}
+			p.lex.unreadToken(t3)
+		}
+		p.lex.unreadToken(t2)
+		return tok.i, nil
+	case tokReal:
+		return tok.f, nil
+	case tokName:
+		return Name(tok.text), nil
+	case tokString:
+		return String(tok.text), nil
+	case tokArrayStart:
+		var arr Array
+		for {
+			t, err := p.lex.next()
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == tokArrayEnd {
+				return arr, nil
+			}
+			if t.kind == tokEOF {
+				return arr, fmt.Errorf("unterminated array")
+			}
+			obj, err := p.objectFrom(t)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, obj)
+		}
+	case tokDictStart:
+		dict := make(Dict)
+		for {
+			t, err := p.lex.next()
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == tokDictEnd {
+				return dict, nil
+			}
+			if t.kind == tokEOF {
+				return dict, fmt.Errorf("unterminated dictionary")
+			}
+			if t.kind != tokName {
+				// Skip junk keys written by broken producers
+				continue
+			}
+			val, err := p.readObject()
+			if err != nil {
+				return nil, err
+			}
+			if v, ok := val.(keyword); ok && (v == "endobj" || v == "stream") {
+				p.lex.unreadToken(token{kind: tokKeyword, text: string(v)})
+				return dict, nil
+			}
+			dict[Name(t.text)] = val
+		}
+	case tokKeyword:
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return keyword(tok.text), nil
+	case tokProcStart, tokProcEnd, tokArrayEnd, tokDictEnd:
+		return keyword(""), nil
+	}
+	return nil, fmt.Errorf("unexpected token at offset %d", tok.pos)
+}
+
+// Helpers for reading typed values out of loosely typed objects
+
+func toFloat(obj Object) (float64, bool) {
+	switch v := obj.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, false
+		}
+		return v, true
+	}
+	return 0, false
+}
+
+func toInt(obj Object) (int, bool) {
+	switch v := obj.(type) {
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+func toName(obj Object) Name {
+	n, _ := obj.(Name)
+	return n
+}
+
+func toMatrix(obj Object) (matrix, bool) {
+	arr, ok := obj.(Array)
+	if !ok || len(arr) != 6 {
+		return matrix{}, false
+	}
+	var m matrix
+	for i, v := range arr {
+		f, ok := toFloat(v)
+		if !ok {
+			return matrix{}, false
+		}
+		m[i] = f
+	}
+	return m, true
+}
@@ -0,0 +1,156 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Rect is a rectangle in default user space
+type Rect struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// Page is a single page of the document
+type Page struct {
+	Number    int
+	MediaBox  Rect
+	Rotate    int
+	r         *Reader
+	dict      Dict
+	resources Dict
+}
+
+// Text is a run of characters drawn by one text-showing operation
+type Text struct {
+	S        string
+	X, Y     float64 // baseline origin in default user space
+	W        float64 // horizontal advance
+	FontSize float64
+	Font     *Font
+}
+
+type inherited struct {
+	resources Dict
+	mediaBox  Object
+	rotate    Object
+}
+
+func (r *Reader) loadPages() error {
+	root := r.resolveDict(r.trailer["Root"])
+	if root == nil {
+		return fmt.Errorf("pdf: missing document catalog")
+	}
+	seen := make(map[Ref]bool)
+	r.walkPages(root["Pages"], inherited{}, seen, 0)
+	return nil
+}
+
+func (r *Reader) walkPages(obj Object, inh inherited, seen map[Ref]bool, depth int) {
+	if ref, ok := obj.(Ref); ok {
+		if seen[ref] {
+			return
+		}
+		seen[ref] = true
+	}
+	if depth > 64 {
+		return
+	}
+	node := r.resolveDict(obj)
+	if node == nil {
+		return
+	}
+
+	if res := r.resolveDict(node["Resources"]); res != nil {
+		inh.resources = res
+	}
+	if mb := r.resolve(node["MediaBox"]); mb != nil {
+		inh.mediaBox = mb
+	}
+	if rot := r.resolve(node["Rotate"]); rot != nil {
+		inh.rotate = rot
+	}
+
+	kids, hasKids := r.resolve(node["Kids"]).(Array)
+	if toName(node["Type"]) == "Pages" || (hasKids && toName(node["Type"]) != "Page") {
+		for _, kid := range kids {
+			r.walkPages(kid, inh, seen, depth+1)
+		}
+		return
+	}
+
+	page := &Page{
+		Number:    len(r.pages) + 1,
+		MediaBox:  Rect{0, 0, 612, 792},
+		r:         r,
+		dict:      node,
+		resources: inh.resources,
+	}
+	if arr, ok := inh.mediaBox.(Array); ok && len(arr) == 4 {
+		var v [4]float64
+		for i := range arr {
+			v[i], _ = toFloat(r.resolve(arr[i]))
+		}
+		page.MediaBox = Rect{min(v[0], v[2]), min(v[1], v[3]), max(v[0], v[2]), max(v[1], v[3])}
+	}
+	if rot, ok := toInt(inh.rotate); ok {
+		page.Rotate = rot
+	}
+	r.pages = append(r.pages, page)
+}
+
+// contentData returns the concatenated, decoded content streams
+func (p *Page) contentData() ([]byte, error) {
+	var streams []Stream
+	switch c := p.r.resolve(p.dict["Contents"]).(type) {
+	case Stream:
+		streams = append(streams, c)
+	case Array:
+		for _, v := range c {
+			if s, ok := p.r.resolve(v).(Stream); ok {
+				streams = append(streams, s)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, s := range streams {
+		data, err := p.r.decodeStream(s)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// Content interprets the page's content streams and returns the text
+// drawn on it in content stream order.
+func (p *Page) Content() ([]Text, error) {
+	in, err := p.interpret()
+	if err != nil {
+		return nil, err
+	}
+	return in.out, nil
+}
+
+// Images returns the image XObjects drawn on the page, including those
+// drawn by forms, in content stream order. Inline images are skipped.
+func (p *Page) Images() ([]Image, error) {
+	in, err := p.interpret()
+	if err != nil {
+		return nil, err
+	}
+	return in.images, nil
+}
+
+func (p *Page) interpret() (*interpreter, error) {
+	data, err := p.contentData()
+	if err != nil {
+		return nil, err
+	}
+	in := &interpreter{r: p.r}
+	in.gs = defaultGState()
+	in.run(data, p.resources, 0)
+	return in, nil
+}
@@ -0,0 +1,294 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/png"
+	"resumeparser/internal/pdf/pdftest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func textOf(t *testing.T, data []byte) string {
+	t.Helper()
+	r, err := Open(data)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	var parts []string
+	for i := 1; i <= r.NumPage(); i++ {
+		content, err := r.Page(i).Content()
+		if err != nil {
+			t.Fatalf("Content() error = %v", err)
+		}
+		for _, text := range content {
+			parts = append(parts, text.S)
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+func TestSimpleFont(t *testing.T) {
+	data := pdftest.Build([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 612 792] >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		pdftest.FlateStream("BT /F1 12 Tf 72 720 Td (JOHN DOE) Tj 0 -14 Td [(Soft)-20(ware \\(Go\\))] TJ (\\223Lead\\224) ' ET"),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	})
+
+	got := textOf(t, data)
+	want := "JOHN DOE|Soft|ware (Go)|“Lead”"
+	if got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
+
+func TestCompositeFontToUnicode(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+2 beginbfchar
+<0001> <0048>
+<0002> <0069>
+endbfchar
+1 beginbfrange
+<0010> <0012> <0061>
+endbfrange
+endcmap
+end end`
+	data := pdftest.Build([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		pdftest.FlateStream("BT /F1 10 Tf 1 0 0 1 50 700 Tm <000100020010001100120002> Tj ET"),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /ABCDEF+Calibri /Encoding /Identity-H /DescendantFonts [6 0 R] /ToUnicode 7 0 R >>",
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /ABCDEF+Calibri /DW 500 /W [1 [600 250]] >>",
+		pdftest.Stream(cmap),
+	})
+
+	r, err := Open(data)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	content, err := r.Page(1).Content()
+	if err != nil || len(content) != 1 {
+		t.Fatalf("Content() = %v, %v", content, err)
+	}
+	text := content[0]
+	if text.S != "Hiabci" {
+		t.Errorf("text = %q, want %q", text.S, "Hiabci")
+	}
+	if text.Font.BaseFont != "Calibri" {
+		t.Errorf("font = %q, want subset prefix stripped", text.Font.BaseFont)
+	}
+	// 600 + 250 + 3 * 500 + 250 glyph units at 10pt
+	if want := 26.0; text.X != 50 || text.W < want-0.01 || text.W > want+0.01 {
+		t.Errorf("position = (%v, width %v), want (50, width %v)", text.X, text.W, want)
+	}
+}
+
+func TestBrokenXref(t *testing.T) {
+	data := pdftest.Build([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		pdftest.FlateStream("BT /F1 12 Tf 72 720 Td (Recovered) Tj ET"),
+		"<< /Type /Font /Subtype /TrueType /BaseFont /Arial >>",
+	})
+	// Corrupt the startxref offset so the table must be rebuilt
+	idx := bytes.LastIndex(data, []byte("startxref"))
+	data = append(data[:idx], []byte("startxref\n999999\n%%EOF\n")...)
+
+	if got := textOf(t, data); got != "Recovered" {
+		t.Errorf("text = %q, want %q", got, "Recovered")
+	}
+}
+
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		name   Name
+		input  string
+		params Dict
+		want   string
+	}{
+		{name: "ASCIIHexDecode", input: "48 65 6c6C 6f>", want: "Hello"},
+		{name: "ASCII85Decode", input: "<~87cURDZ~>", want: "Hello"},
+		{name: "RunLengthDecode", input: "\x01Hi\xfe!\x80", want: "Hi!!!"},
+		{name: "LZWDecode", input: "\x80\x0b\x60\x50\x22\x0c\x0c\x85\x01", want: "-----A---B"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.name), func(t *testing.T) {
+			got, err := applyFilter(tt.name, []byte(tt.input), tt.params)
+			if err != nil {
+				t.Fatalf("applyFilter() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("applyFilter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterLimits(t *testing.T) {
+	var z bytes.Buffer
+	w := zlib.NewWriter(&z)
+	w.Write([]byte("\x02\x01\x02\x02\x01\x01"))
+	w.Close()
+
+	got, err := applyFilter("FlateDecode", z.Bytes(), Dict{"Predictor": int64(12), "Columns": int64(2)})
+	if err != nil || string(got) != "\x01\x02\x02\x03" {
+		t.Errorf("applyFilter() = %q, %v, want PNG up predictor applied", got, err)
+	}
+
+	tests := []struct {
+		name   string
+		params Dict
+	}{
+		{name: "huge columns", params: Dict{"Predictor": int64(12), "Columns": int64(4398046511104)}},
+		{name: "huge colors", params: Dict{"Predictor": int64(12), "Colors": int64(1 << 40), "Columns": int64(1)}},
+		{name: "row longer than data", params: Dict{"Predictor": int64(12), "Columns": int64(100)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := applyFilter("FlateDecode", z.Bytes(), tt.params); err == nil {
+				t.Error("applyFilter() error = nil, want rejected parameters")
+			}
+		})
+	}
+}
+
+func TestImageLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		dict string
+	}{
+		{name: "huge size", dict: "/Width 4398046511104 /Height 4398046511104 /BitsPerComponent 8"},
+		{name: "overflowing area", dict: "/Width 67108864 /Height 67108864 /BitsPerComponent 16"},
+		{name: "short data", dict: "/Width 1000 /Height 1000 /BitsPerComponent 8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := pdftest.Build([]string{
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /XObject << /Im1 5 0 R >> >> >>",
+				"<< /Length 23 >>\nstream\nq 10 0 0 10 0 0 cm /Im1 Do Q\nendstream",
+				"<< /Type /XObject /Subtype /Image " + tt.dict + " /ColorSpace /DeviceGray /Length 4 >>\nstream\n\x00\x40\x80\xff\nendstream",
+			})
+			r, err := Open(data)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			images, err := r.Page(1).Images()
+			if err != nil || len(images) != 1 {
+				t.Fatalf("Images() = %v, %v", images, err)
+			}
+			if _, _, err := images[0].Encode(); err == nil {
+				t.Error("Encode() error = nil, want invalid image rejected")
+			}
+		})
+	}
+}
+
+func TestLinks(t *testing.T) {
+	data := pdftest.Build([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Annots [4 0 R 5 0 R 6 0 R] >>",
+		"<< /Type /Annot /Subtype /Link /Rect [120 700 72 712] /A << /S /URI /URI (https://github.com/jdoe) >> >>",
+		"<< /Type /Annot /Subtype /Link /Rect [0 0 10 10] /Dest [3 0 R /Fit] >>",
+		"<< /Type /Annot /Subtype /Text /Rect [0 0 10 10] /Contents (note) >>",
+	})
+	r, err := Open(data)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	links := r.Page(1).Links()
+	want := Link{URI: "https://github.com/jdoe", Rect: Rect{72, 700, 120, 712}}
+	if len(links) != 1 || links[0] != want {
+		t.Errorf("Links() = %+v, want [%+v]", links, want)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	data := pdftest.BuildTrailer([]string{
+		"<< /Type /Catalog /Pages 2 0 R /Lang (en-GB) >>",
+		"<< /Type /Pages /Kids [] /Count 0 >>",
+		"<< /Producer (Microsoft\\256 Word 2019) /Author <FEFF004A0061006E00650020004401130061006E> /CreationDate (D:20240131120000+01'00') /ModDate (D:2024) >>",
+	}, "/Info 3 0 R")
+	r, err := Open(data)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	info := r.Info()
+	if info.Producer != "Microsoft® Word 2019" || info.Author != "Jane Dēan" {
+		t.Errorf("Info() = %+v", info)
+	}
+	if got := info.Created.UTC().Format(time.RFC3339); got != "2024-01-31T11:00:00Z" {
+		t.Errorf("Created = %s", got)
+	}
+	if got := info.Modified.Format(time.RFC3339); got != "2024-01-01T00:00:00Z" {
+		t.Errorf("Modified = %s", got)
+	}
+	if lang := r.Lang(); lang != "en-GB" {
+		t.Errorf("Lang() = %q", lang)
+	}
+}
+
+func TestImages(t *testing.T) {
+	content := "q 200 0 0 100 50 600 cm /Im1 Do Q q 10 0 0 10 0 0 cm /Im2 Do Q"
+	data := pdftest.Build([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /XObject << /Im1 5 0 R /Im2 6 0 R >> >> >>",
+		pdftest.Stream(content),
+		"<< /Type /XObject /Subtype /Image /Width 2 /Height 2 /ColorSpace /DeviceGray /BitsPerComponent 8 /Decode [1 0] /Length 4 >>\nstream\n\x00\x40\x80\xff\nendstream",
+		"<< /Type /XObject /Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length 4 >>\nstream\n\xff\xd8\xff\xd9\nendstream",
+	})
+	r, err := Open(data)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	images, err := r.Page(1).Images()
+	if err != nil {
+		t.Fatalf("Images() error = %v", err)
+	}
+	if len(images) != 2 {
+		t.Fatalf("Images() returned %d images, want 2", len(images))
+	}
+
+	gray := images[0]
+	if want := (Rect{50, 600, 250, 700}); gray.Rect != want || gray.Width != 2 || gray.Height != 2 {
+		t.Errorf("image = %+v %dx%d, want %+v 2x2", gray.Rect, gray.Width, gray.Height, want)
+	}
+	encoded, format, err := gray.Encode()
+	if err != nil || format != "png" {
+		t.Fatalf("Encode() = %q, %v", format, err)
+	}
+	m, err := png.Decode(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	var got []uint32
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			v, _, _, _ := m.At(x, y).RGBA()
+			got = append(got, v>>8)
+		}
+	}
+	if want := []uint32{0xff, 0xbf, 0x7f, 0x00}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("pixels = %v, want %v", got, want)
+	}
+
+	encoded, format, err = images[1].Encode()
+	if err != nil || format != "jpeg" || string(encoded) != "\xff\xd8\xff\xd9" {
+		t.Errorf("Encode() = %q, %q, %v", encoded, format, err)
+	}
+}
@@ -0,0 +1,47 @@
+// Package pdftest builds small PDF files for tests.
+package pdftest
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+)
+
+// Build assembles a PDF file from object bodies, numbering them from 1 and
+// writing a valid cross-reference table. Object 1 is the catalog.
+func Build(objects []string) []byte {
+	return BuildTrailer(objects, "")
+}
+
+// BuildTrailer is Build with extra trailer entries
+func BuildTrailer(objects []string, trailer string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R %s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailer, xref)
+	return buf.Bytes()
+}
+
+// Stream returns a stream object holding content as it is
+func Stream(content string) string {
+	return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)
+}
+
+// FlateStream returns a stream object holding content compressed with
+// FlateDecode
+func FlateStream(content string) string {
+	var z bytes.Buffer
+	w := zlib.NewWriter(&z)
+	w.Write([]byte(content))
+	w.Close()
+	return fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.String())
+}
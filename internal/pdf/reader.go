@@ -0,0 +1,557 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ErrEncrypted is returned when a document uses an encryption scheme that
+// is not supported
+var ErrEncrypted = errors.New("pdf: document is encrypted")
+
+type xrefEntry struct {
+	offset     int64
+	gen        int
+	stream     int // object stream number for compressed entries
+	index      int // index within the object stream
+	compressed bool
+}
+
+// Reader provides access to the objects and pages of a PDF document
+type Reader struct {
+	data    []byte
+	xref    map[int]xrefEntry
+	trailer Dict
+	cache   map[int]Object
+	objStms map[int]*objStm
+	fonts   map[Ref]*Font
+	pages   []*Page
+	loading map[int]bool
+
+	crypt    *decrypter
+	cryptRef Ref // the Encrypt dictionary, which is stored unencrypted
+}
+
+// Open parses the cross-reference data of an in-memory PDF file
+func Open(data []byte) (*Reader, error) {
+	return OpenWithPassword(data, "")
+}
+
+// OpenWithPassword opens a PDF file that may be encrypted. The password
+// can be the user or the owner password; documents that only restrict
+// permissions open with an empty password.
+func OpenWithPassword(data []byte, password string) (*Reader, error) {
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")) {
+		return nil, fmt.Errorf("pdf: missing %%PDF header")
+	}
+
+	r := &Reader{
+		data:    data,
+		xref:    make(map[int]xrefEntry),
+		cache:   make(map[int]Object),
+		objStms: make(map[int]*objStm),
+		fonts:   make(map[Ref]*Font),
+		loading: make(map[int]bool),
+	}
+
+	if err := r.readXrefChain(); err != nil || r.trailer == nil || r.trailer["Root"] == nil {
+		// Damaged or missing cross-reference data: rebuild it by scanning the file
+		if err := r.reconstruct(); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.trailer["Encrypt"] != nil {
+		if err := r.setupDecryption(password); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.loadPages(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Trailer returns the document trailer dictionary
+func (r *Reader) Trailer() Dict {
+	return r.trailer
+}
+
+// NumPage returns the number of pages in the document
+func (r *Reader) NumPage() int {
+	return len(r.pages)
+}
+
+// Page returns the page with the given 1-based number
+func (r *Reader) Page(n int) *Page {
+	if n < 1 || n > len(r.pages) {
+		return nil
+	}
+	return r.pages[n-1]
+}
+
+func (r *Reader) readXrefChain() error {
+	idx := bytes.LastIndex(r.data, []byte("startxref"))
+	if idx < 0 {
+		return fmt.Errorf("pdf: missing startxref")
+	}
+	lex := newLexer(r.data, idx+len("startxref"))
+	tok, err := lex.next()
+	if err != nil || tok.kind != tokInteger {
+		return fmt.Errorf("pdf: malformed startxref")
+	}
+
+	offset := tok.i
+	seen := make(map[int64]bool)
+	for offset > 0 && !seen[offset] {
+		seen[offset] = true
+		if offset >= int64(len(r.data)) {
+			return fmt.Errorf("pdf: xref offset %d out of range", offset)
+		}
+
+		trailer, err := r.readXrefSection(int(offset))
+		if err != nil {
+			return err
+		}
+		if r.trailer == nil {
+			r.trailer = trailer
+		}
+
+		// Hybrid files keep extra entries in a cross-reference stream
+		if stm, ok := toInt(trailer["XRefStm"]); ok && !seen[int64(stm)] {
+			seen[int64(stm)] = true
+			if _, err := r.readXrefSection(stm); err != nil {
+				return err
+			}
+		}
+
+		prev, ok := toInt(trailer["Prev"])
+		if !ok {
+			break
+		}
+		offset = int64(prev)
+	}
+	return nil
+}
+
+// readXrefSection reads a classic xref table or a cross-reference stream
+// at offset and returns its trailer dictionary.
+func (r *Reader) readXrefSection(offset int) (Dict, error) {
+	lex := newLexer(r.data, offset)
+	tok, err := lex.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind == tokKeyword && tok.text == "xref" {
+		return r.readXrefTable(lex)
+	}
+	lex.unreadToken(tok)
+
+	obj, _, err := r.readIndirectAt(offset)
+	if err != nil {
+		return nil, err
+	}
+	stm, ok := obj.(Stream)
+	if !ok || toName(stm.Dict["Type"]) != "XRef" {
+		return nil, fmt.Errorf("pdf: no xref at offset %d", offset)
+	}
+	return stm.Dict, r.readXrefStream(stm)
+}
+
+func (r *Reader) readXrefTable(lex *lexer) (Dict, error) {
+	for {
+		tok, err := lex.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == tokKeyword && tok.text == "trailer" {
+			break
+		}
+		if tok.kind != tokInteger {
+			return nil, fmt.Errorf("pdf: malformed xref table")
+		}
+		countTok, err := lex.next()
+		if err != nil || countTok.kind != tokInteger {
+			return nil, fmt.Errorf("pdf: malformed xref subsection")
+		}
+
+		start := int(tok.i)
+		for i := 0; i < int(countTok.i); i++ {
+			offTok, _ := lex.next()
+			genTok, _ := lex.next()
+			typTok, _ := lex.next()
+			if offTok.kind != tokInteger || genTok.kind != tokInteger || typTok.kind != tokKeyword {
+				return nil, fmt.Errorf("pdf: malformed xref entry")
+			}
+			num := start + i
+			if _, exists := r.xref[num]; exists {
+				continue
+			}
+			if typTok.text == "n" {
+				r.xref[num] = xrefEntry{offset: offTok.i, gen: int(genTok.i)}
+			} else {
+				r.xref[num] = xrefEntry{offset: -1}
+			}
+		}
+	}
+
+	p := &parser{lex: lex}
+	obj, err := p.readObject()
+	if err != nil {
+		return nil, err
+	}
+	trailer, ok := obj.(Dict)
+	if !ok {
+		return nil, fmt.Errorf("pdf: malformed trailer")
+	}
+	return trailer, nil
+}
+
+func (r *Reader) readXrefStream(stm Stream) error {
+	data, err := r.decodeStream(stm)
+	if err != nil {
+		return err
+	}
+
+	w, _ := stm.Dict["W"].(Array)
+	if len(w) != 3 {
+		return fmt.Errorf("pdf: malformed xref stream widths")
+	}
+	var widths [3]int
+	rowLen := 0
+	for i := range widths {
+		widths[i], _ = toInt(w[i])
+		rowLen += widths[i]
+	}
+	if rowLen == 0 {
+		return fmt.Errorf("pdf: empty xref stream rows")
+	}
+
+	size, _ := toInt(stm.Dict["Size"])
+	index := []int{0, size}
+	if arr, ok := stm.Dict["Index"].(Array); ok && len(arr)%2 == 0 {
+		index = index[:0]
+		for _, v := range arr {
+			n, _ := toInt(v)
+			index = append(index, n)
+		}
+	}
+
+	field := func(row []byte, i int) int64 {
+		var v int64
+		start := 0
+		for j := 0; j < i; j++ {
+			start += widths[j]
+		}
+		for _, b := range row[start : start+widths[i]] {
+			v = v<<8 | int64(b)
+		}
+		return v
+	}
+
+	pos := 0
+	for k := 0; k+1 < len(index); k += 2 {
+		for i := 0; i < index[k+1]; i++ {
+			if pos+rowLen > len(data) {
+				return nil
+			}
+			row := data[pos : pos+rowLen]
+			pos += rowLen
+
+			num := index[k] + i
+			if _, exists := r.xref[num]; exists {
+				continue
+			}
+			typ := int64(1)
+			if widths[0] > 0 {
+				typ = field(row, 0)
+			}
+			switch typ {
+			case 0:
+				r.xref[num] = xrefEntry{offset: -1}
+			case 1:
+				r.xref[num] = xrefEntry{offset: field(row, 1), gen: int(field(row, 2))}
+			case 2:
+				r.xref[num] = xrefEntry{compressed: true, stream: int(field(row, 1)), index: int(field(row, 2))}
+			}
+		}
+	}
+	return nil
+}
+
+var objHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// reconstruct rebuilds the xref table by scanning for "N G obj" markers
+func (r *Reader) reconstruct() error {
+	r.xref = make(map[int]xrefEntry)
+	r.cache = make(map[int]Object)
+	for _, m := range objHeader.FindAllSubmatchIndex(r.data, -1) {
+		if m[0] > 0 && isRegular(r.data[m[0]-1]) {
+			continue
+		}
+		num, _ := strconv.Atoi(string(r.data[m[2]:m[3]]))
+		gen, _ := strconv.Atoi(string(r.data[m[4]:m[5]]))
+		// Later definitions win, matching incremental update semantics
+		r.xref[num] = xrefEntry{offset: int64(m[0]), gen: gen}
+	}
+	if len(r.xref) == 0 {
+		return fmt.Errorf("pdf: no objects found")
+	}
+
+	trailer := make(Dict)
+	if idx := bytes.LastIndex(r.data, []byte("trailer")); idx >= 0 {
+		p := &parser{lex: newLexer(r.data, idx+len("trailer"))}
+		if obj, err := p.readObject(); err == nil {
+			if d, ok := obj.(Dict); ok {
+				trailer = d
+			}
+		}
+	}
+
+	// Register objects stored inside object streams and find the catalog
+	var streams []int
+	for num := range r.xref {
+		streams = append(streams, num)
+	}
+	for _, num := range streams {
+		obj, err := r.resolveNum(num)
+		if err != nil {
+			continue
+		}
+		switch v := obj.(type) {
+		case Stream:
+			if toName(v.Dict["Type"]) == "ObjStm" {
+				if objs, err := r.loadObjStm(num); err == nil {
+					for i, n := range objs.nums {
+						if _, exists := r.xref[n]; !exists {
+							r.xref[n] = xrefEntry{compressed: true, stream: num, index: i}
+						}
+					}
+				}
+			}
+			if toName(v.Dict["Type"]) == "XRef" && trailer["Root"] == nil {
+				trailer["Root"] = v.Dict["Root"]
+				trailer["Info"] = v.Dict["Info"]
+				trailer["Encrypt"] = v.Dict["Encrypt"]
+				trailer["ID"] = v.Dict["ID"]
+			}
+		}
+	}
+
+	if trailer["Root"] == nil {
+		for num := range r.xref {
+			obj, err := r.resolveNum(num)
+			if err != nil {
+				continue
+			}
+			if d, ok := obj.(Dict); ok && toName(d["Type"]) == "Catalog" {
+				trailer["Root"] = Ref{Num: num}
+				break
+			}
+		}
+	}
+	if trailer["Root"] == nil {
+		return fmt.Errorf("pdf: document catalog not found")
+	}
+	for k, v := range trailer {
+		if v == nil {
+			delete(trailer, k)
+		}
+	}
+	r.trailer = trailer
+	return nil
+}
+
+// readIndirectAt parses "N G obj ... endobj" at offset
+func (r *Reader) readIndirectAt(offset int) (Object, Ref, error) {
+	lex := newLexer(r.data, offset)
+	numTok, _ := lex.next()
+	genTok, _ := lex.next()
+	objTok, _ := lex.next()
+	if numTok.kind != tokInteger || genTok.kind != tokInteger || objTok.kind != tokKeyword || objTok.text != "obj" {
+		return nil, Ref{}, fmt.Errorf("pdf: no object at offset %d", offset)
+	}
+	ref := Ref{Num: int(numTok.i), Gen: int(genTok.i)}
+
+	p := &parser{lex: lex}
+	obj, err := p.readObject()
+	if err != nil {
+		return nil, ref, err
+	}
+
+	dict, ok := obj.(Dict)
+	if !ok {
+		return obj, ref, nil
+	}
+	tok, err := lex.next()
+	if err != nil || tok.kind != tokKeyword || tok.text != "stream" {
+		return dict, ref, nil
+	}
+
+	// Stream data starts after the EOL following the "stream" keyword
+	start := lex.pos
+	if start < len(r.data) && r.data[start] == '\r' {
+		start++
+	}
+	if start < len(r.data) && r.data[start] == '\n' {
+		start++
+	}
+
+	length := -1
+	switch v := dict["Length"].(type) {
+	case int64:
+		length = int(v)
+	case Ref:
+		if r.loading[v.Num] {
+			break
+		}
+		if l, err := r.Resolve(v); err == nil {
+			if n, ok := toInt(l); ok {
+				length = n
+			}
+		}
+	}
+
+	end := start + length
+	if length < 0 || end > len(r.data) || !bytes.HasPrefix(bytes.TrimLeft(r.data[end:], "\r\n\t "), []byte("endstream")) {
+		// Fall back to searching for the endstream keyword
+		idx := bytes.Index(r.data[start:], []byte("endstream"))
+		if idx < 0 {
+			return nil, ref, fmt.Errorf("pdf: unterminated stream in object %d", ref.Num)
+		}
+		end = start + idx
+		for end > start && (r.data[end-1] == '\n' || r.data[end-1] == '\r') {
+			end--
+		}
+	}
+
+	return Stream{Dict: dict, Raw: r.data[start:end], ref: ref}, ref, nil
+}
+
+// Resolve follows indirect references until it reaches a direct object
+func (r *Reader) Resolve(obj Object) (Object, error) {
+	for i := 0; i < 32; i++ {
+		ref, ok := obj.(Ref)
+		if !ok {
+			return obj, nil
+		}
+		var err error
+		obj, err = r.resolveNum(ref.Num)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("pdf: reference chain too deep")
+}
+
+// resolve is Resolve for callers that treat broken references as null
+func (r *Reader) resolve(obj Object) Object {
+	v, _ := r.Resolve(obj)
+	return v
+}
+
+func (r *Reader) resolveDict(obj Object) Dict {
+	switch v := r.resolve(obj).(type) {
+	case Dict:
+		return v
+	case Stream:
+		return v.Dict
+	}
+	return nil
+}
+
+func (r *Reader) resolveArray(obj Object) Array {
+	a, _ := r.resolve(obj).(Array)
+	return a
+}
+
+func (r *Reader) resolveNum(num int) (Object, error) {
+	if obj, ok := r.cache[num]; ok {
+		return obj, nil
+	}
+	entry, ok := r.xref[num]
+	if !ok || entry.offset < 0 && !entry.compressed {
+		return nil, nil
+	}
+	if r.loading[num] {
+		return nil, fmt.Errorf("pdf: circular reference to object %d", num)
+	}
+	r.loading[num] = true
+	defer delete(r.loading, num)
+
+	var obj Object
+	var err error
+	if entry.compressed {
+		obj, err = r.readCompressed(entry.stream, entry.index)
+	} else {
+		if entry.offset >= int64(len(r.data)) {
+			return nil, fmt.Errorf("pdf: object %d offset out of range", num)
+		}
+		var ref Ref
+		obj, ref, err = r.readIndirectAt(int(entry.offset))
+		if err == nil && r.crypt != nil && ref != r.cryptRef {
+			obj = r.crypt.decryptObject(ref, obj)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.cache[num] = obj
+	return obj, nil
+}
+
+type objStm struct {
+	data []byte
+	nums []int
+	offs []int
+}
+
+func (r *Reader) loadObjStm(num int) (*objStm, error) {
+	if objs, ok := r.objStms[num]; ok {
+		return objs, nil
+	}
+	obj, err := r.resolveNum(num)
+	if err != nil {
+		return nil, err
+	}
+	stm, ok := obj.(Stream)
+	if !ok {
+		return nil, fmt.Errorf("pdf: object stream %d is not a stream", num)
+	}
+	data, err := r.decodeStream(stm)
+	if err != nil {
+		return nil, err
+	}
+
+	n, _ := toInt(stm.Dict["N"])
+	first, _ := toInt(stm.Dict["First"])
+	objs := &objStm{data: data}
+	lex := newLexer(data, 0)
+	for i := 0; i < n; i++ {
+		numTok, _ := lex.next()
+		offTok, _ := lex.next()
+		if numTok.kind != tokInteger || offTok.kind != tokInteger {
+			break
+		}
+		objs.nums = append(objs.nums, int(numTok.i))
+		objs.offs = append(objs.offs, first+int(offTok.i))
+	}
+	r.objStms[num] = objs
+	return objs, nil
+}
+
+func (r *Reader) readCompressed(stream, index int) (Object, error) {
+	objs, err := r.loadObjStm(stream)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(objs.offs) || objs.offs[index] >= len(objs.data) {
+		return nil, fmt.Errorf("pdf: bad index %d in object stream %d", index, stream)
+	}
+	p := &parser{lex: newLexer(objs.data, objs.offs[index])}
+	return p.readObject()
+}
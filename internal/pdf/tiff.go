@@ -0,0 +1,95 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// TIFF tags written by ccittTIFF
+const (
+	tiffImageWidth      = 256
+	tiffImageLength     = 257
+	tiffBitsPerSample   = 258
+	tiffCompression     = 259
+	tiffPhotometric     = 262
+	tiffStripOffsets    = 273
+	tiffSamplesPerPixel = 277
+	tiffRowsPerStrip    = 278
+	tiffStripByteCounts = 279
+	tiffT4Options       = 292
+	tiffT6Options       = 293
+)
+
+// ccittTIFF wraps CCITT fax data in a single strip TIFF file, which OCR
+// programs read without the image being decoded here. The parameters are
+// those of the CCITTFaxDecode filter; invert is set by a [1 0] Decode
+// array.
+func ccittTIFF(data []byte, params Dict, width, height int, invert bool) []byte {
+	k, _ := toInt(params["K"])
+	columns := width
+	if v, ok := toInt(params["Columns"]); ok && v > 0 {
+		columns = v
+	}
+	rows := height
+	if v, ok := toInt(params["Rows"]); ok && v > 0 {
+		rows = v
+	}
+	blackIs1, _ := params["BlackIs1"].(bool)
+	aligned, _ := params["EncodedByteAlign"].(bool)
+
+	// Without BlackIs1, 0 bits are black
+	photometric := uint32(1)
+	if blackIs1 != invert {
+		photometric = 0
+	}
+
+	type entry struct {
+		tag, typ uint16
+		value    uint32
+	}
+	const short, long = 3, 4
+	entries := []entry{
+		{tiffImageWidth, long, uint32(columns)},
+		{tiffImageLength, long, uint32(rows)},
+		{tiffBitsPerSample, short, 1},
+		{tiffCompression, short, 0},
+		{tiffPhotometric, short, photometric},
+		{tiffStripOffsets, long, 0},
+		{tiffSamplesPerPixel, short, 1},
+		{tiffRowsPerStrip, long, uint32(rows)},
+		{tiffStripByteCounts, long, uint32(len(data))},
+	}
+	if k < 0 {
+		// Group 4
+		entries[3].value = 4
+		entries = append(entries, entry{tiffT6Options, long, 0})
+	} else {
+		// Group 3, two-dimensional when K is positive
+		entries[3].value = 3
+		var options uint32
+		if k > 0 {
+			options |= 1
+		}
+		if aligned {
+			options |= 4
+		}
+		entries = append(entries, entry{tiffT4Options, long, options})
+	}
+	entries[5].value = uint32(8 + 2 + 12*len(entries) + 4)
+
+	var buf bytes.Buffer
+	buf.WriteString("II*\x00")
+	binary.Write(&buf, binary.LittleEndian, uint32(8))
+	binary.Write(&buf, binary.LittleEndian, uint16(len(entries)))
+	for _, e := range entries {
+		// Short values sit in the first two bytes of the value field,
+		// which little-endian order gives for free
+		binary.Write(&buf, binary.LittleEndian, e.tag)
+		binary.Write(&buf, binary.LittleEndian, e.typ)
+		binary.Write(&buf, binary.LittleEndian, uint32(1))
+		binary.Write(&buf, binary.LittleEndian, e.value)
+	}
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+	buf.Write(data)
+	return buf.Bytes()
+}